@@ -11,6 +11,8 @@ func main() {
 	application := app.New()
 	defer application.Close()
 
+	application.StartWorkers()
+
 	router := httptransport.NewRouter(application.Services)
 	if err := router.Run(":8080"); err != nil {
 		log.Fatalf("failed to start server:%v", err)
@@ -2,11 +2,19 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/Mutter0815/pr-reviewer-service/internal/codehost"
 	"github.com/Mutter0815/pr-reviewer-service/internal/config"
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/Mutter0815/pr-reviewer-service/internal/notify"
 	"github.com/Mutter0815/pr-reviewer-service/internal/repository/postgres"
 	"github.com/Mutter0815/pr-reviewer-service/internal/service"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -16,6 +24,14 @@ type App struct {
 	Cfg      *config.Config
 	Pool     *pgxpool.Pool
 	Services *service.Services
+
+	webhookDispatcher *service.WebhookDispatcher
+	outboxRelay       *service.OutboxRelay
+	reviewerSync      *service.ReviewerSyncWorker
+	digest            *service.DigestWorker
+	slaScheduler      *service.SLAScheduler
+	staleWorker       *service.StaleWorker
+	stopWorkers       context.CancelFunc
 }
 
 func New() *App {
@@ -28,32 +44,273 @@ func New() *App {
 	if err != nil {
 		log.Fatalf("failed to connect to postgres: %v", err)
 	}
-	sql, err := os.ReadFile("migrations/0001_init.up.sql")
-	if err != nil {
-		log.Fatalf("failed to read migration file: %v", err)
-	}
-
-	if _, err := pool.Exec(ctx, string(sql)); err != nil {
-		log.Fatalf("failed to apply migration: %v", err)
+	if err := applyMigrations(ctx, pool, "migrations"); err != nil {
+		log.Fatalf("failed to apply migrations: %v", err)
 	}
 
 	teamRepo := postgres.NewTeamRepo(pool)
 	userRepo := postgres.NewUserRepo(pool)
 	prRepo := postgres.NewPullRequestRepo(pool)
+	absenceRepo := postgres.NewAbsenceRepo(pool)
+	eventRepo := postgres.NewReviewerEventRepo(pool)
+	idempotencyRepo := postgres.NewIdempotencyRepo(pool)
+	auditRepo := postgres.NewAuditRepo(pool)
+	webhookRepo := postgres.NewWebhookRepo(pool)
+	outboxRepo := postgres.NewOutboxRepo(pool)
+	accountRepo := postgres.NewExternalAccountRepo(pool)
+	syncRepo := postgres.NewReviewerSyncRepo(pool)
+	notificationRepo := postgres.NewNotificationRepo(pool)
+	digestRepo := postgres.NewDigestRepo(pool)
+	slaRepo := postgres.NewSLARepo(pool)
+	staleRepo := postgres.NewStaleRepo(pool)
+	statsRepo := postgres.NewStatsRepo(pool)
+	txManager := postgres.NewTxManager(pool)
+
+	webhookSvc := service.NewWebhookService(webhookRepo, teamRepo)
+	publisher := service.NewOutboxPublisher(outboxRepo)
+
+	prSvc := service.NewPRService(prRepo, userRepo, teamRepo, eventRepo, syncRepo, publisher, txManager)
+	teamSvc := service.NewTeamService(teamRepo, userRepo, publisher, txManager, prSvc)
+	userSvc := service.NewUserService(userRepo, prRepo, absenceRepo, publisher, txManager, prSvc)
+
+	idempotencySvc := service.NewIdempotencyService(idempotencyRepo)
+	auditSvc := service.NewAuditService(auditRepo)
+
+	accountSvc := service.NewAccountService(accountRepo, userRepo)
+	githubSvc := service.NewGitHubService(prSvc, accountSvc, cfg.GitHubWebhookSecret)
+	gitlabSvc := service.NewGitLabService(prSvc, accountSvc, teamRepo, cfg.GitLabWebhookToken, cfg.GitLabProjectTeams)
+	notificationSvc := service.NewNotificationService(notificationRepo, userRepo, teamRepo)
+	statsSvc := service.NewStatsService(slaRepo, statsRepo, teamRepo)
+
+	services := service.NewServices(
+		teamSvc,
+		userSvc,
+		prSvc,
+		idempotencySvc,
+		auditSvc,
+		webhookSvc,
+		accountSvc,
+		githubSvc,
+		gitlabSvc,
+		notificationSvc,
+		statsSvc,
+	)
+
+	dispatcher := service.NewWebhookDispatcher(webhookRepo, nil, service.WebhookDispatcherConfig{
+		PollInterval:   cfg.WebhookPollInterval,
+		BatchSize:      cfg.WebhookBatchSize,
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		BackoffBase:    cfg.WebhookBackoffBase,
+		BackoffMax:     cfg.WebhookBackoffMax,
+		RequestTimeout: cfg.WebhookTimeout,
+	})
+
+	slack := notify.NewSlack(cfg.SlackWebhookURL, &http.Client{Timeout: cfg.SlackTimeout})
+	notificationSink := service.NewNotificationSink(notificationSvc, prRepo, userRepo, slack)
+
+	sinks, err := outboxSinks(cfg, webhookSvc, notificationSink)
+	if err != nil {
+		log.Fatalf("failed to configure outbox sinks: %v", err)
+	}
+	relay := service.NewOutboxRelay(outboxRepo, sinks, service.OutboxRelayConfig{
+		PollInterval: cfg.OutboxPollInterval,
+		BatchSize:    cfg.OutboxBatchSize,
+		BackoffBase:  cfg.OutboxBackoffBase,
+		BackoffMax:   cfg.OutboxBackoffMax,
+	})
+
+	reviewerSync := service.NewReviewerSyncWorker(syncRepo, prRepo, eventRepo, accountRepo, codeHostClients(cfg), service.ReviewerSyncConfig{
+		PollInterval:   cfg.ReviewerSyncPollInterval,
+		BatchSize:      cfg.ReviewerSyncBatchSize,
+		MaxAttempts:    cfg.ReviewerSyncMaxAttempts,
+		BackoffBase:    cfg.ReviewerSyncBackoffBase,
+		BackoffMax:     cfg.ReviewerSyncBackoffMax,
+		RequestTimeout: cfg.ReviewerSyncTimeout,
+	})
+
+	digest, err := digestWorker(cfg, digestRepo, prRepo)
+	if err != nil {
+		log.Fatalf("failed to configure email digest: %v", err)
+	}
 
-	teamSvc := service.NewTeamService(teamRepo, userRepo)
-	userSvc := service.NewUserService(userRepo, prRepo)
-	prSvc := service.NewPRService(prRepo, userRepo, teamRepo)
+	slaScheduler, err := newSLAScheduler(cfg, slaRepo, prSvc, publisher, txManager)
+	if err != nil {
+		log.Fatalf("failed to configure review sla: %v", err)
+	}
 
-	services := service.NewServices(teamSvc, userSvc, prSvc)
+	staleWorker := service.NewStaleWorker(staleRepo, prRepo, prSvc, postgres.NewAdvisoryLocker(pool), txManager, cfg.StaleCheckInterval)
 
 	return &App{
-		Cfg:      cfg,
-		Pool:     pool,
-		Services: services,
+		Cfg:               cfg,
+		Pool:              pool,
+		Services:          services,
+		webhookDispatcher: dispatcher,
+		outboxRelay:       relay,
+		reviewerSync:      reviewerSync,
+		digest:            digest,
+		slaScheduler:      slaScheduler,
+		staleWorker:       staleWorker,
+	}
+}
+
+func newSLAScheduler(
+	cfg *config.Config,
+	slaRepo *postgres.SLARepo,
+	prSvc *service.PRService,
+	publisher domain.EventPublisher,
+	txManager *postgres.TxManager,
+) (*service.SLAScheduler, error) {
+	start, err := parseClock(cfg.SLAWorkdayStart)
+	if err != nil {
+		return nil, fmt.Errorf("SLA_WORKDAY_START: %w", err)
 	}
+	end, err := parseClock(cfg.SLAWorkdayEnd)
+	if err != nil {
+		return nil, fmt.Errorf("SLA_WORKDAY_END: %w", err)
+	}
+	loc, err := time.LoadLocation(cfg.SLATimezone)
+	if err != nil {
+		return nil, fmt.Errorf("SLA_TIMEZONE: %w", err)
+	}
+
+	calendar := domain.WorkCalendar{Start: start, End: end, Location: loc}
+	if err := calendar.Validate(); err != nil {
+		return nil, err
+	}
+	return service.NewSLAScheduler(slaRepo, prSvc, publisher, txManager, service.SLAConfig{
+		Calendar:      calendar,
+		CheckInterval: cfg.SLACheckInterval,
+	}), nil
+}
+
+// parseClock разбирает время суток ЧЧ:ММ в смещение от полуночи.
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// digestWorker собирает ежедневный дайджест; nil, если SMTP не настроен.
+func digestWorker(
+	cfg *config.Config,
+	digestRepo *postgres.DigestRepo,
+	prRepo *postgres.PullRequestRepo,
+) (*service.DigestWorker, error) {
+	if cfg.SMTPHost == "" {
+		return nil, nil
+	}
+
+	sendAt, err := parseClock(cfg.DigestSendAt)
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_SEND_AT: %w", err)
+	}
+	loc, err := time.LoadLocation(cfg.DigestTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("DIGEST_TIMEZONE: %w", err)
+	}
+
+	mailer := notify.NewSMTP(notify.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+		Timeout:  cfg.SMTPTimeout,
+	})
+	return service.NewDigestWorker(digestRepo, prRepo, mailer, service.DigestConfig{
+		SendAt:        sendAt,
+		Location:      loc,
+		CheckInterval: cfg.DigestCheckInterval,
+	}), nil
+}
+
+// codeHostClients — клиенты хостов, для которых задан токен API.
+func codeHostClients(cfg *config.Config) service.CodeHostClients {
+	clients := service.CodeHostClients{}
+	if cfg.GitHubToken != "" {
+		clients[domain.CodeHostGitHub] = codehost.NewGitHubClient(cfg.GitHubAPIURL, cfg.GitHubToken, nil)
+	}
+	if cfg.GitLabAPIToken != "" {
+		clients[domain.CodeHostGitLab] = codehost.NewGitLabClient(cfg.GitLabURL, cfg.GitLabAPIToken, nil)
+	}
+	return clients
+}
+
+// outboxSinks собирает приёмники событий по OUTBOX_SINKS. Если задан SLACK_WEBHOOK_URL,
+// notify добавляется и без явного упоминания — иначе настроенный Slack молча бы не работал.
+func outboxSinks(
+	cfg *config.Config,
+	webhookSvc *service.WebhookService,
+	notificationSink *service.NotificationSink,
+) ([]service.EventSink, error) {
+	sinks := make([]service.EventSink, 0, len(cfg.OutboxSinks)+1)
+	hasNotify := false
+	for _, name := range cfg.OutboxSinks {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "log":
+			sinks = append(sinks, service.NewLogSink())
+		case "webhook":
+			sinks = append(sinks, service.NewWebhookSink(webhookSvc))
+		case "file":
+			sinks = append(sinks, service.NewFileSink(cfg.OutboxFilePath))
+		case "notify":
+			sinks = append(sinks, notificationSink)
+			hasNotify = true
+		case "nats":
+			sinks = append(sinks, service.NewNATSSink(cfg.NATSURL, cfg.NATSSubjectPrefix, cfg.NATSTimeout))
+		default:
+			return nil, fmt.Errorf("unknown sink %q", name)
+		}
+	}
+	if !hasNotify && cfg.SlackWebhookURL != "" {
+		sinks = append(sinks, notificationSink)
+	}
+	return sinks, nil
+}
+
+// StartWorkers запускает фоновые обработчики; они останавливаются в Close.
+func (a *App) StartWorkers() {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.stopWorkers = cancel
+
+	go a.webhookDispatcher.Run(ctx)
+	go a.outboxRelay.Run(ctx)
+	go a.reviewerSync.Run(ctx)
+	if a.digest != nil {
+		go a.digest.Run(ctx)
+	}
+	go a.slaScheduler.Run(ctx)
+	go a.staleWorker.Run(ctx)
+}
+
+// applyMigrations прогоняет все *.up.sql по порядку имён.
+// Миграции написаны идемпотентно, поэтому их можно применять при каждом старте.
+func applyMigrations(ctx context.Context, pool *pgxpool.Pool, dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+
+	for _, f := range files {
+		sql, err := os.ReadFile(f)
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f, err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			return fmt.Errorf("apply %s: %w", f, err)
+		}
+	}
+
+	return nil
 }
 
 func (a *App) Close() {
+	if a.stopWorkers != nil {
+		a.stopWorkers()
+	}
 	a.Pool.Close()
 }
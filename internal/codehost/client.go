@@ -0,0 +1,83 @@
+// Package codehost — клиенты API GitHub и GitLab для выгрузки ревьюверов в PR.
+package codehost
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// apiError — ответ API с кодом вне 2xx. Тело обрезается, чтобы не раздувать last_error.
+type apiError struct {
+	method string
+	path   string
+	status int
+	body   string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.method, e.path, e.status, e.body)
+}
+
+// doJSON отправляет запрос с JSON-телом (если in != nil) и разбирает ответ в out (если out != nil).
+func doJSON(ctx context.Context, client *http.Client, req *http.Request, in, out any) error {
+	if in != nil {
+		body, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &apiError{
+			method: req.Method,
+			path:   req.URL.Path,
+			status: resp.StatusCode,
+			body:   string(bytes.TrimSpace(body)),
+		}
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// plan сравнивает запрошенных ревьюверов have с want: add — кого из want нет в have,
+// remove — кто из replaced ещё запрошен и больше не нужен. Остальных из have не трогаем:
+// их запросили люди, а не сервис.
+func plan(have, want, replaced []string) (add, remove []string) {
+	haveSet := make(map[string]struct{}, len(have))
+	for _, h := range have {
+		haveSet[h] = struct{}{}
+	}
+	wantSet := make(map[string]struct{}, len(want))
+	for _, w := range want {
+		wantSet[w] = struct{}{}
+		if _, ok := haveSet[w]; !ok {
+			add = append(add, w)
+		}
+	}
+	for _, r := range replaced {
+		_, requested := haveSet[r]
+		_, wanted := wantSet[r]
+		if requested && !wanted {
+			remove = append(remove, r)
+		}
+	}
+	return add, remove
+}
@@ -0,0 +1,135 @@
+package codehost
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+func TestGitHubClient_SetReviewers(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/acme/api/pulls/7/requested_reviewers" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"users":[{"login":"Alice"},{"login":"carol"},{"login":"dave"}],"teams":[]}`))
+			return
+		}
+		var body gitHubReviewersBody
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		calls = append(calls, r.Method+" "+strings.Join(body.Reviewers, ","))
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient(srv.URL, "secret", srv.Client())
+	pr := domain.ExternalPR{Host: domain.CodeHostGitHub, Project: "acme/api", Number: 7}
+	// carol сервис заменил на bob; dave запросил человек — его не трогаем.
+	if err := client.SetReviewers(context.Background(), pr, []string{"alice", "bob"}, []string{"carol"}); err != nil {
+		t.Fatalf("SetReviewers error: %v", err)
+	}
+
+	sort.Strings(calls)
+	if strings.Join(calls, "; ") != "DELETE carol; POST bob" {
+		t.Fatalf("unexpected calls: %v", calls)
+	}
+}
+
+func TestGitHubClient_ReportsAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"message":"Reviews may only be requested from collaborators."}`))
+	}))
+	defer srv.Close()
+
+	client := NewGitHubClient(srv.URL, "secret", srv.Client())
+	err := client.SetReviewers(context.Background(), domain.ExternalPR{Project: "acme/api", Number: 1}, []string{"x"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "422") || !strings.Contains(err.Error(), "collaborators") {
+		t.Fatalf("expected API error with status and body, got %v", err)
+	}
+}
+
+func TestGitLabClient_SetReviewers(t *testing.T) {
+	var gotPath string
+	var gotIDs []int64
+	var lookups []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/users":
+			ids := map[string]int64{"alice": 11, "bob": 12}
+			username := r.URL.Query().Get("username")
+			lookups = append(lookups, username)
+			_ = json.NewEncoder(w).Encode([]gitLabUser{{ID: ids[username], Username: username}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v4/projects/acme/api/merge_requests/3":
+			_, _ = w.Write([]byte(`{"reviewers":[{"id":11,"username":"Alice"},{"id":13,"username":"carol"},{"id":14,"username":"dave"}]}`))
+		case r.Method == http.MethodPut:
+			gotPath = r.URL.EscapedPath()
+			var body struct {
+				ReviewerIDs []int64 `json:"reviewer_ids"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			gotIDs = body.ReviewerIDs
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewGitLabClient(srv.URL, "secret", srv.Client())
+	pr := domain.ExternalPR{Host: domain.CodeHostGitLab, Project: "acme/api", Number: 3}
+	// carol сервис заменил на bob; dave добавил человек — он должен остаться.
+	if err := client.SetReviewers(context.Background(), pr, []string{"alice", "bob"}, []string{"carol"}); err != nil {
+		t.Fatalf("SetReviewers error: %v", err)
+	}
+
+	if gotPath != "/api/v4/projects/acme%2Fapi/merge_requests/3" {
+		t.Fatalf("unexpected path %q", gotPath)
+	}
+	if fmt.Sprint(gotIDs) != "[11 14 12]" {
+		t.Fatalf("unexpected reviewer ids %v", gotIDs)
+	}
+	if fmt.Sprint(lookups) != "[bob]" {
+		t.Fatalf("expected to look up only the added reviewer, got %v", lookups)
+	}
+}
+
+func TestGitLabClient_SetReviewers_NothingToChange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"reviewers":[{"id":11,"username":"alice"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewGitLabClient(srv.URL, "secret", srv.Client())
+	pr := domain.ExternalPR{Host: domain.CodeHostGitLab, Project: "acme/api", Number: 3}
+	if err := client.SetReviewers(context.Background(), pr, []string{"alice"}, []string{"carol"}); err != nil {
+		t.Fatalf("SetReviewers error: %v", err)
+	}
+}
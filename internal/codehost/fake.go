@@ -0,0 +1,69 @@
+package codehost
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// Fake хранит запрошенных ревьюверов вместо вызова API; для тестов.
+// Первые FailTimes вызовов возвращают Err.
+type Fake struct {
+	mu        sync.Mutex
+	reviewers map[string][]string
+	calls     int
+
+	Err       error
+	FailTimes int
+}
+
+func NewFake() *Fake {
+	return &Fake{reviewers: make(map[string][]string)}
+}
+
+func (f *Fake) SetReviewers(ctx context.Context, pr domain.ExternalPR, logins, replaced []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls++
+	if f.Err != nil && f.calls <= f.FailTimes {
+		return f.Err
+	}
+
+	have := f.reviewers[pr.ID()]
+	add, remove := plan(have, logins, replaced)
+	removed := make(map[string]struct{}, len(remove))
+	for _, login := range remove {
+		removed[login] = struct{}{}
+	}
+	next := make([]string, 0, len(have)+len(add))
+	for _, login := range have {
+		if _, ok := removed[login]; !ok {
+			next = append(next, login)
+		}
+	}
+	f.reviewers[pr.ID()] = append(next, add...)
+	return nil
+}
+
+// Request имитирует ревьюверов, запрошенных на стороне хоста в обход сервиса.
+func (f *Fake) Request(pr domain.ExternalPR, logins ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reviewers[pr.ID()] = append(f.reviewers[pr.ID()], logins...)
+}
+
+// Reviewers — запрошенные ревьюверы PR и был ли PR вообще тронут.
+func (f *Fake) Reviewers(prID string) ([]string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	r, ok := f.reviewers[prID]
+	return r, ok
+}
+
+func (f *Fake) Calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls
+}
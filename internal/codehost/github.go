@@ -0,0 +1,82 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// GitHubClient выставляет ревьюверов через REST API pull request review requests.
+type GitHubClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitHubClient(baseURL, token string, client *http.Client) *GitHubClient {
+	if baseURL == "" {
+		baseURL = DefaultGitHubAPIURL
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GitHubClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    client,
+	}
+}
+
+type gitHubRequestedReviewers struct {
+	Users []struct {
+		Login string `json:"login"`
+	} `json:"users"`
+}
+
+type gitHubReviewersBody struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// SetReviewers запрашивает ревью у недостающих из logins и снимает запрос только с replaced.
+// GitHub убирает ревьювера из запрошенных, когда тот оставил ревью, поэтому такой ревьювер
+// будет запрошен повторно — это совпадает с тем, что PR снова ждёт его внимания.
+func (c *GitHubClient) SetReviewers(ctx context.Context, pr domain.ExternalPR, logins, replaced []string) error {
+	path := fmt.Sprintf("/repos/%s/pulls/%d/requested_reviewers", pr.Project, pr.Number)
+
+	var current gitHubRequestedReviewers
+	if err := doJSON(ctx, c.http, c.request(http.MethodGet, path), nil, &current); err != nil {
+		return err
+	}
+	have := make([]string, 0, len(current.Users))
+	for _, u := range current.Users {
+		have = append(have, domain.NormalizeLogin(u.Login))
+	}
+
+	add, remove := plan(have, logins, replaced)
+	if len(add) > 0 {
+		if err := doJSON(ctx, c.http, c.request(http.MethodPost, path), gitHubReviewersBody{Reviewers: add}, nil); err != nil {
+			return err
+		}
+	}
+	if len(remove) > 0 {
+		if err := doJSON(ctx, c.http, c.request(http.MethodDelete, path), gitHubReviewersBody{Reviewers: remove}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *GitHubClient) request(method, path string) *http.Request {
+	req, _ := http.NewRequest(method, c.baseURL+path, nil)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	return req
+}
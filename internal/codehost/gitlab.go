@@ -0,0 +1,105 @@
+package codehost
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// GitLabClient выставляет ревьюверов MR через REST API v4. baseURL — адрес инстанса
+// (https://gitlab.com или self-hosted), без /api/v4.
+type GitLabClient struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func NewGitLabClient(baseURL, token string, client *http.Client) *GitLabClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &GitLabClient{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http:    client,
+	}
+}
+
+type gitLabUser struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type gitLabMergeRequest struct {
+	Reviewers []gitLabUser `json:"reviewers"`
+}
+
+// SetReviewers добавляет недостающих из logins и снимает только replaced. GitLab принимает
+// список ревьюверов целиком в reviewer_ids, поэтому сначала читаем текущий и правим его.
+func (c *GitLabClient) SetReviewers(ctx context.Context, pr domain.ExternalPR, logins, replaced []string) error {
+	path := fmt.Sprintf("/api/v4/projects/%s/merge_requests/%d", url.PathEscape(pr.Project), pr.Number)
+
+	var current gitLabMergeRequest
+	if err := doJSON(ctx, c.http, c.request(http.MethodGet, path), nil, &current); err != nil {
+		return err
+	}
+	have := make([]string, 0, len(current.Reviewers))
+	for _, u := range current.Reviewers {
+		have = append(have, domain.NormalizeLogin(u.Username))
+	}
+
+	add, remove := plan(have, logins, replaced)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	removed := make(map[string]struct{}, len(remove))
+	for _, login := range remove {
+		removed[login] = struct{}{}
+	}
+	ids := make([]int64, 0, len(current.Reviewers)+len(add))
+	for i, u := range current.Reviewers {
+		if _, ok := removed[have[i]]; !ok {
+			ids = append(ids, u.ID)
+		}
+	}
+	for _, login := range add {
+		id, err := c.userID(ctx, login)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	body := struct {
+		ReviewerIDs []int64 `json:"reviewer_ids"`
+	}{ReviewerIDs: ids}
+	return doJSON(ctx, c.http, c.request(http.MethodPut, path), body, nil)
+}
+
+func (c *GitLabClient) userID(ctx context.Context, username string) (int64, error) {
+	req := c.request(http.MethodGet, "/api/v4/users")
+	req.URL.RawQuery = url.Values{"username": {username}}.Encode()
+
+	var users []gitLabUser
+	if err := doJSON(ctx, c.http, req, nil, &users); err != nil {
+		return 0, err
+	}
+	if len(users) == 0 {
+		return 0, fmt.Errorf("gitlab user %q not found", username)
+	}
+	return users[0].ID, nil
+}
+
+func (c *GitLabClient) request(method, path string) *http.Request {
+	req, _ := http.NewRequest(method, c.baseURL+path, nil)
+	// RawPath сохраняет %2F в пути проекта: GitLab ждёт group%2Fproject.
+	req.URL.RawPath = path
+	req.URL.Path, _ = url.PathUnescape(path)
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+	return req
+}
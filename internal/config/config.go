@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/caarlos0/env/v11"
 )
@@ -13,6 +14,74 @@ type Config struct {
 	DBUser     string `env:"DB_USER"     envDefault:"pr_user"`
 	DBPassword string `env:"DB_PASSWORD" envDefault:"pr_pass"`
 	DBName     string `env:"DB_NAME"     envDefault:"pr_db"`
+
+	WebhookPollInterval time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"1s"`
+	WebhookBatchSize    int           `env:"WEBHOOK_BATCH_SIZE"    envDefault:"50"`
+	WebhookMaxAttempts  int           `env:"WEBHOOK_MAX_ATTEMPTS"  envDefault:"8"`
+	WebhookBackoffBase  time.Duration `env:"WEBHOOK_BACKOFF_BASE"  envDefault:"5s"`
+	WebhookBackoffMax   time.Duration `env:"WEBHOOK_BACKOFF_MAX"   envDefault:"1h"`
+	WebhookTimeout      time.Duration `env:"WEBHOOK_TIMEOUT"       envDefault:"10s"`
+
+	// OutboxSinks — приёмники событий через запятую: log, webhook, nats, file, notify.
+	// notify включается и сам, если задан SLACK_WEBHOOK_URL.
+	OutboxSinks        []string      `env:"OUTBOX_SINKS"         envDefault:"log,webhook" envSeparator:","`
+	OutboxPollInterval time.Duration `env:"OUTBOX_POLL_INTERVAL" envDefault:"500ms"`
+	OutboxBatchSize    int           `env:"OUTBOX_BATCH_SIZE"    envDefault:"100"`
+	OutboxBackoffBase  time.Duration `env:"OUTBOX_BACKOFF_BASE"  envDefault:"1s"`
+	OutboxBackoffMax   time.Duration `env:"OUTBOX_BACKOFF_MAX"   envDefault:"5m"`
+	OutboxFilePath     string        `env:"OUTBOX_FILE_PATH"     envDefault:"events.jsonl"`
+
+	NATSURL           string        `env:"NATS_URL"            envDefault:"nats://localhost:4222"`
+	NATSSubjectPrefix string        `env:"NATS_SUBJECT_PREFIX" envDefault:"pr_reviewer"`
+	NATSTimeout       time.Duration `env:"NATS_TIMEOUT"        envDefault:"5s"`
+
+	// SlackWebhookURL — общий канал для уведомлений приёмника notify; у пользователя может быть свой webhook.
+	SlackWebhookURL string        `env:"SLACK_WEBHOOK_URL"`
+	SlackTimeout    time.Duration `env:"SLACK_TIMEOUT"     envDefault:"10s"`
+
+	// SMTPHost пустой — ежедневный дайджест ревью выключен.
+	SMTPHost     string        `env:"SMTP_HOST"`
+	SMTPPort     int           `env:"SMTP_PORT"     envDefault:"587"`
+	SMTPUsername string        `env:"SMTP_USERNAME"`
+	SMTPPassword string        `env:"SMTP_PASSWORD"`
+	SMTPFrom     string        `env:"SMTP_FROM"     envDefault:"pr-reviewer@localhost"`
+	SMTPTimeout  time.Duration `env:"SMTP_TIMEOUT"  envDefault:"10s"`
+
+	// DigestSendAt — время отправки дайджеста (ЧЧ:ММ) в часовом поясе DigestTimezone.
+	DigestSendAt        string        `env:"DIGEST_SEND_AT"        envDefault:"09:00"`
+	DigestTimezone      string        `env:"DIGEST_TIMEZONE"       envDefault:"UTC"`
+	DigestCheckInterval time.Duration `env:"DIGEST_CHECK_INTERVAL" envDefault:"1m"`
+
+	// Рабочий день для SLA команд с sla_working_hours: будни с SLA_WORKDAY_START до SLA_WORKDAY_END.
+	SLACheckInterval time.Duration `env:"SLA_CHECK_INTERVAL" envDefault:"1m"`
+	SLAWorkdayStart  string        `env:"SLA_WORKDAY_START"  envDefault:"09:00"`
+	SLAWorkdayEnd    string        `env:"SLA_WORKDAY_END"    envDefault:"18:00"`
+	SLATimezone      string        `env:"SLA_TIMEZONE"       envDefault:"UTC"`
+
+	// StaleCheckInterval — как часто применять политику устаревших PR; сроки задаются в настройках команд.
+	StaleCheckInterval time.Duration `env:"STALE_CHECK_INTERVAL" envDefault:"1h"`
+
+	// GitHubWebhookSecret — секрет вебхука в настройках репозитория; пока он пуст, входящие события отклоняются.
+	GitHubWebhookSecret string `env:"GITHUB_WEBHOOK_SECRET"`
+
+	// GitLabWebhookToken — Secret token из настроек вебхука GitLab; пока он пуст, входящие события отклоняются.
+	GitLabWebhookToken string `env:"GITLAB_WEBHOOK_TOKEN"`
+	// GitLabProjectTeams — какие проекты обрабатывать и какой команде они принадлежат:
+	// "group/project=team,group/other=team2". Ревьюверы MR назначаются из команды проекта.
+	GitLabProjectTeams map[string]string `env:"GITLAB_PROJECT_TEAMS" envKeyValSeparator:"="`
+
+	// Выгрузка ревьюверов обратно в GitHub/GitLab включается для хоста, когда задан его токен.
+	GitHubAPIURL   string `env:"GITHUB_API_URL"   envDefault:"https://api.github.com"`
+	GitHubToken    string `env:"GITHUB_TOKEN"`
+	GitLabURL      string `env:"GITLAB_URL"       envDefault:"https://gitlab.com"`
+	GitLabAPIToken string `env:"GITLAB_API_TOKEN"`
+
+	ReviewerSyncPollInterval time.Duration `env:"REVIEWER_SYNC_POLL_INTERVAL" envDefault:"2s"`
+	ReviewerSyncBatchSize    int           `env:"REVIEWER_SYNC_BATCH_SIZE"    envDefault:"20"`
+	ReviewerSyncMaxAttempts  int           `env:"REVIEWER_SYNC_MAX_ATTEMPTS"  envDefault:"10"`
+	ReviewerSyncBackoffBase  time.Duration `env:"REVIEWER_SYNC_BACKOFF_BASE"  envDefault:"10s"`
+	ReviewerSyncBackoffMax   time.Duration `env:"REVIEWER_SYNC_BACKOFF_MAX"   envDefault:"30m"`
+	ReviewerSyncTimeout      time.Duration `env:"REVIEWER_SYNC_TIMEOUT"       envDefault:"10s"`
 }
 
 func Load() *Config {
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Absence — период, когда пользователь недоступен для ревью (отпуск, больничный и т.п.).
+// Границы полуоткрытые: [StartsAt, EndsAt).
+type Absence struct {
+	ID       int64
+	UserID   string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Reason   string
+}
+
+func (a Absence) Validate() error {
+	if a.StartsAt.IsZero() || a.EndsAt.IsZero() {
+		return fmt.Errorf("%w: absence start and end are required", ErrInvalidInput)
+	}
+	if !a.EndsAt.After(a.StartsAt) {
+		return fmt.Errorf("%w: absence must end after it starts", ErrInvalidInput)
+	}
+	return nil
+}
+
+// Covers сообщает, приходится ли момент t на отсутствие.
+func (a Absence) Covers(t time.Time) bool {
+	return !t.Before(a.StartsAt) && t.Before(a.EndsAt)
+}
@@ -0,0 +1,16 @@
+package domain
+
+import "context"
+
+type actorKey struct{}
+
+// WithActor кладёт в контекст идентификатор того, кто выполняет запрос.
+func WithActor(ctx context.Context, actorID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+// ActorFromContext возвращает идентификатор из WithActor или пустую строку.
+func ActorFromContext(ctx context.Context) string {
+	actorID, _ := ctx.Value(actorKey{}).(string)
+	return actorID
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// Типы сущностей, к которым привязываются записи аудита.
+const (
+	AuditEntityPullRequest = "pull_request"
+	AuditEntityUser        = "user"
+	AuditEntityTeam        = "team"
+	AuditEntityAbsence     = "absence"
+	AuditEntityWebhook     = "webhook"
+)
+
+// AuditEntry — запись о выполненном изменяющем запросе.
+type AuditEntry struct {
+	ID          int64
+	ActorID     string
+	Method      string
+	Endpoint    string
+	EntityType  string
+	EntityID    string
+	PayloadHash string
+	StatusCode  int
+	// OutcomeCode — "OK" для успешных ответов, иначе код ошибки из тела ответа.
+	OutcomeCode string
+	CreatedAt   time.Time
+}
+
+// AuditFilter — условия выборки журнала; пустые поля не ограничивают выборку.
+type AuditFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
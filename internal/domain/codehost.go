@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CodeHost — внешняя система, из которой приходят PR.
+type CodeHost string
+
+const (
+	CodeHostGitHub CodeHost = "github"
+	CodeHostGitLab CodeHost = "gitlab"
+)
+
+func (h CodeHost) IsValid() bool {
+	switch h {
+	case CodeHostGitHub, CodeHostGitLab:
+		return true
+	}
+	return false
+}
+
+// ExternalPR — PR во внешней системе, из которого пришёл PR сервиса.
+// Project — owner/repo для GitHub и group/project для GitLab.
+type ExternalPR struct {
+	Host    CodeHost
+	Project string
+	Number  int
+}
+
+// externalPRSeparators — чем номер отделяется от проекта в id: так PR и MR пишутся в самих системах.
+var externalPRSeparators = map[CodeHost]string{
+	CodeHostGitHub: "#",
+	CodeHostGitLab: "!",
+}
+
+// ID — идентификатор PR в сервисе: "github:owner/repo#N" или "gitlab:group/project!N".
+func (p ExternalPR) ID() string {
+	return fmt.Sprintf("%s:%s%s%d", p.Host, p.Project, externalPRSeparators[p.Host], p.Number)
+}
+
+// ParseExternalPRID разбирает id, созданный ExternalPR.ID. Для PR, заведённых через API, ok = false.
+func ParseExternalPRID(id string) (ExternalPR, bool) {
+	host, rest, ok := strings.Cut(id, ":")
+	if !ok || !CodeHost(host).IsValid() {
+		return ExternalPR{}, false
+	}
+
+	sep := externalPRSeparators[CodeHost(host)]
+	i := strings.LastIndex(rest, sep)
+	if i <= 0 {
+		return ExternalPR{}, false
+	}
+	number, err := strconv.Atoi(rest[i+len(sep):])
+	if err != nil || number <= 0 {
+		return ExternalPR{}, false
+	}
+
+	return ExternalPR{Host: CodeHost(host), Project: rest[:i], Number: number}, true
+}
@@ -3,10 +3,19 @@ package domain
 import "errors"
 
 var (
-	ErrTeamExists  = errors.New("team already exists")
-	ErrPRExists    = errors.New("pr already exists")
-	ErrPRMerged    = errors.New("pr is merged")
-	ErrNotAssigned = errors.New("reviewer is not assigned to this PR")
-	ErrNoCandidate = errors.New("no active replacement candidate in team")
-	ErrNotFound    = errors.New("resource not found")
+	ErrTeamExists   = errors.New("team already exists")
+	ErrPRExists     = errors.New("pr already exists")
+	ErrPRMerged     = errors.New("pr is merged")
+	ErrNotAssigned  = errors.New("reviewer is not assigned to this PR")
+	ErrNoCandidate  = errors.New("no active replacement candidate in team")
+	ErrNotFound     = errors.New("resource not found")
+	ErrInvalidInput = errors.New("invalid input")
+
+	ErrNotEnoughReviewers = errors.New("not enough active reviewers in team")
+	ErrConflict           = errors.New("concurrent modification, retry the request")
+	ErrInvalidTransition  = errors.New("invalid pr status transition")
+	ErrPRNotOpen          = errors.New("pr is not open")
+	ErrNotApproved        = errors.New("pr does not have the required approvals")
+
+	ErrIdempotencyKeyReused = errors.New("idempotency key was already used with a different request body")
 )
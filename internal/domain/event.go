@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// EventType — тип доменного события, которое уходит во внешние системы.
+type EventType string
+
+const (
+	EventPRCreated          EventType = "pr.created"
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerReassigned EventType = "reviewer.reassigned"
+	EventPRMerged           EventType = "pr.merged"
+	EventUserDeactivated    EventType = "user.deactivated"
+	EventReviewSLABreached  EventType = "review.sla_breached"
+)
+
+func (t EventType) IsValid() bool {
+	switch t {
+	case EventPRCreated,
+		EventReviewerAssigned,
+		EventReviewerReassigned,
+		EventPRMerged,
+		EventUserDeactivated,
+		EventReviewSLABreached:
+		return true
+	}
+	return false
+}
+
+// Event — событие для подписчиков. TeamName — команда, к которой оно относится
+// (для PR — команда автора), по ней выбираются подписки.
+type Event struct {
+	ID         string
+	Type       EventType
+	TeamName   string
+	OccurredAt time.Time
+	Data       any
+}
+
+// EventPublisher принимает события в той же транзакции, что и изменение,
+// и не должен ждать внешние системы.
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
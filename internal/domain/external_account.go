@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExternalAccount связывает логин во внешней системе с users.user_id.
+type ExternalAccount struct {
+	Provider  CodeHost
+	Login     string
+	UserID    string
+	CreatedAt time.Time
+}
+
+// NormalizeLogin приводит логин к виду, в котором он хранится: логины GitHub и GitLab регистронезависимы.
+func NormalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+func (a ExternalAccount) Validate() error {
+	if !a.Provider.IsValid() {
+		return fmt.Errorf("%w: unknown provider %q", ErrInvalidInput, a.Provider)
+	}
+	if NormalizeLogin(a.Login) == "" {
+		return fmt.Errorf("%w: login is required", ErrInvalidInput)
+	}
+	if a.UserID == "" {
+		return fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+	}
+	return nil
+}
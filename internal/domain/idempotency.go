@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord — сохранённый ответ на запрос с заголовком Idempotency-Key.
+type IdempotencyRecord struct {
+	Key         string
+	Endpoint    string
+	RequestHash string
+	// StatusCode равен 0, пока первый запрос с этим ключом ещё выполняется.
+	StatusCode   int
+	ResponseBody []byte
+	CreatedAt    time.Time
+}
+
+func (r IdempotencyRecord) InProgress() bool {
+	return r.StatusCode == 0
+}
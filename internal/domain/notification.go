@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// NotificationKind — повод уведомить пользователя.
+type NotificationKind string
+
+const (
+	// NotificationAssigned — пользователя назначили ревьювером.
+	NotificationAssigned NotificationKind = "assigned"
+	// NotificationReplaced — пользователя сняли с ревью и, возможно, заменили другим.
+	NotificationReplaced NotificationKind = "replaced"
+	// NotificationMerged — PR, где пользователь автор или ревьювер, смержен.
+	NotificationMerged NotificationKind = "merged"
+	// NotificationSLABreach — ревьювер в команде, где пользователь тимлид, нарушил SLA.
+	NotificationSLABreach NotificationKind = "sla_breach"
+)
+
+func AllNotificationKinds() []NotificationKind {
+	return []NotificationKind{NotificationAssigned, NotificationReplaced, NotificationMerged, NotificationSLABreach}
+}
+
+func (k NotificationKind) IsValid() bool {
+	switch k {
+	case NotificationAssigned, NotificationReplaced, NotificationMerged, NotificationSLABreach:
+		return true
+	}
+	return false
+}
+
+// NotificationPreferences — настройки уведомлений пользователя.
+// Пока пользователь их не сохранял, действуют DefaultNotificationPreferences.
+type NotificationPreferences struct {
+	UserID  string
+	Enabled bool
+	Kinds   []NotificationKind
+	// SlackUserID (U0123...) нужен, чтобы упомянуть пользователя в общем канале.
+	SlackUserID string
+	// SlackWebhookURL — личный incoming webhook; пусто — общий канал из конфига.
+	SlackWebhookURL string
+	UpdatedAt       time.Time
+}
+
+func DefaultNotificationPreferences(userID string) NotificationPreferences {
+	return NotificationPreferences{
+		UserID:  userID,
+		Enabled: true,
+		Kinds:   AllNotificationKinds(),
+	}
+}
+
+func (p NotificationPreferences) Validate() error {
+	if p.UserID == "" {
+		return fmt.Errorf("%w: user_id is required", ErrInvalidInput)
+	}
+	for _, k := range p.Kinds {
+		if !k.IsValid() {
+			return fmt.Errorf("%w: unknown notification event %q", ErrInvalidInput, k)
+		}
+	}
+	if p.SlackWebhookURL != "" {
+		u, err := url.Parse(p.SlackWebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: slack_webhook_url must be an absolute http(s) url", ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+func (p NotificationPreferences) Wants(kind NotificationKind) bool {
+	if !p.Enabled {
+		return false
+	}
+	for _, k := range p.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationTemplate — текст сообщения команды в синтаксисе text/template.
+type NotificationTemplate struct {
+	TeamName  string
+	Kind      NotificationKind
+	Template  string
+	UpdatedAt time.Time
+}
+
+// Notification — готовое сообщение одному пользователю.
+type Notification struct {
+	Kind   NotificationKind
+	UserID string
+	Text   string
+	// Target — адрес доставки в терминах канала (для Slack — incoming webhook); пусто — канал по умолчанию.
+	Target string
+}
+
+// EmailMessage — письмо в виде простого текста.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
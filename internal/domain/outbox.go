@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// OutboxMessage — доменное событие, сохранённое в той же транзакции, что и изменение.
+// Payload — готовый JSON-конверт события, его и получают все приёмники.
+type OutboxMessage struct {
+	ID            int64
+	EventID       string
+	EventType     EventType
+	TeamName      string
+	Payload       []byte
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	// DeliveredSinks — приёмники, которые уже приняли сообщение; при повторе они пропускаются.
+	DeliveredSinks []string
+	CreatedAt      time.Time
+	PublishedAt    *time.Time
+}
+
+func (m OutboxMessage) DeliveredTo(sink string) bool {
+	for _, s := range m.DeliveredSinks {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,64 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 type PRStatus string
 
 const (
+	PullRequestStatusDraft  PRStatus = "DRAFT"
 	PullRequestStatusOpen   PRStatus = "OPEN"
 	PullRequestStatusMerged PRStatus = "MERGED"
+	PullRequestStatusClosed PRStatus = "CLOSED"
+)
+
+// PRTransition — действие, переводящее PR из одного статуса в другой.
+type PRTransition string
+
+const (
+	PRTransitionReady  PRTransition = "ready"
+	PRTransitionClose  PRTransition = "close"
+	PRTransitionReopen PRTransition = "reopen"
+	PRTransitionMerge  PRTransition = "merge"
 )
 
+type prTransitionRule struct {
+	from []PRStatus
+	to   PRStatus
+}
+
+// prTransitions — единственное место, где описан жизненный цикл PR:
+//
+//	DRAFT --ready--> OPEN --merge--> MERGED
+//	DRAFT/OPEN --close--> CLOSED --reopen--> OPEN
+var prTransitions = map[PRTransition]prTransitionRule{
+	PRTransitionReady:  {from: []PRStatus{PullRequestStatusDraft}, to: PullRequestStatusOpen},
+	PRTransitionClose:  {from: []PRStatus{PullRequestStatusDraft, PullRequestStatusOpen}, to: PullRequestStatusClosed},
+	PRTransitionReopen: {from: []PRStatus{PullRequestStatusClosed}, to: PullRequestStatusOpen},
+	PRTransitionMerge:  {from: []PRStatus{PullRequestStatusOpen}, to: PullRequestStatusMerged},
+}
+
+// Target возвращает статус, в который ведёт переход.
+func (t PRTransition) Target() PRStatus {
+	return prTransitions[t].to
+}
+
+// Next проверяет, что переход t допустим из статуса s, и возвращает новый статус.
+func (s PRStatus) Next(t PRTransition) (PRStatus, error) {
+	rule, ok := prTransitions[t]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown transition %q", ErrInvalidTransition, t)
+	}
+	for _, from := range rule.from {
+		if from == s {
+			return rule.to, nil
+		}
+	}
+	return "", fmt.Errorf("%w: cannot %s pr in status %s", ErrInvalidTransition, t, s)
+}
+
 type PullRequest struct {
 	ID                string
 	Name              string
@@ -17,4 +67,37 @@ type PullRequest struct {
 	AssignedReviewers []string
 	CreatedAt         time.Time
 	MergedAt          *time.Time
+	ClosedAt          *time.Time
+	// ReviewerTeam — команда, из которой назначаются ревьюверы; пусто — команда автора.
+	ReviewerTeam string
+	// StaleSince — когда PR помечен устаревшим; nil, если после этого была активность или пометки не было.
+	StaleSince *time.Time
+	// Reviews — решения текущих ревьюверов; решения снятых с PR не учитываются.
+	Reviews []ReviewDecision
+	// ReviewerSync — выгрузка ревьюверов во внешнюю систему; nil для PR, созданных через API.
+	ReviewerSync *ReviewerSync
+}
+
+// OpenReview — OPEN PR с текущим составом ревьюверов для массового переноса ревью.
+// TeamName — команда PR: reviewer_team или команда автора.
+type OpenReview struct {
+	PullRequestID string
+	AuthorID      string
+	TeamName      string
+	Reviewers     []string
+}
+
+// ReviewerChange — замена ревьювера на PR; пустой NewReviewerID означает, что ревьювер просто снят.
+type ReviewerChange struct {
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+}
+
+// ReviewReassignment — строка отчёта о переносе ревью с одного пользователя на другого.
+type ReviewReassignment struct {
+	PullRequestID string
+	OldReviewerID string
+	// NewReviewerID пуст, если замену найти не удалось и ревьювер остался прежним.
+	NewReviewerID string
 }
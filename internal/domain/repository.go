@@ -1,18 +1,37 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
+
+// TxManager объединяет вызовы репозиториев внутри fn в одну транзакцию.
+// Репозитории подхватывают транзакцию из переданного в fn контекста.
+type TxManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
 
 type TeamRepository interface {
-	Create(ctx context.Context, name string) error
+	Create(ctx context.Context, name string, settings TeamSettings) error
 	GetByName(ctx context.Context, name string) (Team, error)
 	List(ctx context.Context) ([]Team, error)
+	GetSettings(ctx context.Context, name string) (TeamSettings, error)
+	UpdateSettings(ctx context.Context, name string, settings TeamSettings) (TeamSettings, error)
+	// RoundRobinCursor возвращает user_id последнего ревьювера, назначенного по кругу, и блокирует
+	// строку команды до конца транзакции. Пустая строка — круг ещё не начинался или команды нет.
+	RoundRobinCursor(ctx context.Context, name string) (string, error)
+	SetRoundRobinCursor(ctx context.Context, name, userID string) error
 }
 
 type UserRepository interface {
 	Upsert(ctx context.Context, u User) error
 	GetByID(ctx context.Context, id string) (User, error)
 	ListActiveByTeam(ctx context.Context, teamName string) ([]User, error)
-	SetIsActive(ctx context.Context, userID string, isActive bool) (User, error)
+	// SetIsActive меняет флаг активности и возвращает пользователя вместе с прежним значением флага.
+	SetIsActive(ctx context.Context, userID string, isActive bool) (user User, wasActive bool, err error)
+	// DeactivateMany деактивирует перечисленных участников команды и возвращает их вместе с прежним
+	// значением флага по user_id; пользователи из других команд пропускаются.
+	DeactivateMany(ctx context.Context, teamName string, userIDs []string) (users []User, wasActive map[string]bool, err error)
 }
 
 type PullRequestRepository interface {
@@ -20,9 +39,151 @@ type PullRequestRepository interface {
 	AssignReviewers(ctx context.Context, prID string, reviewerIDs []string) error
 
 	GetByID(ctx context.Context, id string) (PullRequest, error)
+	// GetByIDForUpdate — то же, что GetByID, но блокирует PR до конца транзакции.
+	GetByIDForUpdate(ctx context.Context, id string) (PullRequest, error)
 	ListReviewers(ctx context.Context, prID string) ([]string, error)
 	ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error
 	RemoveReviewer(ctx context.Context, prID, reviewerID string) error
 	Merge(ctx context.Context, prID string) error
+	// SetStatus меняет статус без проверок (их делает сервис); closed_at ставится только для CLOSED.
+	SetStatus(ctx context.Context, prID string, status PRStatus) error
+	SaveReview(ctx context.Context, review ReviewDecision) error
+	// ListReviews возвращает решения только тех, кто сейчас назначен на PR.
+	ListReviews(ctx context.Context, prID string) ([]ReviewDecision, error)
 	ListByReviewer(ctx context.Context, reviewerID string) ([]PullRequest, error)
+	// OpenReviewCounts возвращает число OPEN PR на ревью у каждого из пользователей;
+	// пользователей без открытых ревью в результате нет.
+	OpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int, error)
+	// ListOpenReviews возвращает OPEN PR, где ревьюит кто-то из reviewerIDs, и блокирует их
+	// до конца транзакции.
+	ListOpenReviews(ctx context.Context, reviewerIDs []string) ([]OpenReview, error)
+	// ApplyReviewerChanges применяет пачку замен и снятий ревьюверов за несколько запросов.
+	ApplyReviewerChanges(ctx context.Context, changes []ReviewerChange) error
+}
+
+type AbsenceRepository interface {
+	Create(ctx context.Context, a *Absence) error
+	GetByID(ctx context.Context, id int64) (Absence, error)
+	ListByUser(ctx context.Context, userID string) ([]Absence, error)
+	Update(ctx context.Context, a Absence) (Absence, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// ReviewerEventRepository — только добавление и чтение: история не редактируется.
+type ReviewerEventRepository interface {
+	Append(ctx context.Context, events ...ReviewerEvent) error
+	ListByPR(ctx context.Context, prID string) ([]ReviewerEvent, error)
+}
+
+type AuditRepository interface {
+	Insert(ctx context.Context, entry AuditEntry) error
+	// List возвращает записи от новых к старым.
+	List(ctx context.Context, filter AuditFilter) ([]AuditEntry, error)
+}
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *WebhookSubscription) error
+	ListSubscriptions(ctx context.Context, teamName string) ([]WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int64) error
+
+	Enqueue(ctx context.Context, deliveries ...WebhookDelivery) error
+	// ClaimDue забирает до limit готовых к отправке доставок и откладывает их на lease,
+	// чтобы другой экземпляр сервиса не отправил их параллельно.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error
+}
+
+type OutboxRepository interface {
+	Add(ctx context.Context, messages ...OutboxMessage) error
+	// ClaimPending забирает до limit неопубликованных сообщений в порядке появления
+	// и откладывает их на lease, чтобы их не взял relay другого экземпляра.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]OutboxMessage, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string, deliveredSinks []string) error
+}
+
+type ExternalAccountRepository interface {
+	// Upsert привязывает логин к пользователю; повторная привязка того же логина заменяет пользователя.
+	Upsert(ctx context.Context, account *ExternalAccount) error
+	// GetUserID возвращает ErrNotFound, если логин ни к кому не привязан.
+	GetUserID(ctx context.Context, provider CodeHost, login string) (string, error)
+	List(ctx context.Context, provider CodeHost) ([]ExternalAccount, error)
+	// GetLogin — обратный поиск: логин пользователя во внешней системе или ErrNotFound.
+	GetLogin(ctx context.Context, provider CodeHost, userID string) (string, error)
+}
+
+type ReviewerSyncRepository interface {
+	// Request ставит PR в очередь выгрузки заново: статус pending, счётчик попыток сброшен, версия +1.
+	Request(ctx context.Context, prID string) error
+	Get(ctx context.Context, prID string) (ReviewerSync, error)
+	// ClaimDue забирает до limit готовых к попытке PR и откладывает их на lease.
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]ReviewerSync, error)
+	// Mark* обновляют запись, только если её версия не изменилась с момента ClaimDue.
+	MarkSynced(ctx context.Context, prID string, version int64) error
+	MarkRetry(ctx context.Context, prID string, version int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, prID string, version int64, attempts int, lastError string) error
+}
+
+type NotificationRepository interface {
+	// GetPreferences возвращает ErrNotFound, если пользователь настройки не сохранял.
+	GetPreferences(ctx context.Context, userID string) (NotificationPreferences, error)
+	UpsertPreferences(ctx context.Context, prefs *NotificationPreferences) error
+
+	ListTemplates(ctx context.Context, teamName string) ([]NotificationTemplate, error)
+	// GetTemplate возвращает ErrNotFound, если у команды нет своего шаблона для kind.
+	GetTemplate(ctx context.Context, teamName string, kind NotificationKind) (NotificationTemplate, error)
+	UpsertTemplate(ctx context.Context, tpl *NotificationTemplate) error
+	DeleteTemplate(ctx context.Context, teamName string, kind NotificationKind) error
+}
+
+type DigestRepository interface {
+	// ListRecipients — активные пользователи с email, которые сейчас не в отсутствии.
+	ListRecipients(ctx context.Context) ([]User, error)
+	// Claim отмечает дайджест пользователя за день; false — его уже забрал другой экземпляр.
+	Claim(ctx context.Context, userID string, day time.Time) (bool, error)
+	// Release снимает отметку, чтобы дайджест отправился на следующей проверке.
+	Release(ctx context.Context, userID string, day time.Time) error
+}
+
+type SLARepository interface {
+	// ListOverdue — назначения на OPEN PR без решения, просроченные по календарным часам
+	// и ещё не отмеченные как нарушение; самые старые первыми.
+	ListOverdue(ctx context.Context, now time.Time) ([]OverdueReview, error)
+	// RecordBreach сохраняет нарушение и заполняет ID; false — его уже записал другой экземпляр.
+	RecordBreach(ctx context.Context, breach *SLABreach) (bool, error)
+	SetNewReviewer(ctx context.Context, breachID int64, newReviewerID string) error
+	// ListBreaches возвращает нарушения от новых к старым.
+	ListBreaches(ctx context.Context, filter SLABreachFilter) ([]SLABreach, error)
+}
+
+// StatsRepository строит отчёты агрегирующими запросами.
+type StatsRepository interface {
+	// ReviewerStats возвращает строку на каждого пользователя, подходящего под фильтр, даже без активности.
+	ReviewerStats(ctx context.Context, filter ReviewerStatsFilter) ([]ReviewerStats, error)
+}
+
+type StaleRepository interface {
+	// MarkStale помечает OPEN и DRAFT PR, по которым не было активности дольше stale_after_days
+	// команды автора, и возвращает только что помеченные.
+	MarkStale(ctx context.Context, now time.Time) ([]StalePR, error)
+	// ListToClose — помеченные PR, у которых истёк stale_close_after_days; самые старые первыми.
+	ListToClose(ctx context.Context, now time.Time) ([]StalePR, error)
+}
+
+// Locker — блокировка, общая для всех экземпляров сервиса.
+type Locker interface {
+	// TryLock берёт блокировку name без ожидания; ok=false — её держит другой экземпляр.
+	// unlock нужно вызвать, только если ok.
+	TryLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+}
+
+type IdempotencyRepository interface {
+	// Reserve занимает ключ под новый запрос. Если ключ уже занят и ещё не протух (ttl),
+	// возвращает существующую запись и false. Незавершённую бронь старше lease можно перехватить:
+	// её владелец, скорее всего, упал, не успев ни сохранить ответ, ни освободить ключ.
+	Reserve(ctx context.Context, rec IdempotencyRecord, ttl, lease time.Duration) (IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key, endpoint string, statusCode int, body []byte) error
+	Release(ctx context.Context, key, endpoint string) error
 }
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+type ReviewDecisionType string
+
+const (
+	ReviewDecisionApproved         ReviewDecisionType = "APPROVED"
+	ReviewDecisionChangesRequested ReviewDecisionType = "CHANGES_REQUESTED"
+	ReviewDecisionCommented        ReviewDecisionType = "COMMENTED"
+)
+
+func (d ReviewDecisionType) IsValid() bool {
+	switch d {
+	case ReviewDecisionApproved,
+		ReviewDecisionChangesRequested,
+		ReviewDecisionCommented:
+		return true
+	}
+	return false
+}
+
+// ReviewDecision — последнее решение ревьювера по PR.
+type ReviewDecision struct {
+	PullRequestID string
+	ReviewerID    string
+	Decision      ReviewDecisionType
+	DecidedAt     time.Time
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+type ReviewerEventType string
+
+const (
+	ReviewerEventAssigned ReviewerEventType = "assigned"
+	ReviewerEventReplaced ReviewerEventType = "replaced"
+	ReviewerEventRemoved  ReviewerEventType = "removed"
+)
+
+// Причины, с которыми сервис пишет события назначения.
+const (
+	ReviewerEventReasonPRCreated       = "pr_created"
+	ReviewerEventReasonPRReady         = "pr_ready"
+	ReviewerEventReasonPRReopened      = "pr_reopened"
+	ReviewerEventReasonReassign        = "reassign"
+	ReviewerEventReasonUserDeactivated = "user_deactivated"
+	ReviewerEventReasonSLABreach       = "sla_breach"
+)
+
+// ReviewerEvent — запись в append-only истории ревьюверов PR.
+type ReviewerEvent struct {
+	ID            int64
+	PullRequestID string
+	Type          ReviewerEventType
+	ReviewerID    string
+	// ReplacedBy заполнен только для replaced — кто занял место ReviewerID.
+	ReplacedBy string
+	// ActorID — кто вызвал изменение (заголовок X-Actor-ID); пусто, если неизвестно.
+	ActorID   string
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+type ReviewerSyncStatus string
+
+const (
+	ReviewerSyncPending ReviewerSyncStatus = "pending"
+	ReviewerSyncSynced  ReviewerSyncStatus = "synced"
+	ReviewerSyncFailed  ReviewerSyncStatus = "failed"
+)
+
+// ReviewerSync — состояние выгрузки ревьюверов PR во внешнюю систему.
+// Version растёт при каждой смене ревьюверов: результат устаревшей попытки не перезаписывает новую.
+type ReviewerSync struct {
+	PullRequestID string
+	Status        ReviewerSyncStatus
+	Version       int64
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxSLAHours — верхняя граница SLA на ревью (30 суток).
+const MaxSLAHours = 720
+
+// WorkCalendar — рабочие часы для SLA: будни с Start до End (от начала суток) в Location.
+type WorkCalendar struct {
+	Start    time.Duration
+	End      time.Duration
+	Location *time.Location
+}
+
+func (c WorkCalendar) Validate() error {
+	if c.Start < 0 || c.End > 24*time.Hour || c.Start >= c.End {
+		return fmt.Errorf("%w: working day must start before it ends", ErrInvalidInput)
+	}
+	return nil
+}
+
+// AddWorkingHours возвращает момент, когда от t пройдёт d рабочего времени.
+func (c WorkCalendar) AddWorkingHours(t time.Time, d time.Duration) time.Time {
+	loc := c.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	t = t.In(loc)
+
+	for {
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		if wd := day.Weekday(); wd != time.Saturday && wd != time.Sunday {
+			open, closed := day.Add(c.Start), day.Add(c.End)
+			if t.Before(open) {
+				t = open
+			}
+			if t.Before(closed) {
+				left := closed.Sub(t)
+				if d <= left {
+					return t.Add(d)
+				}
+				d -= left
+			}
+		}
+		t = day.AddDate(0, 0, 1)
+	}
+}
+
+// SLADeadline — к какому моменту ревьювер, назначенный в assignedAt, должен оставить решение.
+func (c WorkCalendar) SLADeadline(assignedAt time.Time, hours int, workingHours bool) time.Time {
+	d := time.Duration(hours) * time.Hour
+	if !workingHours {
+		return assignedAt.Add(d)
+	}
+	return c.AddWorkingHours(assignedAt, d)
+}
+
+// OverdueReview — назначение на OPEN PR без решения ревьювера, у которого истекли
+// SLAHours календарных часов. С рабочими часами дедлайн может быть ещё не наступил.
+type OverdueReview struct {
+	PullRequestID   string
+	PullRequestName string
+	ReviewerID      string
+	AssignedAt      time.Time
+	TeamName        string
+	SLAHours        int
+	SLAWorkingHours bool
+	SLAAutoReassign bool
+	TeamLead        string
+}
+
+// SLABreach — зафиксированное нарушение SLA одним назначением.
+type SLABreach struct {
+	ID              int64
+	PullRequestID   string
+	PullRequestName string
+	TeamName        string
+	ReviewerID      string
+	AssignedAt      time.Time
+	Deadline        time.Time
+	DetectedAt      time.Time
+	// NewReviewerID — кому ревью передано автоматически; пусто, если не передавалось.
+	NewReviewerID string
+	// Open — ревьювер всё ещё назначен на OPEN PR и решения не оставил.
+	Open bool
+}
+
+// SLABreachFilter — условия выборки нарушений; пустые поля не ограничивают выборку.
+type SLABreachFilter struct {
+	TeamName   string
+	ReviewerID string
+	From       time.Time
+	To         time.Time
+	OpenOnly   bool
+	Limit      int
+}
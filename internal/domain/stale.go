@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// StalePR — PR, помеченный устаревшим, с командой автора, чья политика к нему применяется.
+type StalePR struct {
+	PullRequestID string
+	TeamName      string
+	StaleSince    time.Time
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// ReviewerStats — сводка по ревьюверу. Назначения и передачи считаются по истории за период,
+// MergedReviews и MedianTimeToMerge — по PR, смёрженным за период, OpenReviews — на текущий момент.
+type ReviewerStats struct {
+	UserID   string
+	Username string
+	TeamName string
+	// Assignments — сколько раз пользователь был назначен ревьювером, в том числе на замену.
+	Assignments int
+	OpenReviews int
+	// MergedReviews — смёрженные PR, в которых пользователь остался ревьювером.
+	MergedReviews int
+	// ReassignedAway — сколько раз ревью передавали от пользователя другому или снимали с него.
+	ReassignedAway int
+	// MedianTimeToMerge — медиана времени от создания до merge по MergedReviews; 0, если их нет.
+	MedianTimeToMerge time.Duration
+}
+
+// ReviewerStatsFilter — условия отчёта; пустые поля не ограничивают выборку.
+type ReviewerStatsFilter struct {
+	TeamName string
+	From     time.Time
+	To       time.Time
+}
+
+// DefaultFairnessWindow — период отчёта о равномерности нагрузки, если начало не задано.
+const DefaultFairnessWindow = 30 * 24 * time.Hour
+
+// TeamFairness — как назначения на ревью распределились между участниками команды за период.
+type TeamFairness struct {
+	TeamName         string
+	From             time.Time
+	To               time.Time
+	TotalAssignments int
+	// Gini — коэффициент Джини по числу назначений: 0 — поровну, ближе к 1 — почти всё у одного.
+	Gini float64
+	// MaxMinRatio — отношение наибольшего числа назначений к наименьшему; 0, если у кого-то их нет.
+	MaxMinRatio float64
+	Members     []MemberLoad
+}
+
+// MemberLoad — нагрузка участника: неактивные попадают в отчёт, только если получали назначения.
+type MemberLoad struct {
+	UserID      string
+	Username    string
+	IsActive    bool
+	Assignments int
+	// Share — доля участника в назначениях команды за период.
+	Share float64
+}
@@ -1,12 +1,154 @@
 package domain
 
+import "fmt"
+
+type AssignmentStrategy string
+
+const (
+	AssignmentStrategyRandom      AssignmentStrategy = "random"
+	AssignmentStrategyRoundRobin  AssignmentStrategy = "round_robin"
+	AssignmentStrategyWeighted    AssignmentStrategy = "weighted"
+	AssignmentStrategyLeastLoaded AssignmentStrategy = "least_loaded"
+)
+
+func (s AssignmentStrategy) IsValid() bool {
+	switch s {
+	case AssignmentStrategyRandom,
+		AssignmentStrategyRoundRobin,
+		AssignmentStrategyWeighted,
+		AssignmentStrategyLeastLoaded:
+		return true
+	}
+	return false
+}
+
+// MaxStaleDays — верхняя граница для сроков политики устаревших PR.
+const MaxStaleDays = 365
+
+// DefaultReviewWeight используется для участников, у которых вес не задан явно.
+const DefaultReviewWeight = 1
+
 type TeamMember struct {
-	ID       string
-	Username string
-	IsActive bool
+	ID             string
+	Username       string
+	Email          string
+	IsActive       bool
+	ReviewWeight   int
+	MaxOpenReviews int
+}
+
+type TeamSettings struct {
+	AssignmentStrategy AssignmentStrategy
+	// MinReviewers — сколько ревьюверов обязательно; если кандидатов меньше, PR не создаётся.
+	MinReviewers int
+	// MaxReviewers — сколько ревьюверов назначается на новый PR.
+	MaxReviewers int
+	// FallbackTeam — откуда добирать ревьюверов, если в команде не осталось свободных. Пусто — не добирать.
+	FallbackTeam string
+	// RequiredApprovals — сколько APPROVED от назначенных ревьюверов нужно для merge. 0 — merge без проверки.
+	RequiredApprovals int
+	// SLAHours — за сколько часов ревьювер должен оставить решение после назначения. 0 — SLA не отслеживается.
+	SLAHours int
+	// SLAWorkingHours — считать только рабочие часы (будни, рабочий день из конфига), а не календарные.
+	SLAWorkingHours bool
+	// SLAAutoReassign — при нарушении SLA передавать ревью другому участнику.
+	SLAAutoReassign bool
+	// TeamLead — кому сообщать о нарушениях SLA; пусто — никому.
+	TeamLead string
+	// StaleAfterDays — через сколько дней без активности PR помечается устаревшим. 0 — не помечать.
+	StaleAfterDays int
+	// StaleCloseAfterDays — через сколько дней после пометки устаревший PR закрывается. 0 — не закрывать.
+	StaleCloseAfterDays int
+}
+
+func DefaultTeamSettings() TeamSettings {
+	return TeamSettings{
+		AssignmentStrategy: AssignmentStrategyRandom,
+		MinReviewers:       0,
+		MaxReviewers:       2,
+	}
+}
+
+func (s TeamSettings) Validate() error {
+	if !s.AssignmentStrategy.IsValid() {
+		return fmt.Errorf("%w: unknown assignment strategy %q", ErrInvalidInput, s.AssignmentStrategy)
+	}
+	if s.MinReviewers < 0 || s.MaxReviewers < 1 || s.MinReviewers > s.MaxReviewers {
+		return fmt.Errorf("%w: expected 0 <= min_reviewers <= max_reviewers and max_reviewers >= 1, got %d/%d",
+			ErrInvalidInput, s.MinReviewers, s.MaxReviewers)
+	}
+	// Иначе PR может открыться с меньшим числом ревьюверов, чем нужно апрувов, и его нельзя будет смёржить.
+	if s.RequiredApprovals < 0 || s.RequiredApprovals > s.MinReviewers {
+		return fmt.Errorf("%w: expected 0 <= required_approvals <= min_reviewers, got %d/%d",
+			ErrInvalidInput, s.RequiredApprovals, s.MinReviewers)
+	}
+	if s.SLAHours < 0 || s.SLAHours > MaxSLAHours {
+		return fmt.Errorf("%w: expected 0 <= sla_hours <= %d, got %d", ErrInvalidInput, MaxSLAHours, s.SLAHours)
+	}
+	if s.StaleAfterDays < 0 || s.StaleAfterDays > MaxStaleDays || s.StaleCloseAfterDays < 0 || s.StaleCloseAfterDays > MaxStaleDays {
+		return fmt.Errorf("%w: stale_after_days and stale_close_after_days must be between 0 and %d",
+			ErrInvalidInput, MaxStaleDays)
+	}
+	if s.StaleCloseAfterDays > 0 && s.StaleAfterDays == 0 {
+		return fmt.Errorf("%w: stale_close_after_days requires stale_after_days", ErrInvalidInput)
+	}
+	return nil
+}
+
+// TeamSettingsUpdate описывает частичное изменение настроек: nil-поля не трогаются.
+type TeamSettingsUpdate struct {
+	AssignmentStrategy  *AssignmentStrategy
+	MinReviewers        *int
+	MaxReviewers        *int
+	FallbackTeam        *string
+	RequiredApprovals   *int
+	SLAHours            *int
+	SLAWorkingHours     *bool
+	SLAAutoReassign     *bool
+	TeamLead            *string
+	StaleAfterDays      *int
+	StaleCloseAfterDays *int
+}
+
+func (u TeamSettingsUpdate) Apply(s TeamSettings) TeamSettings {
+	if u.AssignmentStrategy != nil {
+		s.AssignmentStrategy = *u.AssignmentStrategy
+	}
+	if u.MinReviewers != nil {
+		s.MinReviewers = *u.MinReviewers
+	}
+	if u.MaxReviewers != nil {
+		s.MaxReviewers = *u.MaxReviewers
+	}
+	if u.FallbackTeam != nil {
+		s.FallbackTeam = *u.FallbackTeam
+	}
+	if u.RequiredApprovals != nil {
+		s.RequiredApprovals = *u.RequiredApprovals
+	}
+	if u.SLAHours != nil {
+		s.SLAHours = *u.SLAHours
+	}
+	if u.SLAWorkingHours != nil {
+		s.SLAWorkingHours = *u.SLAWorkingHours
+	}
+	if u.SLAAutoReassign != nil {
+		s.SLAAutoReassign = *u.SLAAutoReassign
+	}
+	if u.TeamLead != nil {
+		s.TeamLead = *u.TeamLead
+	}
+	if u.StaleAfterDays != nil {
+		s.StaleAfterDays = *u.StaleAfterDays
+	}
+	if u.StaleCloseAfterDays != nil {
+		s.StaleCloseAfterDays = *u.StaleCloseAfterDays
+	}
+	return s
 }
 
 type Team struct {
-	Name    string
-	Members []TeamMember
+	Name     string
+	Members  []TeamMember
+	Settings TeamSettings
 }
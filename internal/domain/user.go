@@ -3,6 +3,11 @@ package domain
 type User struct {
 	ID       string
 	Username string
-	TeamName string
-	IsActive bool
+	// Email — куда слать дайджест ревью; пусто — дайджест не отправляется.
+	Email        string
+	TeamName     string
+	IsActive     bool
+	ReviewWeight int
+	// MaxOpenReviews — сколько OPEN PR можно держать на ревью одновременно, 0 — без ограничения.
+	MaxOpenReviews int
 }
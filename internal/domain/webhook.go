@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+type WebhookSubscription struct {
+	ID         int64
+	TeamName   string
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+func (s WebhookSubscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) url", ErrInvalidInput)
+	}
+	if s.Secret == "" {
+		return fmt.Errorf("%w: secret must not be empty", ErrInvalidInput)
+	}
+	if len(s.EventTypes) == 0 {
+		return fmt.Errorf("%w: event_types must not be empty", ErrInvalidInput)
+	}
+	for _, t := range s.EventTypes {
+		if !t.IsValid() {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, t)
+		}
+	}
+	return nil
+}
+
+func (s WebhookSubscription) Accepts(t EventType) bool {
+	for _, et := range s.EventTypes {
+		if et == t {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery — одна отправка события одному подписчику.
+// URL и Secret подставляются из подписки при выборке очереди.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        string
+	EventType      EventType
+	Payload        []byte
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	URL            string
+	Secret         string
+}
@@ -0,0 +1,81 @@
+// Package notify — каналы доставки личных уведомлений.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// Error — отказ канала доставки. Permanent — повтор не поможет (webhook удалён, канал в архиве).
+type Error struct {
+	Status int
+	Msg    string
+}
+
+func (e *Error) Error() string {
+	if e.Status == 0 {
+		return e.Msg
+	}
+	return fmt.Sprintf("slack responded %d: %s", e.Status, e.Msg)
+}
+
+func (e *Error) Permanent() bool {
+	return e.Status == 0 || (e.Status >= 400 && e.Status < 500 && e.Status != http.StatusTooManyRequests)
+}
+
+// Slack отправляет уведомления в incoming webhook: личный из Notification.Target или общий канал.
+type Slack struct {
+	defaultURL string
+	http       *http.Client
+}
+
+func NewSlack(defaultURL string, client *http.Client) *Slack {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Slack{
+		defaultURL: defaultURL,
+		http:       client,
+	}
+}
+
+func (s *Slack) Notify(ctx context.Context, n domain.Notification) error {
+	url := n.Target
+	if url == "" {
+		url = s.defaultURL
+	}
+	if url == "" {
+		return &Error{Msg: "slack webhook url is not configured"}
+	}
+
+	body, err := json.Marshal(struct {
+		Text string `json:"text"`
+	}{Text: n.Text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return &Error{Msg: fmt.Sprintf("slack webhook url: %v", err)}
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode != http.StatusOK {
+		return &Error{Status: resp.StatusCode, Msg: string(bytes.TrimSpace(msg))}
+	}
+	return nil
+}
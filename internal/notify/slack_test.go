@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+func TestSlack_PostsToTargetOrDefault(t *testing.T) {
+	got := make(map[string]string)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Text string `json:"text"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		got[r.URL.Path] = body.Text
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	slack := NewSlack(srv.URL+"/channel", srv.Client())
+	ctx := context.Background()
+
+	if err := slack.Notify(ctx, domain.Notification{Text: "to channel"}); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+	if err := slack.Notify(ctx, domain.Notification{Text: "to me", Target: srv.URL + "/personal"}); err != nil {
+		t.Fatalf("Notify error: %v", err)
+	}
+
+	if got["/channel"] != "to channel" || got["/personal"] != "to me" {
+		t.Fatalf("unexpected deliveries: %v", got)
+	}
+}
+
+func TestSlack_ClassifiesErrors(t *testing.T) {
+	status := http.StatusNotFound
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("no_service"))
+	}))
+	defer srv.Close()
+
+	slack := NewSlack(srv.URL, srv.Client())
+	ctx := context.Background()
+
+	err := slack.Notify(ctx, domain.Notification{Text: "x"})
+	if e, ok := err.(*Error); !ok || !e.Permanent() || e.Msg != "no_service" {
+		t.Fatalf("expected permanent error for 404, got %v", err)
+	}
+
+	status = http.StatusTooManyRequests
+	err = slack.Notify(ctx, domain.Notification{Text: "x"})
+	if e, ok := err.(*Error); !ok || e.Permanent() {
+		t.Fatalf("expected retryable error for 429, got %v", err)
+	}
+
+	err = NewSlack("", nil).Notify(ctx, domain.Notification{Text: "x"})
+	if e, ok := err.(*Error); !ok || !e.Permanent() {
+		t.Fatalf("expected permanent error without url, got %v", err)
+	}
+}
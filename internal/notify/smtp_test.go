@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// smtpStub — минимальный SMTP-сервер без TLS и авторизации; запоминает конверт и тело письма.
+type smtpStub struct {
+	ln   net.Listener
+	from string
+	rcpt []string
+	data string
+	done chan struct{}
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpStub{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { _ = ln.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *smtpStub) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 stub ready")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		switch upper := strings.ToUpper(cmd); {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 stub")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 ok")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 ok")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestSMTP_Send(t *testing.T) {
+	stub := newSMTPStub(t)
+	host, port, _ := net.SplitHostPort(stub.ln.Addr().String())
+	portNum, _ := strconv.Atoi(port)
+
+	mailer := NewSMTP(SMTPConfig{Host: host, Port: portNum, From: "bot@example.com", Timeout: 5 * time.Second})
+	err := mailer.Send(context.Background(), domain.EmailMessage{
+		To:      "alice@example.com",
+		Subject: "Review digest: 2 pull requests waiting",
+		Body:    "Hi Alice,\n\n.dot line\n",
+	})
+	if err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+	<-stub.done
+
+	if stub.from != "bot@example.com" || len(stub.rcpt) != 1 || stub.rcpt[0] != "alice@example.com" {
+		t.Fatalf("unexpected envelope: from=%q rcpt=%v", stub.from, stub.rcpt)
+	}
+	if !strings.Contains(stub.data, "Subject: Review digest: 2 pull requests waiting\r\n") {
+		t.Fatalf("subject header missing:\n%s", stub.data)
+	}
+	if !strings.Contains(stub.data, "\r\n\r\nHi Alice,\r\n\r\n..dot line\r\n") {
+		t.Fatalf("body must use CRLF and dot-stuffing:\n%s", stub.data)
+	}
+}
+
+func TestSMTP_ConnectError(t *testing.T) {
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := ln.Addr().(*net.TCPAddr)
+	_ = ln.Close()
+
+	mailer := NewSMTP(SMTPConfig{Host: "127.0.0.1", Port: addr.Port, From: "bot@example.com", Timeout: time.Second})
+	if err := mailer.Send(context.Background(), domain.EmailMessage{To: "a@example.com"}); err == nil {
+		t.Fatal("expected connection error")
+	}
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AbsenceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAbsenceRepo(pool *pgxpool.Pool) *AbsenceRepo {
+	return &AbsenceRepo{pool: pool}
+}
+
+func (r *AbsenceRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *AbsenceRepo) Create(ctx context.Context, a *domain.Absence) error {
+	const query = `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING absence_id;
+	`
+
+	return r.db(ctx).QueryRow(ctx, query, a.UserID, a.StartsAt, a.EndsAt, a.Reason).Scan(&a.ID)
+}
+
+func (r *AbsenceRepo) GetByID(ctx context.Context, id int64) (domain.Absence, error) {
+	const query = `
+		SELECT absence_id, user_id, starts_at, ends_at, reason
+		FROM user_absences
+		WHERE absence_id = $1;
+	`
+
+	var a domain.Absence
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, domain.ErrNotFound
+		}
+		return domain.Absence{}, err
+	}
+
+	return a, nil
+}
+
+func (r *AbsenceRepo) ListByUser(ctx context.Context, userID string) ([]domain.Absence, error) {
+	const query = `
+		SELECT absence_id, user_id, starts_at, ends_at, reason
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.Absence, 0)
+	for rows.Next() {
+		var a domain.Absence
+		if err := rows.Scan(&a.ID, &a.UserID, &a.StartsAt, &a.EndsAt, &a.Reason); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *AbsenceRepo) Update(ctx context.Context, a domain.Absence) (domain.Absence, error) {
+	const query = `
+		UPDATE user_absences
+		SET starts_at = $2,
+		    ends_at   = $3,
+		    reason    = $4
+		WHERE absence_id = $1
+		RETURNING absence_id, user_id, starts_at, ends_at, reason;
+	`
+
+	var res domain.Absence
+	err := r.db(ctx).QueryRow(ctx, query, a.ID, a.StartsAt, a.EndsAt, a.Reason).
+		Scan(&res.ID, &res.UserID, &res.StartsAt, &res.EndsAt, &res.Reason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, domain.ErrNotFound
+		}
+		return domain.Absence{}, err
+	}
+
+	return res, nil
+}
+
+func (r *AbsenceRepo) Delete(ctx context.Context, id int64) error {
+	const query = `
+		DELETE FROM user_absences
+		WHERE absence_id = $1;
+	`
+
+	cmd, err := r.db(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepo(pool *pgxpool.Pool) *AuditRepo {
+	return &AuditRepo{pool: pool}
+}
+
+func (r *AuditRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *AuditRepo) Insert(ctx context.Context, e domain.AuditEntry) error {
+	const query = `
+		INSERT INTO audit_log (
+			actor_id,
+			method,
+			endpoint,
+			entity_type,
+			entity_id,
+			payload_hash,
+			status_code,
+			outcome_code,
+			created_at
+		)
+		VALUES (NULLIF($1, ''), $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7, $8, $9);
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query,
+		e.ActorID,
+		e.Method,
+		e.Endpoint,
+		e.EntityType,
+		e.EntityID,
+		e.PayloadHash,
+		e.StatusCode,
+		e.OutcomeCode,
+		e.CreatedAt,
+	)
+	return err
+}
+
+func (r *AuditRepo) List(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	const query = `
+		SELECT audit_id,
+		       COALESCE(actor_id, ''),
+		       method,
+		       endpoint,
+		       COALESCE(entity_type, ''),
+		       COALESCE(entity_id, ''),
+		       payload_hash,
+		       status_code,
+		       outcome_code,
+		       created_at
+		FROM audit_log
+		WHERE ($1 = '' OR actor_id = $1)
+		  AND ($2 = '' OR entity_type = $2)
+		  AND ($3 = '' OR entity_id = $3)
+		  AND ($4::timestamptz IS NULL OR created_at >= $4)
+		  AND ($5::timestamptz IS NULL OR created_at < $5)
+		ORDER BY created_at DESC, audit_id DESC
+		LIMIT $6;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query,
+		f.ActorID,
+		f.EntityType,
+		f.EntityID,
+		nullTime(f.From),
+		nullTime(f.To),
+		f.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.AuditEntry, 0)
+	for rows.Next() {
+		var e domain.AuditEntry
+		err := rows.Scan(
+			&e.ID,
+			&e.ActorID,
+			&e.Method,
+			&e.Endpoint,
+			&e.EntityType,
+			&e.EntityID,
+			&e.PayloadHash,
+			&e.StatusCode,
+			&e.OutcomeCode,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+
+	return res, rows.Err()
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
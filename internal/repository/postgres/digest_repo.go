@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DigestRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDigestRepo(pool *pgxpool.Pool) *DigestRepo {
+	return &DigestRepo{pool: pool}
+}
+
+func (r *DigestRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *DigestRepo) ListRecipients(ctx context.Context) ([]domain.User, error) {
+	const query = `
+		SELECT user_id, username, team_name, is_active, review_weight, max_open_reviews, email
+		FROM users u
+		WHERE is_active = TRUE
+		  AND email <> ''
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM user_absences a
+		      WHERE a.user_id = u.user_id
+		        AND now() >= a.starts_at
+		        AND now() < a.ends_at
+		  )
+		ORDER BY user_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.ReviewWeight, &u.MaxOpenReviews, &u.Email); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *DigestRepo) Claim(ctx context.Context, userID string, day time.Time) (bool, error) {
+	const query = `
+		INSERT INTO email_digests (user_id, digest_date)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING;
+	`
+
+	cmd, err := r.db(ctx).Exec(ctx, query, userID, day)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+func (r *DigestRepo) Release(ctx context.Context, userID string, day time.Time) error {
+	const query = `
+		DELETE FROM email_digests
+		WHERE user_id = $1 AND digest_date = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, userID, day)
+	return err
+}
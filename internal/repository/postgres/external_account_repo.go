@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ExternalAccountRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewExternalAccountRepo(pool *pgxpool.Pool) *ExternalAccountRepo {
+	return &ExternalAccountRepo{pool: pool}
+}
+
+func (r *ExternalAccountRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *ExternalAccountRepo) Upsert(ctx context.Context, account *domain.ExternalAccount) error {
+	const query = `
+		INSERT INTO external_accounts (provider, login, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (provider, login) DO UPDATE
+		SET user_id = EXCLUDED.user_id
+		RETURNING created_at;
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		string(account.Provider),
+		account.Login,
+		account.UserID,
+	).Scan(&account.CreatedAt)
+}
+
+func (r *ExternalAccountRepo) GetUserID(ctx context.Context, provider domain.CodeHost, login string) (string, error) {
+	const query = `
+		SELECT user_id
+		FROM external_accounts
+		WHERE provider = $1 AND login = $2;
+	`
+
+	var userID string
+	if err := r.db(ctx).QueryRow(ctx, query, string(provider), login).Scan(&userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+
+	return userID, nil
+}
+
+func (r *ExternalAccountRepo) GetLogin(ctx context.Context, provider domain.CodeHost, userID string) (string, error) {
+	const query = `
+		SELECT login
+		FROM external_accounts
+		WHERE provider = $1 AND user_id = $2
+		ORDER BY created_at DESC
+		LIMIT 1;
+	`
+
+	var login string
+	if err := r.db(ctx).QueryRow(ctx, query, string(provider), userID).Scan(&login); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", domain.ErrNotFound
+		}
+		return "", err
+	}
+
+	return login, nil
+}
+
+func (r *ExternalAccountRepo) List(ctx context.Context, provider domain.CodeHost) ([]domain.ExternalAccount, error) {
+	const query = `
+		SELECT provider, login, user_id, created_at
+		FROM external_accounts
+		WHERE provider = $1
+		ORDER BY login;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, string(provider))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ExternalAccount, 0)
+	for rows.Next() {
+		var a domain.ExternalAccount
+		if err := rows.Scan(&a.Provider, &a.Login, &a.UserID, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, a)
+	}
+
+	return res, rows.Err()
+}
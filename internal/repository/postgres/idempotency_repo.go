@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepo(pool *pgxpool.Pool) *IdempotencyRepo {
+	return &IdempotencyRepo{pool: pool}
+}
+
+func (r *IdempotencyRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *IdempotencyRepo) Reserve(
+	ctx context.Context,
+	rec domain.IdempotencyRecord,
+	ttl, lease time.Duration,
+) (domain.IdempotencyRecord, bool, error) {
+	// Протухшая запись перезаписывается, как будто ключа не было. Так же перехватывается
+	// бронь без ответа старше lease: created_at у неё — время бронирования.
+	const reserveQuery = `
+		INSERT INTO idempotency_keys (idempotency_key, endpoint, request_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (idempotency_key, endpoint) DO UPDATE SET
+			request_hash  = EXCLUDED.request_hash,
+			status_code   = NULL,
+			response_body = NULL,
+			created_at    = now()
+		WHERE idempotency_keys.created_at < now() - make_interval(secs => $4)
+		   OR (idempotency_keys.status_code IS NULL
+		       AND idempotency_keys.created_at < now() - make_interval(secs => $5))
+		RETURNING created_at;
+	`
+
+	err := r.db(ctx).QueryRow(ctx, reserveQuery, rec.Key, rec.Endpoint, rec.RequestHash, ttl.Seconds(), lease.Seconds()).
+		Scan(&rec.CreatedAt)
+	if err == nil {
+		return rec, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	const selectQuery = `
+		SELECT idempotency_key, endpoint, request_hash, COALESCE(status_code, 0), response_body, created_at
+		FROM idempotency_keys
+		WHERE idempotency_key = $1 AND endpoint = $2;
+	`
+
+	var existing domain.IdempotencyRecord
+	err = r.db(ctx).QueryRow(ctx, selectQuery, rec.Key, rec.Endpoint).Scan(
+		&existing.Key,
+		&existing.Endpoint,
+		&existing.RequestHash,
+		&existing.StatusCode,
+		&existing.ResponseBody,
+		&existing.CreatedAt,
+	)
+	if err != nil {
+		return domain.IdempotencyRecord{}, false, err
+	}
+
+	return existing, false, nil
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, key, endpoint string, statusCode int, body []byte) error {
+	const query = `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_body = $4
+		WHERE idempotency_key = $1 AND endpoint = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, key, endpoint, statusCode, body)
+	return err
+}
+
+func (r *IdempotencyRepo) Release(ctx context.Context, key, endpoint string) error {
+	const query = `
+		DELETE FROM idempotency_keys
+		WHERE idempotency_key = $1 AND endpoint = $2 AND status_code IS NULL;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, key, endpoint)
+	return err
+}
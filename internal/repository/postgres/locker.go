@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unlockTimeout — сколько ждать снятия блокировки; контекст прохода к этому моменту может быть отменён.
+const unlockTimeout = 5 * time.Second
+
+// AdvisoryLocker — блокировки на pg_try_advisory_lock. Блокировка живёт в сессии, поэтому
+// соединение держится до unlock; если экземпляр упадёт, Postgres снимет её вместе с соединением.
+type AdvisoryLocker struct {
+	pool *pgxpool.Pool
+}
+
+func NewAdvisoryLocker(pool *pgxpool.Pool) *AdvisoryLocker {
+	return &AdvisoryLocker{pool: pool}
+}
+
+func (l *AdvisoryLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	c, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var ok bool
+	if err := c.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1));`, name).Scan(&ok); err != nil {
+		c.Release()
+		return nil, false, err
+	}
+	if !ok {
+		c.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+
+		if _, err := c.Exec(ctx, `SELECT pg_advisory_unlock(hashtext($1));`, name); err != nil {
+			// Соединение с неснятой блокировкой нельзя возвращать в пул — закрываем его.
+			_ = c.Conn().Close(ctx)
+		}
+		c.Release()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type NotificationRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewNotificationRepo(pool *pgxpool.Pool) *NotificationRepo {
+	return &NotificationRepo{pool: pool}
+}
+
+func (r *NotificationRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *NotificationRepo) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	const query = `
+		SELECT user_id, enabled, kinds, slack_user_id, slack_webhook_url, updated_at
+		FROM notification_preferences
+		WHERE user_id = $1;
+	`
+
+	var (
+		p     domain.NotificationPreferences
+		kinds []string
+	)
+	err := r.db(ctx).QueryRow(ctx, query, userID).Scan(
+		&p.UserID,
+		&p.Enabled,
+		&kinds,
+		&p.SlackUserID,
+		&p.SlackWebhookURL,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NotificationPreferences{}, domain.ErrNotFound
+		}
+		return domain.NotificationPreferences{}, err
+	}
+
+	p.Kinds = make([]domain.NotificationKind, 0, len(kinds))
+	for _, k := range kinds {
+		p.Kinds = append(p.Kinds, domain.NotificationKind(k))
+	}
+	return p, nil
+}
+
+func (r *NotificationRepo) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	const query = `
+		INSERT INTO notification_preferences (user_id, enabled, kinds, slack_user_id, slack_webhook_url)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+		    kinds = EXCLUDED.kinds,
+		    slack_user_id = EXCLUDED.slack_user_id,
+		    slack_webhook_url = EXCLUDED.slack_webhook_url,
+		    updated_at = now()
+		RETURNING updated_at;
+	`
+
+	kinds := make([]string, 0, len(prefs.Kinds))
+	for _, k := range prefs.Kinds {
+		kinds = append(kinds, string(k))
+	}
+
+	return r.db(ctx).QueryRow(ctx, query,
+		prefs.UserID,
+		prefs.Enabled,
+		kinds,
+		prefs.SlackUserID,
+		prefs.SlackWebhookURL,
+	).Scan(&prefs.UpdatedAt)
+}
+
+func (r *NotificationRepo) ListTemplates(ctx context.Context, teamName string) ([]domain.NotificationTemplate, error) {
+	const query = `
+		SELECT team_name, kind, template, updated_at
+		FROM notification_templates
+		WHERE team_name = $1
+		ORDER BY kind;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.NotificationTemplate, 0)
+	for rows.Next() {
+		var t domain.NotificationTemplate
+		if err := rows.Scan(&t.TeamName, &t.Kind, &t.Template, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, t)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *NotificationRepo) GetTemplate(
+	ctx context.Context,
+	teamName string,
+	kind domain.NotificationKind,
+) (domain.NotificationTemplate, error) {
+	const query = `
+		SELECT team_name, kind, template, updated_at
+		FROM notification_templates
+		WHERE team_name = $1 AND kind = $2;
+	`
+
+	var t domain.NotificationTemplate
+	err := r.db(ctx).QueryRow(ctx, query, teamName, string(kind)).Scan(&t.TeamName, &t.Kind, &t.Template, &t.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.NotificationTemplate{}, domain.ErrNotFound
+		}
+		return domain.NotificationTemplate{}, err
+	}
+
+	return t, nil
+}
+
+func (r *NotificationRepo) UpsertTemplate(ctx context.Context, tpl *domain.NotificationTemplate) error {
+	const query = `
+		INSERT INTO notification_templates (team_name, kind, template)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (team_name, kind) DO UPDATE
+		SET template = EXCLUDED.template,
+		    updated_at = now()
+		RETURNING updated_at;
+	`
+
+	return r.db(ctx).QueryRow(ctx, query, tpl.TeamName, string(tpl.Kind), tpl.Template).Scan(&tpl.UpdatedAt)
+}
+
+func (r *NotificationRepo) DeleteTemplate(ctx context.Context, teamName string, kind domain.NotificationKind) error {
+	const query = `
+		DELETE FROM notification_templates
+		WHERE team_name = $1 AND kind = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, teamName, string(kind))
+	return err
+}
@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutboxRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutboxRepo(pool *pgxpool.Pool) *OutboxRepo {
+	return &OutboxRepo{pool: pool}
+}
+
+func (r *OutboxRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *OutboxRepo) Add(ctx context.Context, messages ...domain.OutboxMessage) error {
+	const query = `
+		INSERT INTO outbox (event_id, event_type, team_name, payload)
+		VALUES ($1, $2, $3, $4);
+	`
+
+	for _, m := range messages {
+		if _, err := r.db(ctx).Exec(ctx, query, m.EventID, string(m.EventType), m.TeamName, m.Payload); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *OutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	const query = `
+		WITH due AS (
+			SELECT message_id
+			FROM outbox
+			WHERE published_at IS NULL AND next_attempt_at <= now()
+			ORDER BY message_id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox o
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due
+		WHERE o.message_id = due.message_id
+		RETURNING o.message_id,
+		          o.event_id,
+		          o.event_type,
+		          o.team_name,
+		          o.payload,
+		          o.attempts,
+		          o.next_attempt_at,
+		          o.last_error,
+		          o.delivered_sinks,
+		          o.created_at;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.OutboxMessage
+	for rows.Next() {
+		var m domain.OutboxMessage
+		err := rows.Scan(
+			&m.ID,
+			&m.EventID,
+			&m.EventType,
+			&m.TeamName,
+			&m.Payload,
+			&m.Attempts,
+			&m.NextAttemptAt,
+			&m.LastError,
+			&m.DeliveredSinks,
+			&m.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// UPDATE ... RETURNING не гарантирует порядок, а приёмникам важна последовательность событий.
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (r *OutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	const query = `
+		UPDATE outbox
+		SET published_at = now(),
+		    attempts = attempts + 1,
+		    last_error = ''
+		WHERE message_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id)
+	return err
+}
+
+func (r *OutboxRepo) MarkRetry(
+	ctx context.Context,
+	id int64,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+	deliveredSinks []string,
+) error {
+	const query = `
+		UPDATE outbox
+		SET attempts = $2,
+		    next_attempt_at = $3,
+		    last_error = $4,
+		    delivered_sinks = $5
+		WHERE message_id = $1;
+	`
+
+	if deliveredSinks == nil {
+		deliveredSinks = []string{}
+	}
+	_, err := r.db(ctx).Exec(ctx, query, id, attempts, nextAttemptAt, lastError, deliveredSinks)
+	return err
+}
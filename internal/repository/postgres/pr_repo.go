@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
 	"github.com/jackc/pgx/v5"
@@ -16,23 +17,29 @@ func NewPullRequestRepo(pool *pgxpool.Pool) *PullRequestRepo {
 	return &PullRequestRepo{pool: pool}
 }
 
+func (r *PullRequestRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
 func (r *PullRequestRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
 	const query = `
 		INSERT INTO pull_requests (
 			pull_request_id,
 			pull_request_name,
 			author_id,
+			reviewer_team,
 			status,
 			created_at
 		)
-		VALUES ($1, $2, $3, $4, $5)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
 		ON CONFLICT DO NOTHING;
 	`
 
-	cmd, err := r.pool.Exec(ctx, query,
+	cmd, err := r.db(ctx).Exec(ctx, query,
 		pr.ID,
 		pr.Name,
 		pr.AuthorID,
+		pr.ReviewerTeam,
 		pr.Status,
 		pr.CreatedAt,
 	)
@@ -49,19 +56,39 @@ func (r *PullRequestRepo) Create(ctx context.Context, pr *domain.PullRequest) er
 
 func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (domain.PullRequest, error) {
 	const query = `
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
+		SELECT pull_request_id, pull_request_name, author_id, COALESCE(reviewer_team, ''), status, created_at, merged_at, closed_at, stale_since
 		FROM pull_requests
 		WHERE pull_request_id = $1;
 	`
 
+	return r.getByID(ctx, query, id)
+}
+
+// GetByIDForUpdate читает PR с блокировкой строки до конца транзакции,
+// чтобы параллельные reassign/merge одного PR выполнялись по очереди.
+func (r *PullRequestRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	const query = `
+		SELECT pull_request_id, pull_request_name, author_id, COALESCE(reviewer_team, ''), status, created_at, merged_at, closed_at, stale_since
+		FROM pull_requests
+		WHERE pull_request_id = $1
+		FOR UPDATE;
+	`
+
+	return r.getByID(ctx, query, id)
+}
+
+func (r *PullRequestRepo) getByID(ctx context.Context, query, id string) (domain.PullRequest, error) {
 	var pr domain.PullRequest
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
 		&pr.ID,
 		&pr.Name,
 		&pr.AuthorID,
+		&pr.ReviewerTeam,
 		&pr.Status,
 		&pr.CreatedAt,
 		&pr.MergedAt,
+		&pr.ClosedAt,
+		&pr.StaleSince,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -76,9 +103,49 @@ func (r *PullRequestRepo) GetByID(ctx context.Context, id string) (domain.PullRe
 	}
 	pr.AssignedReviewers = reviewers
 
+	reviews, err := r.ListReviews(ctx, id)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	pr.Reviews = reviews
+
+	sync, err := r.reviewerSync(ctx, id)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	pr.ReviewerSync = sync
+
 	return pr, nil
 }
 
+// reviewerSync читает состояние выгрузки ревьюверов; nil, если PR не из внешней системы.
+func (r *PullRequestRepo) reviewerSync(ctx context.Context, prID string) (*domain.ReviewerSync, error) {
+	const query = `
+		SELECT pull_request_id, status, version, attempts, next_attempt_at, last_error, updated_at
+		FROM pr_reviewer_sync
+		WHERE pull_request_id = $1;
+	`
+
+	var s domain.ReviewerSync
+	err := r.db(ctx).QueryRow(ctx, query, prID).Scan(
+		&s.PullRequestID,
+		&s.Status,
+		&s.Version,
+		&s.Attempts,
+		&s.NextAttemptAt,
+		&s.LastError,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &s, nil
+}
+
 func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]string, error) {
 	const query = `
 		SELECT reviewer_id
@@ -86,7 +153,7 @@ func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]str
 		WHERE pull_request_id = $1;
 	`
 
-	rows, err := r.pool.Query(ctx, query, prID)
+	rows, err := r.db(ctx).Query(ctx, query, prID)
 	if err != nil {
 		return nil, err
 	}
@@ -107,11 +174,12 @@ func (r *PullRequestRepo) ListReviewers(ctx context.Context, prID string) ([]str
 func (r *PullRequestRepo) ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) error {
 	const query = `
 		UPDATE pull_request_reviewers
-		SET reviewer_id = $3
+		SET reviewer_id = $3,
+		    assigned_at = now()
 		WHERE pull_request_id = $1 AND reviewer_id = $2;
 	`
 
-	cmd, err := r.pool.Exec(ctx, query, prID, oldReviewerID, newReviewerID)
+	cmd, err := r.db(ctx).Exec(ctx, query, prID, oldReviewerID, newReviewerID)
 	if err != nil {
 		return err
 	}
@@ -120,7 +188,7 @@ func (r *PullRequestRepo) ReassignReviewer(ctx context.Context, prID, oldReviewe
 		return domain.ErrNotAssigned
 	}
 
-	return nil
+	return r.touch(ctx, prID)
 }
 
 func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
@@ -129,7 +197,7 @@ func (r *PullRequestRepo) RemoveReviewer(ctx context.Context, prID, reviewerID s
 		WHERE pull_request_id = $1 AND reviewer_id = $2;
 	`
 
-	_, err := r.pool.Exec(ctx, query, prID, reviewerID)
+	_, err := r.db(ctx).Exec(ctx, query, prID, reviewerID)
 	return err
 }
 
@@ -141,23 +209,41 @@ func (r *PullRequestRepo) AssignReviewers(ctx context.Context, prID string, revi
 	`
 
 	for _, reviewerID := range reviewerIDs {
-		if _, err := r.pool.Exec(ctx, query, prID, reviewerID); err != nil {
+		if _, err := r.db(ctx).Exec(ctx, query, prID, reviewerID); err != nil {
 			return err
 		}
 	}
 
-	return nil
+	if len(reviewerIDs) == 0 {
+		return nil
+	}
+	return r.touch(ctx, prID)
+}
+
+// touch отмечает активность по PR: отсчёт до пометки устаревшим начинается заново.
+func (r *PullRequestRepo) touch(ctx context.Context, prID string) error {
+	const query = `
+		UPDATE pull_requests
+		SET last_activity_at = now(),
+		    stale_since = NULL
+		WHERE pull_request_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, prID)
+	return err
 }
 
 func (r *PullRequestRepo) Merge(ctx context.Context, prID string) error {
 	const query = `
 		UPDATE pull_requests
 		SET status = 'MERGED',
-		    merged_at = COALESCE(merged_at, now())
+		    merged_at = COALESCE(merged_at, now()),
+		    last_activity_at = now(),
+		    stale_since = NULL
 		WHERE pull_request_id = $1;
 	`
 
-	cmd, err := r.pool.Exec(ctx, query, prID)
+	cmd, err := r.db(ctx).Exec(ctx, query, prID)
 	if err != nil {
 		return err
 	}
@@ -169,12 +255,98 @@ func (r *PullRequestRepo) Merge(ctx context.Context, prID string) error {
 	return nil
 }
 
+func (r *PullRequestRepo) SetStatus(ctx context.Context, prID string, status domain.PRStatus) error {
+	const query = `
+		UPDATE pull_requests
+		SET status = $2,
+		    closed_at = CASE WHEN $2 = 'CLOSED' THEN now() END,
+		    last_activity_at = now(),
+		    stale_since = NULL
+		WHERE pull_request_id = $1;
+	`
+
+	cmd, err := r.db(ctx).Exec(ctx, query, prID, string(status))
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	if status != domain.PullRequestStatusOpen {
+		return nil
+	}
+
+	// Пока PR был закрыт, ревью от ревьюверов не ждали — SLA после reopen считается заново.
+	const restartSLA = `
+		UPDATE pull_request_reviewers
+		SET assigned_at = now()
+		WHERE pull_request_id = $1;
+	`
+
+	_, err = r.db(ctx).Exec(ctx, restartSLA, prID)
+	return err
+}
+
+func (r *PullRequestRepo) SaveReview(ctx context.Context, review domain.ReviewDecision) error {
+	const query = `
+		INSERT INTO pull_request_reviews (pull_request_id, reviewer_id, decision, decided_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (pull_request_id, reviewer_id) DO UPDATE
+		SET decision = EXCLUDED.decision,
+		    decided_at = EXCLUDED.decided_at;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query,
+		review.PullRequestID,
+		review.ReviewerID,
+		string(review.Decision),
+		review.DecidedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.touch(ctx, review.PullRequestID)
+}
+
+func (r *PullRequestRepo) ListReviews(ctx context.Context, prID string) ([]domain.ReviewDecision, error) {
+	const query = `
+		SELECT rv.pull_request_id, rv.reviewer_id, rv.decision, rv.decided_at
+		FROM pull_request_reviews rv
+		JOIN pull_request_reviewers rr
+		      ON rr.pull_request_id = rv.pull_request_id AND rr.reviewer_id = rv.reviewer_id
+		WHERE rv.pull_request_id = $1
+		ORDER BY rv.decided_at;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.ReviewDecision
+	for rows.Next() {
+		var d domain.ReviewDecision
+		if err := rows.Scan(&d.PullRequestID, &d.ReviewerID, &d.Decision, &d.DecidedAt); err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, rows.Err()
+}
+
 func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
 	const query = `
 		SELECT pr.pull_request_id,
 		       pr.pull_request_name,
 		       pr.author_id,
-		       pr.status
+		       pr.status,
+		       pr.created_at,
+		       pr.stale_since
 		FROM pull_requests pr
 		JOIN pull_request_reviewers rr
 		      ON pr.pull_request_id = rr.pull_request_id
@@ -182,7 +354,7 @@ func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string)
 		ORDER BY pr.created_at;
 	`
 
-	rows, err := r.pool.Query(ctx, query, reviewerID)
+	rows, err := r.db(ctx).Query(ctx, query, reviewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +369,8 @@ func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string)
 			&pr.Name,
 			&pr.AuthorID,
 			&pr.Status,
+			&pr.CreatedAt,
+			&pr.StaleSince,
 		)
 		if err != nil {
 			return nil, err
@@ -206,3 +380,142 @@ func (r *PullRequestRepo) ListByReviewer(ctx context.Context, reviewerID string)
 
 	return res, nil
 }
+
+func (r *PullRequestRepo) OpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	const query = `
+		SELECT rr.reviewer_id, COUNT(*)
+		FROM pull_request_reviewers rr
+		JOIN pull_requests pr
+		      ON pr.pull_request_id = rr.pull_request_id
+		WHERE rr.reviewer_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY rr.reviewer_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make(map[string]int, len(reviewerIDs))
+	for rows.Next() {
+		var (
+			id    string
+			count int
+		)
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		res[id] = count
+	}
+
+	return res, rows.Err()
+}
+
+func (r *PullRequestRepo) ListOpenReviews(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error) {
+	const query = `
+		WITH locked AS (
+			SELECT pull_request_id, author_id, reviewer_team
+			FROM pull_requests
+			WHERE status = 'OPEN'
+			  AND pull_request_id IN (
+			      SELECT pull_request_id
+			      FROM pull_request_reviewers
+			      WHERE reviewer_id = ANY($1)
+			  )
+			ORDER BY pull_request_id
+			FOR UPDATE
+		)
+		SELECT l.pull_request_id,
+		       l.author_id,
+		       COALESCE(l.reviewer_team, a.team_name, ''),
+		       array_agg(rr.reviewer_id ORDER BY rr.assigned_at, rr.reviewer_id)
+		FROM locked l
+		JOIN pull_request_reviewers rr
+		      ON rr.pull_request_id = l.pull_request_id
+		LEFT JOIN users a
+		      ON a.user_id = l.author_id
+		GROUP BY l.pull_request_id, l.author_id, l.reviewer_team, a.team_name
+		ORDER BY l.pull_request_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.OpenReview
+	for rows.Next() {
+		var o domain.OpenReview
+		if err := rows.Scan(&o.PullRequestID, &o.AuthorID, &o.TeamName, &o.Reviewers); err != nil {
+			return nil, err
+		}
+		res = append(res, o)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *PullRequestRepo) ApplyReviewerChanges(ctx context.Context, changes []domain.ReviewerChange) error {
+	const (
+		removeQuery = `
+			DELETE FROM pull_request_reviewers r
+			USING unnest($1::text[], $2::text[]) AS c(pull_request_id, reviewer_id)
+			WHERE r.pull_request_id = c.pull_request_id
+			  AND r.reviewer_id = c.reviewer_id;
+		`
+		replaceQuery = `
+			UPDATE pull_request_reviewers r
+			SET reviewer_id = c.new_id,
+			    assigned_at = now()
+			FROM unnest($1::text[], $2::text[], $3::text[]) AS c(pull_request_id, old_id, new_id)
+			WHERE r.pull_request_id = c.pull_request_id
+			  AND r.reviewer_id = c.old_id;
+		`
+		touchQuery = `
+			UPDATE pull_requests
+			SET last_activity_at = now(),
+			    stale_since = NULL
+			WHERE pull_request_id = ANY($1);
+		`
+	)
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var (
+		removePRs, removeIDs       []string
+		replacePRs, oldIDs, newIDs []string
+		touched                    = make([]string, 0, len(changes))
+		seen                       = make(map[string]struct{}, len(changes))
+	)
+	for _, c := range changes {
+		if c.NewReviewerID == "" {
+			removePRs = append(removePRs, c.PullRequestID)
+			removeIDs = append(removeIDs, c.OldReviewerID)
+		} else {
+			replacePRs = append(replacePRs, c.PullRequestID)
+			oldIDs = append(oldIDs, c.OldReviewerID)
+			newIDs = append(newIDs, c.NewReviewerID)
+		}
+		if _, ok := seen[c.PullRequestID]; !ok {
+			seen[c.PullRequestID] = struct{}{}
+			touched = append(touched, c.PullRequestID)
+		}
+	}
+
+	if len(removePRs) > 0 {
+		if _, err := r.db(ctx).Exec(ctx, removeQuery, removePRs, removeIDs); err != nil {
+			return err
+		}
+	}
+	if len(replacePRs) > 0 {
+		if _, err := r.db(ctx).Exec(ctx, replaceQuery, replacePRs, oldIDs, newIDs); err != nil {
+			return err
+		}
+	}
+	_, err := r.db(ctx).Exec(ctx, touchQuery, touched)
+	return err
+}
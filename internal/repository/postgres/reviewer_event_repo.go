@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewerEventRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReviewerEventRepo(pool *pgxpool.Pool) *ReviewerEventRepo {
+	return &ReviewerEventRepo{pool: pool}
+}
+
+func (r *ReviewerEventRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *ReviewerEventRepo) Append(ctx context.Context, events ...domain.ReviewerEvent) error {
+	const query = `
+		INSERT INTO pull_request_reviewer_events (
+			pull_request_id,
+			event_type,
+			reviewer_id,
+			replaced_by,
+			actor_id,
+			reason,
+			created_at
+		)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7);
+	`
+
+	for _, e := range events {
+		_, err := r.db(ctx).Exec(ctx, query,
+			e.PullRequestID,
+			string(e.Type),
+			e.ReviewerID,
+			e.ReplacedBy,
+			e.ActorID,
+			e.Reason,
+			e.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *ReviewerEventRepo) ListByPR(ctx context.Context, prID string) ([]domain.ReviewerEvent, error) {
+	const query = `
+		SELECT event_id,
+		       pull_request_id,
+		       event_type,
+		       reviewer_id,
+		       COALESCE(replaced_by, ''),
+		       COALESCE(actor_id, ''),
+		       reason,
+		       created_at
+		FROM pull_request_reviewer_events
+		WHERE pull_request_id = $1
+		ORDER BY event_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, prID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ReviewerEvent, 0)
+	for rows.Next() {
+		var e domain.ReviewerEvent
+		err := rows.Scan(
+			&e.ID,
+			&e.PullRequestID,
+			&e.Type,
+			&e.ReviewerID,
+			&e.ReplacedBy,
+			&e.ActorID,
+			&e.Reason,
+			&e.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, e)
+	}
+
+	return res, rows.Err()
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReviewerSyncRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReviewerSyncRepo(pool *pgxpool.Pool) *ReviewerSyncRepo {
+	return &ReviewerSyncRepo{pool: pool}
+}
+
+func (r *ReviewerSyncRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *ReviewerSyncRepo) Request(ctx context.Context, prID string) error {
+	const query = `
+		INSERT INTO pr_reviewer_sync (pull_request_id)
+		VALUES ($1)
+		ON CONFLICT (pull_request_id) DO UPDATE
+		SET status = 'pending',
+		    version = pr_reviewer_sync.version + 1,
+		    attempts = 0,
+		    next_attempt_at = now(),
+		    last_error = '',
+		    updated_at = now();
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, prID)
+	return err
+}
+
+func (r *ReviewerSyncRepo) Get(ctx context.Context, prID string) (domain.ReviewerSync, error) {
+	const query = `
+		SELECT pull_request_id, status, version, attempts, next_attempt_at, last_error, updated_at
+		FROM pr_reviewer_sync
+		WHERE pull_request_id = $1;
+	`
+
+	var s domain.ReviewerSync
+	err := r.db(ctx).QueryRow(ctx, query, prID).Scan(
+		&s.PullRequestID,
+		&s.Status,
+		&s.Version,
+		&s.Attempts,
+		&s.NextAttemptAt,
+		&s.LastError,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.ReviewerSync{}, domain.ErrNotFound
+		}
+		return domain.ReviewerSync{}, err
+	}
+
+	return s, nil
+}
+
+func (r *ReviewerSyncRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.ReviewerSync, error) {
+	const query = `
+		WITH due AS (
+			SELECT pull_request_id
+			FROM pr_reviewer_sync
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE pr_reviewer_sync s
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due
+		WHERE s.pull_request_id = due.pull_request_id
+		RETURNING s.pull_request_id, s.status, s.version, s.attempts, s.next_attempt_at, s.last_error, s.updated_at;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.ReviewerSync
+	for rows.Next() {
+		var s domain.ReviewerSync
+		err := rows.Scan(
+			&s.PullRequestID,
+			&s.Status,
+			&s.Version,
+			&s.Attempts,
+			&s.NextAttemptAt,
+			&s.LastError,
+			&s.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, s)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *ReviewerSyncRepo) MarkSynced(ctx context.Context, prID string, version int64) error {
+	const query = `
+		UPDATE pr_reviewer_sync
+		SET status = 'synced',
+		    attempts = attempts + 1,
+		    last_error = '',
+		    updated_at = now()
+		WHERE pull_request_id = $1 AND version = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, prID, version)
+	return err
+}
+
+func (r *ReviewerSyncRepo) MarkRetry(
+	ctx context.Context,
+	prID string,
+	version int64,
+	attempts int,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	const query = `
+		UPDATE pr_reviewer_sync
+		SET attempts = $3,
+		    next_attempt_at = $4,
+		    last_error = $5,
+		    updated_at = now()
+		WHERE pull_request_id = $1 AND version = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, prID, version, attempts, nextAttemptAt, lastError)
+	return err
+}
+
+func (r *ReviewerSyncRepo) MarkFailed(ctx context.Context, prID string, version int64, attempts int, lastError string) error {
+	const query = `
+		UPDATE pr_reviewer_sync
+		SET status = 'failed',
+		    attempts = $3,
+		    last_error = $4,
+		    updated_at = now()
+		WHERE pull_request_id = $1 AND version = $2;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, prID, version, attempts, lastError)
+	return err
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SLARepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSLARepo(pool *pgxpool.Pool) *SLARepo {
+	return &SLARepo{pool: pool}
+}
+
+func (r *SLARepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+// ListOverdue берёт SLA команды PR: reviewer_team, а если её нет — команды автора. Решение, оставленное до переназначения, не считается.
+func (r *SLARepo) ListOverdue(ctx context.Context, now time.Time) ([]domain.OverdueReview, error) {
+	const query = `
+		SELECT rr.pull_request_id,
+		       pr.pull_request_name,
+		       rr.reviewer_id,
+		       rr.assigned_at,
+		       t.team_name,
+		       t.sla_hours,
+		       t.sla_working_hours,
+		       t.sla_auto_reassign,
+		       COALESCE(t.team_lead, '')
+		FROM pull_request_reviewers rr
+		JOIN pull_requests pr ON pr.pull_request_id = rr.pull_request_id
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN teams t ON t.team_name = COALESCE(pr.reviewer_team, a.team_name)
+		WHERE pr.status = 'OPEN'
+		  AND t.sla_hours > 0
+		  AND rr.assigned_at + make_interval(hours => t.sla_hours) <= $1
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM pull_request_reviews rv
+		      WHERE rv.pull_request_id = rr.pull_request_id
+		        AND rv.reviewer_id = rr.reviewer_id
+		        AND rv.decided_at >= rr.assigned_at
+		  )
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM review_sla_breaches b
+		      WHERE b.pull_request_id = rr.pull_request_id
+		        AND b.reviewer_id = rr.reviewer_id
+		        AND b.assigned_at = rr.assigned_at
+		  )
+		ORDER BY rr.assigned_at;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.OverdueReview, 0)
+	for rows.Next() {
+		var o domain.OverdueReview
+		err := rows.Scan(
+			&o.PullRequestID,
+			&o.PullRequestName,
+			&o.ReviewerID,
+			&o.AssignedAt,
+			&o.TeamName,
+			&o.SLAHours,
+			&o.SLAWorkingHours,
+			&o.SLAAutoReassign,
+			&o.TeamLead,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, o)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *SLARepo) RecordBreach(ctx context.Context, b *domain.SLABreach) (bool, error) {
+	const query = `
+		INSERT INTO review_sla_breaches (pull_request_id, reviewer_id, team_name, assigned_at, deadline, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (pull_request_id, reviewer_id, assigned_at) DO NOTHING
+		RETURNING breach_id;
+	`
+
+	err := r.db(ctx).QueryRow(ctx, query,
+		b.PullRequestID,
+		b.ReviewerID,
+		b.TeamName,
+		b.AssignedAt,
+		b.Deadline,
+		b.DetectedAt,
+	).Scan(&b.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *SLARepo) SetNewReviewer(ctx context.Context, breachID int64, newReviewerID string) error {
+	const query = `
+		UPDATE review_sla_breaches
+		SET new_reviewer_id = $2
+		WHERE breach_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, breachID, newReviewerID)
+	return err
+}
+
+func (r *SLARepo) ListBreaches(ctx context.Context, f domain.SLABreachFilter) ([]domain.SLABreach, error) {
+	const query = `
+		WITH breaches AS (
+			SELECT b.breach_id,
+			       b.pull_request_id,
+			       pr.pull_request_name,
+			       b.team_name,
+			       b.reviewer_id,
+			       b.assigned_at,
+			       b.deadline,
+			       b.detected_at,
+			       COALESCE(b.new_reviewer_id, '') AS new_reviewer_id,
+			       pr.status = 'OPEN'
+			       AND EXISTS (
+			           SELECT 1
+			           FROM pull_request_reviewers rr
+			           WHERE rr.pull_request_id = b.pull_request_id
+			             AND rr.reviewer_id = b.reviewer_id
+			             AND rr.assigned_at = b.assigned_at
+			       )
+			       AND NOT EXISTS (
+			           SELECT 1
+			           FROM pull_request_reviews rv
+			           WHERE rv.pull_request_id = b.pull_request_id
+			             AND rv.reviewer_id = b.reviewer_id
+			             AND rv.decided_at >= b.assigned_at
+			       ) AS is_open
+			FROM review_sla_breaches b
+			JOIN pull_requests pr ON pr.pull_request_id = b.pull_request_id
+			WHERE ($1 = '' OR b.team_name = $1)
+			  AND ($2 = '' OR b.reviewer_id = $2)
+			  AND ($3::timestamptz IS NULL OR b.detected_at >= $3)
+			  AND ($4::timestamptz IS NULL OR b.detected_at < $4)
+		)
+		SELECT breach_id,
+		       pull_request_id,
+		       pull_request_name,
+		       team_name,
+		       reviewer_id,
+		       assigned_at,
+		       deadline,
+		       detected_at,
+		       new_reviewer_id,
+		       is_open
+		FROM breaches
+		WHERE NOT $5 OR is_open
+		ORDER BY detected_at DESC, breach_id DESC
+		LIMIT $6;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query,
+		f.TeamName,
+		f.ReviewerID,
+		nullTime(f.From),
+		nullTime(f.To),
+		f.OpenOnly,
+		f.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.SLABreach, 0)
+	for rows.Next() {
+		var b domain.SLABreach
+		err := rows.Scan(
+			&b.ID,
+			&b.PullRequestID,
+			&b.PullRequestName,
+			&b.TeamName,
+			&b.ReviewerID,
+			&b.AssignedAt,
+			&b.Deadline,
+			&b.DetectedAt,
+			&b.NewReviewerID,
+			&b.Open,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, b)
+	}
+
+	return res, rows.Err()
+}
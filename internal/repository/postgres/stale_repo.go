@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StaleRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStaleRepo(pool *pgxpool.Pool) *StaleRepo {
+	return &StaleRepo{pool: pool}
+}
+
+func (r *StaleRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+// MarkStale и ListToClose берут политику команды PR: reviewer_team, а если её нет — команды автора.
+func (r *StaleRepo) MarkStale(ctx context.Context, now time.Time) ([]domain.StalePR, error) {
+	const query = `
+		UPDATE pull_requests pr
+		SET stale_since = $1
+		FROM users a, teams t
+		WHERE a.user_id = pr.author_id
+		  AND t.team_name = COALESCE(pr.reviewer_team, a.team_name)
+		  AND pr.status IN ('OPEN', 'DRAFT')
+		  AND pr.stale_since IS NULL
+		  AND t.stale_after_days > 0
+		  AND pr.last_activity_at <= $1 - make_interval(days => t.stale_after_days)
+		RETURNING pr.pull_request_id, t.team_name, pr.stale_since;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	return scanStalePRs(rows)
+}
+
+func (r *StaleRepo) ListToClose(ctx context.Context, now time.Time) ([]domain.StalePR, error) {
+	const query = `
+		SELECT pr.pull_request_id, t.team_name, pr.stale_since
+		FROM pull_requests pr
+		JOIN users a ON a.user_id = pr.author_id
+		JOIN teams t ON t.team_name = COALESCE(pr.reviewer_team, a.team_name)
+		WHERE pr.status IN ('OPEN', 'DRAFT')
+		  AND pr.stale_since IS NOT NULL
+		  AND t.stale_close_after_days > 0
+		  AND pr.stale_since <= $1 - make_interval(days => t.stale_close_after_days)
+		ORDER BY pr.stale_since;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, now)
+	if err != nil {
+		return nil, err
+	}
+	return scanStalePRs(rows)
+}
+
+func scanStalePRs(rows pgx.Rows) ([]domain.StalePR, error) {
+	defer rows.Close()
+
+	res := make([]domain.StalePR, 0)
+	for rows.Next() {
+		var pr domain.StalePR
+		if err := rows.Scan(&pr.PullRequestID, &pr.TeamName, &pr.StaleSince); err != nil {
+			return nil, err
+		}
+		res = append(res, pr)
+	}
+
+	return res, rows.Err()
+}
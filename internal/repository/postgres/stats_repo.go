@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StatsRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatsRepo(pool *pgxpool.Pool) *StatsRepo {
+	return &StatsRepo{pool: pool}
+}
+
+func (r *StatsRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+// ReviewerStats: назначения и передачи берутся из истории ревьюверов (замена считается
+// назначением нового ревьювера), merge — из текущего состава ревьюверов PR.
+func (r *StatsRepo) ReviewerStats(ctx context.Context, f domain.ReviewerStatsFilter) ([]domain.ReviewerStats, error) {
+	const query = `
+		WITH members AS (
+			SELECT user_id, username, COALESCE(team_name, '') AS team_name
+			FROM users
+			WHERE $1 = '' OR team_name = $1
+		),
+		history AS (
+			SELECT e.event_type, e.reviewer_id, e.replaced_by
+			FROM pull_request_reviewer_events e
+			WHERE ($2::timestamptz IS NULL OR e.created_at >= $2)
+			  AND ($3::timestamptz IS NULL OR e.created_at < $3)
+		),
+		assignments AS (
+			SELECT CASE WHEN event_type = 'assigned' THEN reviewer_id ELSE replaced_by END AS user_id,
+			       count(*) AS cnt
+			FROM history
+			WHERE event_type = 'assigned'
+			   OR (event_type = 'replaced' AND replaced_by IS NOT NULL)
+			GROUP BY 1
+		),
+		reassigned AS (
+			SELECT reviewer_id AS user_id, count(*) AS cnt
+			FROM history
+			WHERE event_type IN ('replaced', 'removed')
+			GROUP BY 1
+		),
+		open_reviews AS (
+			SELECT rr.reviewer_id AS user_id, count(*) AS cnt
+			FROM pull_request_reviewers rr
+			JOIN pull_requests pr ON pr.pull_request_id = rr.pull_request_id
+			WHERE pr.status = 'OPEN'
+			GROUP BY 1
+		),
+		merged AS (
+			SELECT rr.reviewer_id AS user_id,
+			       count(*) AS cnt,
+			       percentile_cont(0.5) WITHIN GROUP (
+			           ORDER BY extract(epoch FROM pr.merged_at - pr.created_at)
+			       ) AS median_seconds
+			FROM pull_request_reviewers rr
+			JOIN pull_requests pr ON pr.pull_request_id = rr.pull_request_id
+			WHERE pr.status = 'MERGED'
+			  AND pr.merged_at IS NOT NULL
+			  AND ($2::timestamptz IS NULL OR pr.merged_at >= $2)
+			  AND ($3::timestamptz IS NULL OR pr.merged_at < $3)
+			GROUP BY 1
+		)
+		SELECT m.user_id,
+		       m.username,
+		       m.team_name,
+		       COALESCE(a.cnt, 0),
+		       COALESCE(o.cnt, 0),
+		       COALESCE(mg.cnt, 0),
+		       COALESCE(ra.cnt, 0),
+		       COALESCE(mg.median_seconds, 0)
+		FROM members m
+		LEFT JOIN assignments a ON a.user_id = m.user_id
+		LEFT JOIN open_reviews o ON o.user_id = m.user_id
+		LEFT JOIN merged mg ON mg.user_id = m.user_id
+		LEFT JOIN reassigned ra ON ra.user_id = m.user_id
+		ORDER BY m.team_name, m.user_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, f.TeamName, nullTime(f.From), nullTime(f.To))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.ReviewerStats, 0)
+	for rows.Next() {
+		var (
+			s             domain.ReviewerStats
+			medianSeconds float64
+		)
+		err := rows.Scan(
+			&s.UserID,
+			&s.Username,
+			&s.TeamName,
+			&s.Assignments,
+			&s.OpenReviews,
+			&s.MergedReviews,
+			&s.ReassignedAway,
+			&medianSeconds,
+		)
+		if err != nil {
+			return nil, err
+		}
+		s.MedianTimeToMerge = time.Duration(medianSeconds * float64(time.Second))
+		res = append(res, s)
+	}
+
+	return res, rows.Err()
+}
@@ -17,14 +17,44 @@ func NewTeamRepo(pool *pgxpool.Pool) *TeamRepo {
 	return &TeamRepo{pool: pool}
 }
 
-func (r *TeamRepo) Create(ctx context.Context, name string) error {
+func (r *TeamRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *TeamRepo) Create(ctx context.Context, name string, settings domain.TeamSettings) error {
 	const query = `
-		INSERT INTO teams (team_name)
-		VALUES ($1)
+		INSERT INTO teams (
+			team_name,
+			assignment_strategy,
+			min_reviewers,
+			max_reviewers,
+			fallback_team,
+			required_approvals,
+			sla_hours,
+			sla_working_hours,
+			sla_auto_reassign,
+			team_lead,
+			stale_after_days,
+			stale_close_after_days
+		)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, NULLIF($10, ''), $11, $12)
 		ON CONFLICT DO NOTHING;
 	`
 
-	cmdTag, err := r.pool.Exec(ctx, query, name)
+	cmdTag, err := r.db(ctx).Exec(ctx, query,
+		name,
+		settings.AssignmentStrategy,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.FallbackTeam,
+		settings.RequiredApprovals,
+		settings.SLAHours,
+		settings.SLAWorkingHours,
+		settings.SLAAutoReassign,
+		settings.TeamLead,
+		settings.StaleAfterDays,
+		settings.StaleCloseAfterDays,
+	)
 	if err != nil {
 		return err
 	}
@@ -38,15 +68,30 @@ func (r *TeamRepo) Create(ctx context.Context, name string) error {
 
 func (r *TeamRepo) GetByName(ctx context.Context, name string) (domain.Team, error) {
 	const queryTeam = `
-		SELECT team_name
+		SELECT team_name, assignment_strategy, min_reviewers, max_reviewers, COALESCE(fallback_team, ''), required_approvals,
+		       sla_hours, sla_working_hours, sla_auto_reassign, COALESCE(team_lead, ''),
+		       stale_after_days, stale_close_after_days
 		FROM teams
 		WHERE team_name = $1;
 	`
 
-	row := r.pool.QueryRow(ctx, queryTeam, name)
+	row := r.db(ctx).QueryRow(ctx, queryTeam, name)
 
 	var team domain.Team
-	err := row.Scan(&team.Name)
+	err := row.Scan(
+		&team.Name,
+		&team.Settings.AssignmentStrategy,
+		&team.Settings.MinReviewers,
+		&team.Settings.MaxReviewers,
+		&team.Settings.FallbackTeam,
+		&team.Settings.RequiredApprovals,
+		&team.Settings.SLAHours,
+		&team.Settings.SLAWorkingHours,
+		&team.Settings.SLAAutoReassign,
+		&team.Settings.TeamLead,
+		&team.Settings.StaleAfterDays,
+		&team.Settings.StaleCloseAfterDays,
+	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return domain.Team{}, domain.ErrNotFound
@@ -55,12 +100,12 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (domain.Team, err
 	}
 
 	const queryMembers = `
-		SELECT user_id, username, is_active
+		SELECT user_id, username, is_active, review_weight, max_open_reviews, email
 		FROM users
 		WHERE team_name = $1;
 	`
 
-	rows, err := r.pool.Query(ctx, queryMembers, name)
+	rows, err := r.db(ctx).Query(ctx, queryMembers, name)
 	if err != nil {
 		return domain.Team{}, err
 	}
@@ -69,7 +114,7 @@ func (r *TeamRepo) GetByName(ctx context.Context, name string) (domain.Team, err
 	members := make([]domain.TeamMember, 0)
 	for rows.Next() {
 		var m domain.TeamMember
-		if err := rows.Scan(&m.ID, &m.Username, &m.IsActive); err != nil {
+		if err := rows.Scan(&m.ID, &m.Username, &m.IsActive, &m.ReviewWeight, &m.MaxOpenReviews, &m.Email); err != nil {
 			return domain.Team{}, err
 		}
 		members = append(members, m)
@@ -87,7 +132,7 @@ func (r *TeamRepo) List(ctx context.Context) ([]domain.Team, error) {
 		ORDER BY team_name;
 	`
 
-	rows, err := r.pool.Query(ctx, query)
+	rows, err := r.db(ctx).Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -118,3 +163,122 @@ func (r *TeamRepo) List(ctx context.Context) ([]domain.Team, error) {
 
 	return teams, nil
 }
+
+func (r *TeamRepo) RoundRobinCursor(ctx context.Context, name string) (string, error) {
+	const query = `
+		SELECT COALESCE(round_robin_cursor, '')
+		FROM teams
+		WHERE team_name = $1
+		FOR UPDATE;
+	`
+
+	var cursor string
+	if err := r.db(ctx).QueryRow(ctx, query, name).Scan(&cursor); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return cursor, nil
+}
+
+func (r *TeamRepo) SetRoundRobinCursor(ctx context.Context, name, userID string) error {
+	const query = `
+		UPDATE teams
+		SET round_robin_cursor = $2
+		WHERE team_name = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, name, userID)
+	return err
+}
+
+func (r *TeamRepo) GetSettings(ctx context.Context, name string) (domain.TeamSettings, error) {
+	const query = `
+		SELECT assignment_strategy, min_reviewers, max_reviewers, COALESCE(fallback_team, ''), required_approvals,
+		       sla_hours, sla_working_hours, sla_auto_reassign, COALESCE(team_lead, ''),
+		       stale_after_days, stale_close_after_days
+		FROM teams
+		WHERE team_name = $1;
+	`
+
+	var s domain.TeamSettings
+	err := r.db(ctx).QueryRow(ctx, query, name).Scan(
+		&s.AssignmentStrategy,
+		&s.MinReviewers,
+		&s.MaxReviewers,
+		&s.FallbackTeam,
+		&s.RequiredApprovals,
+		&s.SLAHours,
+		&s.SLAWorkingHours,
+		&s.SLAAutoReassign,
+		&s.TeamLead,
+		&s.StaleAfterDays,
+		&s.StaleCloseAfterDays,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TeamSettings{}, domain.ErrNotFound
+		}
+		return domain.TeamSettings{}, err
+	}
+
+	return s, nil
+}
+
+func (r *TeamRepo) UpdateSettings(ctx context.Context, name string, settings domain.TeamSettings) (domain.TeamSettings, error) {
+	const query = `
+		UPDATE teams
+		SET assignment_strategy = $2,
+		    min_reviewers       = $3,
+		    max_reviewers       = $4,
+		    fallback_team       = NULLIF($5, ''),
+		    required_approvals  = $6,
+		    sla_hours           = $7,
+		    sla_working_hours   = $8,
+		    sla_auto_reassign   = $9,
+		    team_lead           = NULLIF($10, ''),
+		    stale_after_days    = $11,
+		    stale_close_after_days = $12
+		WHERE team_name = $1
+		RETURNING assignment_strategy, min_reviewers, max_reviewers, COALESCE(fallback_team, ''), required_approvals,
+		          sla_hours, sla_working_hours, sla_auto_reassign, COALESCE(team_lead, ''),
+		          stale_after_days, stale_close_after_days;
+	`
+
+	var s domain.TeamSettings
+	err := r.db(ctx).QueryRow(ctx, query,
+		name,
+		settings.AssignmentStrategy,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		settings.FallbackTeam,
+		settings.RequiredApprovals,
+		settings.SLAHours,
+		settings.SLAWorkingHours,
+		settings.SLAAutoReassign,
+		settings.TeamLead,
+		settings.StaleAfterDays,
+		settings.StaleCloseAfterDays,
+	).Scan(
+		&s.AssignmentStrategy,
+		&s.MinReviewers,
+		&s.MaxReviewers,
+		&s.FallbackTeam,
+		&s.RequiredApprovals,
+		&s.SLAHours,
+		&s.SLAWorkingHours,
+		&s.SLAAutoReassign,
+		&s.TeamLead,
+		&s.StaleAfterDays,
+		&s.StaleCloseAfterDays,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TeamSettings{}, domain.ErrNotFound
+		}
+		return domain.TeamSettings{}, err
+	}
+
+	return s, nil
+}
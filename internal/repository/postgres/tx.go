@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Коды ошибок Postgres, которые означают гонку параллельных транзакций.
+const (
+	codeUniqueViolation      = "23505"
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+	codeLockNotAvailable     = "55P03"
+)
+
+type txKey struct{}
+
+// querier — общее подмножество методов pgxpool.Pool и pgx.Tx, которым пользуются репозитории.
+type querier interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// conn возвращает транзакцию из контекста, если она открыта через TxManager, иначе пул.
+func conn(ctx context.Context, pool *pgxpool.Pool) querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return pool
+}
+
+type TxManager struct {
+	pool *pgxpool.Pool
+}
+
+func NewTxManager(pool *pgxpool.Pool) *TxManager {
+	return &TxManager{pool: pool}
+}
+
+// WithinTx выполняет fn в транзакции: коммит, если fn вернула nil, иначе откат.
+// Вложенный вызов присоединяется к уже открытой транзакции.
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := m.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return mapConflict(err)
+	}
+
+	return mapConflict(tx.Commit(ctx))
+}
+
+// mapConflict превращает ошибки конкурентного доступа Postgres в domain.ErrConflict,
+// чтобы клиент получил CONFLICT и мог повторить запрос.
+func mapConflict(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case codeUniqueViolation,
+		codeSerializationFailure,
+		codeDeadlockDetected,
+		codeLockNotAvailable:
+		return fmt.Errorf("%w: %s", domain.ErrConflict, pgErr.Message)
+	}
+	return err
+}
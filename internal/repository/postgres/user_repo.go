@@ -17,33 +17,43 @@ func NewUserRepo(pool *pgxpool.Pool) *UserRepo {
 	return &UserRepo{pool: pool}
 }
 
+func (r *UserRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
 func (r *UserRepo) Upsert(ctx context.Context, u domain.User) error {
 	const query = `
-		INSERT INTO users (user_id, username, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (user_id, username, team_name, is_active, review_weight, max_open_reviews, email)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (user_id) DO UPDATE SET
-			username  = EXCLUDED.username,
-			team_name = EXCLUDED.team_name,
-			is_active = EXCLUDED.is_active;
+			username         = EXCLUDED.username,
+			team_name        = EXCLUDED.team_name,
+			is_active        = EXCLUDED.is_active,
+			review_weight    = EXCLUDED.review_weight,
+			max_open_reviews = EXCLUDED.max_open_reviews,
+			email            = EXCLUDED.email;
 	`
 
-	_, err := r.pool.Exec(ctx, query, u.ID, u.Username, u.TeamName, u.IsActive)
+	_, err := r.db(ctx).Exec(ctx, query, u.ID, u.Username, u.TeamName, u.IsActive, u.ReviewWeight, u.MaxOpenReviews, u.Email)
 	return err
 }
 
 func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error) {
 	const query = `
-		SELECT user_id, username, team_name, is_active
+		SELECT user_id, username, team_name, is_active, review_weight, max_open_reviews, email
 		FROM users
 		WHERE user_id = $1;
 	`
 
 	var u domain.User
-	err := r.pool.QueryRow(ctx, query, id).Scan(
+	err := r.db(ctx).QueryRow(ctx, query, id).Scan(
 		&u.ID,
 		&u.Username,
 		&u.TeamName,
 		&u.IsActive,
+		&u.ReviewWeight,
+		&u.MaxOpenReviews,
+		&u.Email,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -57,12 +67,20 @@ func (r *UserRepo) GetByID(ctx context.Context, id string) (domain.User, error)
 
 func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
 	const query = `
-		SELECT user_id, username, team_name, is_active
-		FROM users
-		WHERE team_name = $1 AND is_active = TRUE;
+		SELECT user_id, username, team_name, is_active, review_weight, max_open_reviews, email
+		FROM users u
+		WHERE team_name = $1
+		  AND is_active = TRUE
+		  AND NOT EXISTS (
+		      SELECT 1
+		      FROM user_absences a
+		      WHERE a.user_id = u.user_id
+		        AND now() >= a.starts_at
+		        AND now() < a.ends_at
+		  );
 	`
 
-	rows, err := r.pool.Query(ctx, query, teamName)
+	rows, err := r.db(ctx).Query(ctx, query, teamName)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +89,7 @@ func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]dom
 	var users []domain.User
 	for rows.Next() {
 		var u domain.User
-		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive); err != nil {
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.ReviewWeight, &u.MaxOpenReviews, &u.Email); err != nil {
 			return nil, err
 		}
 		users = append(users, u)
@@ -80,23 +98,79 @@ func (r *UserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]dom
 	return users, nil
 }
 
-func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
+func (r *UserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, bool, error) {
+	// prev читает строку до UPDATE под FOR UPDATE, поэтому из двух параллельных
+	// деактиваций только одна увидит wasActive = true.
 	const query = `
-		UPDATE users
+		UPDATE users u
 		SET is_active = $2
-		WHERE user_id = $1
-		RETURNING user_id, username, team_name, is_active;
+		FROM (
+			SELECT user_id, is_active
+			FROM users
+			WHERE user_id = $1
+			FOR UPDATE
+		) prev
+		WHERE u.user_id = prev.user_id
+		RETURNING u.user_id, u.username, u.team_name, u.is_active, u.review_weight, u.max_open_reviews, u.email, prev.is_active;
 	`
 
-	var u domain.User
-	err := r.pool.QueryRow(ctx, query, userID, isActive).
-		Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive)
+	var (
+		u         domain.User
+		wasActive bool
+	)
+	err := r.db(ctx).QueryRow(ctx, query, userID, isActive).
+		Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.ReviewWeight, &u.MaxOpenReviews, &u.Email, &wasActive)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.User{}, domain.ErrNotFound
+			return domain.User{}, false, domain.ErrNotFound
 		}
-		return domain.User{}, err
+		return domain.User{}, false, err
 	}
 
-	return u, nil
+	return u, wasActive, nil
+}
+
+func (r *UserRepo) DeactivateMany(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) ([]domain.User, map[string]bool, error) {
+	const query = `
+		UPDATE users u
+		SET is_active = FALSE
+		FROM (
+			SELECT user_id, is_active
+			FROM users
+			WHERE team_name = $1 AND user_id = ANY($2)
+			ORDER BY user_id
+			FOR UPDATE
+		) prev
+		WHERE u.user_id = prev.user_id
+		RETURNING u.user_id, u.username, u.team_name, u.is_active, u.review_weight, u.max_open_reviews, u.email, prev.is_active;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, teamName, userIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(userIDs))
+	wasActive := make(map[string]bool, len(userIDs))
+	for rows.Next() {
+		var (
+			u    domain.User
+			prev bool
+		)
+		if err := rows.Scan(&u.ID, &u.Username, &u.TeamName, &u.IsActive, &u.ReviewWeight, &u.MaxOpenReviews, &u.Email, &prev); err != nil {
+			return nil, nil, err
+		}
+		users = append(users, u)
+		wasActive[u.ID] = prev
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return users, wasActive, nil
 }
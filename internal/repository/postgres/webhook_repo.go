@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WebhookRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWebhookRepo(pool *pgxpool.Pool) *WebhookRepo {
+	return &WebhookRepo{pool: pool}
+}
+
+func (r *WebhookRepo) db(ctx context.Context) querier {
+	return conn(ctx, r.pool)
+}
+
+func (r *WebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	const query = `
+		INSERT INTO webhook_subscriptions (team_name, url, secret, event_types)
+		VALUES ($1, $2, $3, $4)
+		RETURNING subscription_id, created_at;
+	`
+
+	return r.db(ctx).QueryRow(ctx, query,
+		sub.TeamName,
+		sub.URL,
+		sub.Secret,
+		eventTypesToStrings(sub.EventTypes),
+	).Scan(&sub.ID, &sub.CreatedAt)
+}
+
+func (r *WebhookRepo) ListSubscriptions(ctx context.Context, teamName string) ([]domain.WebhookSubscription, error) {
+	const query = `
+		SELECT subscription_id, team_name, url, secret, event_types, created_at
+		FROM webhook_subscriptions
+		WHERE team_name = $1
+		ORDER BY subscription_id;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, teamName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := make([]domain.WebhookSubscription, 0)
+	for rows.Next() {
+		var (
+			sub   domain.WebhookSubscription
+			types []string
+		)
+		if err := rows.Scan(&sub.ID, &sub.TeamName, &sub.URL, &sub.Secret, &types, &sub.CreatedAt); err != nil {
+			return nil, err
+		}
+		for _, t := range types {
+			sub.EventTypes = append(sub.EventTypes, domain.EventType(t))
+		}
+		res = append(res, sub)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *WebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	const query = `
+		DELETE FROM webhook_subscriptions
+		WHERE subscription_id = $1;
+	`
+
+	cmd, err := r.db(ctx).Exec(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// Enqueue вставляет все доставки одним запросом; уже поставленные в очередь
+// (та же подписка и то же событие) пропускаются, поэтому повтор публикации безопасен.
+func (r *WebhookRepo) Enqueue(ctx context.Context, deliveries ...domain.WebhookDelivery) error {
+	const query = `
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+		SELECT * FROM unnest($1::bigint[], $2::text[], $3::text[], $4::bytea[])
+		ON CONFLICT (subscription_id, event_id) DO NOTHING;
+	`
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	var (
+		subscriptionIDs = make([]int64, len(deliveries))
+		eventIDs        = make([]string, len(deliveries))
+		eventTypes      = make([]string, len(deliveries))
+		payloads        = make([][]byte, len(deliveries))
+	)
+	for i, d := range deliveries {
+		subscriptionIDs[i] = d.SubscriptionID
+		eventIDs[i] = d.EventID
+		eventTypes[i] = string(d.EventType)
+		payloads[i] = d.Payload
+	}
+
+	_, err := r.db(ctx).Exec(ctx, query, subscriptionIDs, eventIDs, eventTypes, payloads)
+	return err
+}
+
+func (r *WebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	const query = `
+		WITH due AS (
+			SELECT delivery_id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= now()
+			ORDER BY next_attempt_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = now() + make_interval(secs => $2)
+		FROM due, webhook_subscriptions s
+		WHERE d.delivery_id = due.delivery_id
+		  AND s.subscription_id = d.subscription_id
+		RETURNING d.delivery_id,
+		          d.subscription_id,
+		          d.event_id,
+		          d.event_type,
+		          d.payload,
+		          d.status,
+		          d.attempts,
+		          d.next_attempt_at,
+		          d.last_error,
+		          s.url,
+		          s.secret;
+	`
+
+	rows, err := r.db(ctx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var res []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		err := rows.Scan(
+			&d.ID,
+			&d.SubscriptionID,
+			&d.EventID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastError,
+			&d.URL,
+			&d.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, d)
+	}
+
+	return res, rows.Err()
+}
+
+func (r *WebhookRepo) MarkDelivered(ctx context.Context, id int64) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'delivered',
+		    attempts = attempts + 1,
+		    last_error = '',
+		    delivered_at = now()
+		WHERE delivery_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id)
+	return err
+}
+
+func (r *WebhookRepo) MarkRetry(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET attempts = $2,
+		    next_attempt_at = $3,
+		    last_error = $4
+		WHERE delivery_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id, attempts, nextAttemptAt, lastError)
+	return err
+}
+
+func (r *WebhookRepo) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	const query = `
+		UPDATE webhook_deliveries
+		SET status = 'failed',
+		    attempts = $2,
+		    last_error = $3
+		WHERE delivery_id = $1;
+	`
+
+	_, err := r.db(ctx).Exec(ctx, query, id, attempts, lastError)
+	return err
+}
+
+func eventTypesToStrings(types []domain.EventType) []string {
+	res := make([]string, 0, len(types))
+	for _, t := range types {
+		res = append(res, string(t))
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// AccountService ведёт соответствие логинов во внешних системах пользователям сервиса.
+type AccountService struct {
+	repo     domain.ExternalAccountRepository
+	userRepo domain.UserRepository
+}
+
+func NewAccountService(repo domain.ExternalAccountRepository, userRepo domain.UserRepository) *AccountService {
+	return &AccountService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+func (s *AccountService) Link(ctx context.Context, account domain.ExternalAccount) (domain.ExternalAccount, error) {
+	if err := account.Validate(); err != nil {
+		return domain.ExternalAccount{}, err
+	}
+	account.Login = domain.NormalizeLogin(account.Login)
+
+	if _, err := s.userRepo.GetByID(ctx, account.UserID); err != nil {
+		return domain.ExternalAccount{}, err
+	}
+
+	if err := s.repo.Upsert(ctx, &account); err != nil {
+		return domain.ExternalAccount{}, err
+	}
+	return account, nil
+}
+
+func (s *AccountService) List(ctx context.Context, provider domain.CodeHost) ([]domain.ExternalAccount, error) {
+	if !provider.IsValid() {
+		return nil, fmt.Errorf("%w: unknown provider %q", domain.ErrInvalidInput, provider)
+	}
+	return s.repo.List(ctx, provider)
+}
+
+// ResolveUser возвращает user_id по логину или ErrNotFound, если логин не привязан.
+func (s *AccountService) ResolveUser(ctx context.Context, provider domain.CodeHost, login string) (string, error) {
+	return s.repo.GetUserID(ctx, provider, domain.NormalizeLogin(login))
+}
+
+// WithActor записывает инициатора внешнего события как автора изменений:
+// привязанного пользователя или, если логин не привязан, "<provider>:<login>".
+func (s *AccountService) WithActor(ctx context.Context, provider domain.CodeHost, login string) (context.Context, error) {
+	if login == "" {
+		return ctx, nil
+	}
+
+	userID, err := s.ResolveUser(ctx, provider, login)
+	switch {
+	case err == nil:
+		return domain.WithActor(ctx, userID), nil
+	case errors.Is(err, domain.ErrNotFound):
+		return domain.WithActor(ctx, string(provider)+":"+domain.NormalizeLogin(login)), nil
+	default:
+		return nil, err
+	}
+}
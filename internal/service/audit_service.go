@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditService struct {
+	repo domain.AuditRepository
+}
+
+func NewAuditService(repo domain.AuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record сохраняет запись о запросе. Хранится только хэш тела, само тело в журнал не попадает.
+func (s *AuditService) Record(ctx context.Context, entry domain.AuditEntry, payload []byte) error {
+	if entry.ActorID == "" {
+		entry.ActorID = domain.ActorFromContext(ctx)
+	}
+	entry.PayloadHash = hashPayload(payload)
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	return s.repo.Insert(ctx, entry)
+}
+
+func (s *AuditService) List(ctx context.Context, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("%w: from must be before to", domain.ErrInvalidInput)
+	}
+
+	switch {
+	case filter.Limit < 0 || filter.Limit > maxAuditLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxAuditLimit)
+	case filter.Limit == 0:
+		filter.Limit = defaultAuditLimit
+	}
+
+	return s.repo.List(ctx, filter)
+}
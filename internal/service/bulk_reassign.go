@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// reassignPool — кандидаты одной команды для массового переноса ревью. Состав, настройки
+// и позиция round-robin читаются один раз, дальше выбор идёт в памяти.
+type reassignPool struct {
+	team     string
+	settings domain.TeamSettings
+	// members — активные участники в порядке user_id.
+	members []domain.User
+	cursor  string
+	moved   bool
+}
+
+// bulkReassigner раскладывает ревью уходящих пользователей по тем же правилам, что и
+// reassignReviewer: сначала свободные участники команды, затем fallback-команда, затем
+// уже назначенный на PR ревьювер. Нагрузка ведётся в памяти и растёт с каждым назначением.
+type bulkReassigner struct {
+	s       *PRService
+	own     *reassignPool
+	backup  *reassignPool
+	load    map[string]int
+	leaving map[string]struct{}
+	rand    *rand.Rand
+}
+
+// ReassignReviewsInBulk переносит все OPEN ревью пользователей reviewerIDs из команды teamName.
+// Все PR, кандидаты и их нагрузка читаются заранее, изменения пишутся пачкой, поэтому число
+// запросов не зависит от количества PR. Транзакцией управляет вызывающий код.
+func (s *PRService) ReassignReviewsInBulk(
+	ctx context.Context,
+	teamName string,
+	reviewerIDs []string,
+	reason string,
+) ([]domain.ReviewReassignment, error) {
+	report := make([]domain.ReviewReassignment, 0)
+	if len(reviewerIDs) == 0 {
+		return report, nil
+	}
+
+	reviews, err := s.prRepo.ListOpenReviews(ctx, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return report, nil
+	}
+
+	b, err := s.newBulkReassigner(ctx, teamName, reviewerIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		changes []domain.ReviewerChange
+		events  = make(map[string][]domain.ReviewerEvent)
+		teams   []string
+	)
+	for _, review := range reviews {
+		onPR := make(map[string]struct{}, len(review.Reviewers))
+		for _, id := range review.Reviewers {
+			onPR[id] = struct{}{}
+		}
+
+		for _, oldID := range review.Reviewers {
+			if _, ok := b.leaving[oldID]; !ok {
+				continue
+			}
+
+			item := domain.ReviewReassignment{PullRequestID: review.PullRequestID, OldReviewerID: oldID}
+			newID, reused, err := b.pick(ctx, review.AuthorID, onPR)
+			if err != nil {
+				return nil, err
+			}
+			if newID == "" {
+				report = append(report, item)
+				continue
+			}
+			item.NewReviewerID = newID
+			report = append(report, item)
+
+			event := domain.ReviewerEvent{
+				PullRequestID: review.PullRequestID,
+				Type:          domain.ReviewerEventReplaced,
+				ReviewerID:    oldID,
+				ReplacedBy:    newID,
+				Reason:        reason,
+			}
+			change := domain.ReviewerChange{PullRequestID: review.PullRequestID, OldReviewerID: oldID, NewReviewerID: newID}
+			// Место занял уже назначенный ревьювер — старый просто снят.
+			if reused {
+				event.Type, event.ReplacedBy = domain.ReviewerEventRemoved, ""
+				change.NewReviewerID = ""
+			} else {
+				onPR[newID] = struct{}{}
+				b.load[newID]++
+			}
+			delete(onPR, oldID)
+
+			changes = append(changes, change)
+			if _, ok := events[review.TeamName]; !ok {
+				teams = append(teams, review.TeamName)
+			}
+			events[review.TeamName] = append(events[review.TeamName], event)
+		}
+	}
+
+	if err := s.prRepo.ApplyReviewerChanges(ctx, changes); err != nil {
+		return nil, err
+	}
+	for _, pool := range []*reassignPool{b.own, b.backup} {
+		if pool != nil && pool.moved {
+			if err := s.teamRepo.SetRoundRobinCursor(ctx, pool.team, pool.cursor); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, team := range teams {
+		if err := s.recordEvents(ctx, team, events[team]...); err != nil {
+			return nil, err
+		}
+	}
+
+	return report, nil
+}
+
+func (s *PRService) newBulkReassigner(ctx context.Context, teamName string, reviewerIDs []string) (*bulkReassigner, error) {
+	b := &bulkReassigner{
+		s:       s,
+		leaving: make(map[string]struct{}, len(reviewerIDs)),
+		rand:    newRand(),
+	}
+	for _, id := range reviewerIDs {
+		b.leaving[id] = struct{}{}
+	}
+
+	var err error
+	if b.own, err = s.reassignPool(ctx, teamName); err != nil {
+		return nil, err
+	}
+	if fallback := b.own.settings.FallbackTeam; fallback != "" && fallback != teamName {
+		if b.backup, err = s.reassignPool(ctx, fallback); err != nil {
+			return nil, err
+		}
+	}
+
+	var ids []string
+	for _, pool := range []*reassignPool{b.own, b.backup} {
+		if pool == nil {
+			continue
+		}
+		for _, u := range pool.members {
+			ids = append(ids, u.ID)
+		}
+	}
+	if b.load, err = s.prRepo.OpenReviewCounts(ctx, ids); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (s *PRService) reassignPool(ctx context.Context, teamName string) (*reassignPool, error) {
+	settings, err := s.teamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	active, err := s.userRepo.ListActiveByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := &reassignPool{team: teamName, settings: settings}
+	for _, u := range active {
+		if u.IsActive {
+			pool.members = append(pool.members, u)
+		}
+	}
+	sort.Slice(pool.members, func(i, j int) bool {
+		return pool.members[i].ID < pool.members[j].ID
+	})
+
+	if settings.AssignmentStrategy == domain.AssignmentStrategyRoundRobin {
+		if pool.cursor, err = s.teamRepo.RoundRobinCursor(ctx, teamName); err != nil {
+			return nil, err
+		}
+	}
+	return pool, nil
+}
+
+// pick выбирает замену на PR; reused — выбран ревьювер, который уже стоит на этом PR.
+// Пустой id означает, что замены нет.
+func (b *bulkReassigner) pick(ctx context.Context, authorID string, onPR map[string]struct{}) (string, bool, error) {
+	free := func(u domain.User) bool {
+		if u.ID == authorID {
+			return false
+		}
+		if _, ok := b.leaving[u.ID]; ok {
+			return false
+		}
+		if _, ok := onPR[u.ID]; ok {
+			return false
+		}
+		return u.MaxOpenReviews <= 0 || b.load[u.ID] < u.MaxOpenReviews
+	}
+
+	for _, pool := range []*reassignPool{b.own, b.backup} {
+		if pool == nil {
+			continue
+		}
+		u, ok, err := b.choose(ctx, pool, pool.filter(free))
+		if err != nil || ok {
+			return u.ID, false, err
+		}
+	}
+
+	assigned := b.own.filter(func(u domain.User) bool {
+		_, ok := onPR[u.ID]
+		_, gone := b.leaving[u.ID]
+		return ok && !gone && u.ID != authorID
+	})
+	u, ok, err := b.choose(ctx, b.own, assigned)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	return u.ID, true, nil
+}
+
+func (p *reassignPool) filter(keep func(domain.User) bool) []domain.User {
+	var res []domain.User
+	for _, u := range p.members {
+		if keep(u) {
+			res = append(res, u)
+		}
+	}
+	return res
+}
+
+// choose применяет стратегию команды к кандидатам, не обращаясь к базе:
+// round-robin двигает позицию в памяти, least_loaded смотрит на нагрузку в памяти.
+func (b *bulkReassigner) choose(ctx context.Context, pool *reassignPool, candidates []domain.User) (domain.User, bool, error) {
+	if len(candidates) == 0 {
+		return domain.User{}, false, nil
+	}
+
+	switch pool.settings.AssignmentStrategy {
+	case domain.AssignmentStrategyRoundRobin:
+		// candidates уже упорядочены по user_id.
+		picked := candidates[0]
+		for _, u := range candidates {
+			if u.ID > pool.cursor {
+				picked = u
+				break
+			}
+		}
+		pool.cursor, pool.moved = picked.ID, true
+		return picked, true, nil
+	case domain.AssignmentStrategyLeastLoaded:
+		ordered := append([]domain.User(nil), candidates...)
+		b.rand.Shuffle(len(ordered), func(i, j int) {
+			ordered[i], ordered[j] = ordered[j], ordered[i]
+		})
+		sort.SliceStable(ordered, func(i, j int) bool {
+			return b.load[ordered[i].ID] < b.load[ordered[j].ID]
+		})
+		return ordered[0], true, nil
+	default:
+		picked, err := b.s.selector(pool.settings.AssignmentStrategy).Select(ctx, pool.team, candidates, 1)
+		if err != nil || len(picked) == 0 {
+			return domain.User{}, false, err
+		}
+		return picked[0], true, nil
+	}
+}
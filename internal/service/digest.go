@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// Mailer отправляет письмо; реализация — SMTP в пакете notify.
+type Mailer interface {
+	Send(ctx context.Context, msg domain.EmailMessage) error
+}
+
+type DigestConfig struct {
+	// SendAt — время отправки от начала суток в Location.
+	SendAt        time.Duration
+	Location      *time.Location
+	CheckInterval time.Duration
+}
+
+// digestBuckets — группы PR по возрасту, от самых старых: они важнее.
+var digestBuckets = []struct {
+	title  string
+	minAge time.Duration
+}{
+	{"Waiting more than a week", 7 * 24 * time.Hour},
+	{"Waiting 3-7 days", 3 * 24 * time.Hour},
+	{"Waiting 1-3 days", 24 * time.Hour},
+	{"Opened in the last 24 hours", 0},
+}
+
+// DigestWorker раз в день отправляет каждому активному пользователю с email список OPEN PR,
+// где он всё ещё ревьювер. Пользователям без открытых ревью письмо не отправляется.
+// Если экземпляр упадёт между Claim и отправкой, дайджест за этот день пропадёт —
+// для напоминания это приемлемо, а дубли писем хуже.
+type DigestWorker struct {
+	repo   domain.DigestRepository
+	prRepo domain.PullRequestRepository
+	mailer Mailer
+	cfg    DigestConfig
+}
+
+func NewDigestWorker(
+	repo domain.DigestRepository,
+	prRepo domain.PullRequestRepository,
+	mailer Mailer,
+	cfg DigestConfig,
+) *DigestWorker {
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	return &DigestWorker{
+		repo:   repo,
+		prRepo: prRepo,
+		mailer: mailer,
+		cfg:    cfg,
+	}
+}
+
+func (w *DigestWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.SendDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("email digest: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue отправляет дайджесты за сегодняшний день, если время отправки наступило,
+// и возвращает число отправленных писем. Повторный вызов в тот же день ничего не шлёт.
+func (w *DigestWorker) SendDue(ctx context.Context, now time.Time) (int, error) {
+	local := now.In(w.cfg.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, w.cfg.Location)
+	if local.Sub(day) < w.cfg.SendAt {
+		return 0, nil
+	}
+
+	users, err := w.repo.ListRecipients(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list digest recipients: %w", err)
+	}
+
+	sent := 0
+	for _, u := range users {
+		ok, err := w.send(ctx, u, day, now)
+		if err != nil {
+			log.Printf("email digest for %s: %v", u.ID, err)
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+func (w *DigestWorker) send(ctx context.Context, user domain.User, day, now time.Time) (bool, error) {
+	claimed, err := w.repo.Claim(ctx, user.ID, day)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	prs, err := w.prRepo.ListByReviewer(ctx, user.ID)
+	if err != nil {
+		return false, w.release(ctx, user.ID, day, err)
+	}
+	open := make([]domain.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status == domain.PullRequestStatusOpen {
+			open = append(open, pr)
+		}
+	}
+	if len(open) == 0 {
+		return false, nil
+	}
+
+	if err := w.mailer.Send(ctx, buildDigest(user, open, now)); err != nil {
+		return false, w.release(ctx, user.ID, day, err)
+	}
+	return true, nil
+}
+
+// release возвращает дайджест в очередь после неудачи, чтобы он ушёл на следующей проверке.
+func (w *DigestWorker) release(ctx context.Context, userID string, day time.Time, cause error) error {
+	if err := w.repo.Release(ctx, userID, day); err != nil {
+		return fmt.Errorf("%w (release: %v)", cause, err)
+	}
+	return cause
+}
+
+func buildDigest(user domain.User, prs []domain.PullRequest, now time.Time) domain.EmailMessage {
+	name := user.Username
+	if name == "" {
+		name = user.ID
+	}
+
+	groups := make([][]domain.PullRequest, len(digestBuckets))
+	for _, pr := range prs {
+		age := now.Sub(pr.CreatedAt)
+		for i, b := range digestBuckets {
+			if age >= b.minAge || i == len(digestBuckets)-1 {
+				groups[i] = append(groups[i], pr)
+				break
+			}
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\n", name)
+	fmt.Fprintf(&b, "%s waiting for your review.\n", pluralPRs(len(prs)))
+	for i, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d):\n", digestBuckets[i].title, len(group))
+		for _, pr := range group {
+			fmt.Fprintf(&b, "  - %s %q by %s, opened %s\n", pr.ID, pr.Name, pr.AuthorID, pr.CreatedAt.Format("2006-01-02"))
+		}
+	}
+
+	return domain.EmailMessage{
+		To:      user.Email,
+		Subject: "Review digest: " + pluralPRs(len(prs)) + " waiting",
+		Body:    b.String(),
+	}
+}
+
+func pluralPRs(n int) string {
+	if n == 1 {
+		return "1 pull request"
+	}
+	return fmt.Sprintf("%d pull requests", n)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeDigestRepo struct {
+	users   []domain.User
+	claimed map[string]bool
+}
+
+func (r *fakeDigestRepo) ListRecipients(ctx context.Context) ([]domain.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeDigestRepo) Claim(ctx context.Context, userID string, day time.Time) (bool, error) {
+	key := userID + "/" + day.Format(time.DateOnly)
+	if r.claimed[key] {
+		return false, nil
+	}
+	r.claimed[key] = true
+	return true, nil
+}
+
+func (r *fakeDigestRepo) Release(ctx context.Context, userID string, day time.Time) error {
+	delete(r.claimed, userID+"/"+day.Format(time.DateOnly))
+	return nil
+}
+
+type fakeMailer struct {
+	sent []domain.EmailMessage
+	err  error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg domain.EmailMessage) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func newDigestFixture(now time.Time) (*DigestWorker, *fakeDigestRepo, *fakeMailer) {
+	repo := &fakeDigestRepo{
+		users: []domain.User{
+			{ID: "u1", Username: "Alice", Email: "alice@example.com"},
+			{ID: "u2", Username: "Bob", Email: "bob@example.com"},
+		},
+		claimed: map[string]bool{},
+	}
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-old":    {ID: "pr-old", Name: "Old", AuthorID: "a", Status: domain.PullRequestStatusOpen, CreatedAt: now.Add(-10 * 24 * time.Hour)},
+			"pr-mid":    {ID: "pr-mid", Name: "Mid", AuthorID: "a", Status: domain.PullRequestStatusOpen, CreatedAt: now.Add(-2 * 24 * time.Hour)},
+			"pr-new":    {ID: "pr-new", Name: "New", AuthorID: "a", Status: domain.PullRequestStatusOpen, CreatedAt: now.Add(-time.Hour)},
+			"pr-merged": {ID: "pr-merged", Name: "Done", AuthorID: "a", Status: domain.PullRequestStatusMerged, CreatedAt: now.Add(-time.Hour)},
+		},
+		reviewers: map[string][]string{
+			"pr-old":    {"u1"},
+			"pr-mid":    {"u1"},
+			"pr-new":    {"u1"},
+			"pr-merged": {"u1", "u2"},
+		},
+	}
+	mailer := &fakeMailer{}
+	w := NewDigestWorker(repo, prRepo, mailer, DigestConfig{SendAt: 9 * time.Hour, Location: time.UTC})
+	return w, repo, mailer
+}
+
+func TestDigestWorker_GroupsOpenPRsByAge(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 9, 30, 0, 0, time.UTC)
+	w, _, mailer := newDigestFixture(now)
+
+	sent, err := w.SendDue(ctx, now)
+	if err != nil {
+		t.Fatalf("SendDue error: %v", err)
+	}
+	// u2 ревьюит только смёрженный PR — письма быть не должно.
+	if sent != 1 || len(mailer.sent) != 1 {
+		t.Fatalf("expected one digest, got %d: %+v", sent, mailer.sent)
+	}
+
+	msg := mailer.sent[0]
+	if msg.To != "alice@example.com" || msg.Subject != "Review digest: 3 pull requests waiting" {
+		t.Fatalf("unexpected message headers: %+v", msg)
+	}
+	if strings.Contains(msg.Body, "pr-merged") {
+		t.Fatalf("merged PR must not be listed:\n%s", msg.Body)
+	}
+	old := strings.Index(msg.Body, "Waiting more than a week (1):\n  - pr-old")
+	mid := strings.Index(msg.Body, "Waiting 1-3 days (1):\n  - pr-mid")
+	fresh := strings.Index(msg.Body, "Opened in the last 24 hours (1):\n  - pr-new")
+	if old < 0 || mid < old || fresh < mid {
+		t.Fatalf("unexpected grouping:\n%s", msg.Body)
+	}
+}
+
+func TestDigestWorker_SendsOncePerDayAfterSendAt(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 8, 59, 0, 0, time.UTC)
+	w, _, mailer := newDigestFixture(now)
+
+	if sent, _ := w.SendDue(ctx, now); sent != 0 {
+		t.Fatalf("digest must not be sent before SendAt, sent %d", sent)
+	}
+	if sent, _ := w.SendDue(ctx, now.Add(time.Minute)); sent != 1 {
+		t.Fatalf("expected digest at SendAt, sent %d", sent)
+	}
+	if sent, _ := w.SendDue(ctx, now.Add(time.Hour)); sent != 0 {
+		t.Fatalf("digest must be sent once a day, sent %d", sent)
+	}
+	if sent, _ := w.SendDue(ctx, now.Add(24*time.Hour+time.Minute)); sent != 1 {
+		t.Fatalf("expected digest on the next day, sent %d", sent)
+	}
+	if len(mailer.sent) != 2 {
+		t.Fatalf("expected two digests total, got %d", len(mailer.sent))
+	}
+}
+
+func TestDigestWorker_ReleasesClaimOnMailerFailure(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)
+	w, repo, mailer := newDigestFixture(now)
+
+	mailer.err = errors.New("connection refused")
+	if sent, err := w.SendDue(ctx, now); err != nil || sent != 0 {
+		t.Fatalf("mailer failure must be logged, not returned: sent=%d err=%v", sent, err)
+	}
+	if repo.claimed["u1/2025-03-10"] {
+		t.Fatal("claim must be released so the digest is retried")
+	}
+
+	mailer.err = nil
+	if sent, _ := w.SendDue(ctx, now.Add(time.Minute)); sent != 1 {
+		t.Fatalf("expected digest on retry, sent %d", sent)
+	}
+}
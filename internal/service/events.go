@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// Полезная нагрузка событий — это формат, который видят внешние подписчики.
+
+type PREventData struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	Name              string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	Status            string     `json:"status"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+}
+
+type ReviewerAssignedData struct {
+	PullRequestID string `json:"pull_request_id"`
+	ReviewerID    string `json:"reviewer_id"`
+	Reason        string `json:"reason"`
+}
+
+type ReviewerReassignedData struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	// NewReviewerID пуст, если ревьювер снят, а новый не назначен.
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+type UserDeactivatedData struct {
+	UserID   string `json:"user_id"`
+	TeamName string `json:"team_name"`
+}
+
+type ReviewSLABreachedData struct {
+	PullRequestID string    `json:"pull_request_id"`
+	ReviewerID    string    `json:"reviewer_id"`
+	AssignedAt    time.Time `json:"assignedAt"`
+	Deadline      time.Time `json:"deadline"`
+	// NewReviewerID заполнен, если ревью передано другому автоматически.
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+	TeamLead      string `json:"team_lead,omitempty"`
+}
+
+func prEventData(pr domain.PullRequest) PREventData {
+	return PREventData{
+		PullRequestID:     pr.ID,
+		Name:              pr.Name,
+		AuthorID:          pr.AuthorID,
+		Status:            string(pr.Status),
+		AssignedReviewers: append([]string(nil), pr.AssignedReviewers...),
+		CreatedAt:         pr.CreatedAt,
+		MergedAt:          pr.MergedAt,
+	}
+}
+
+// eventEnvelope — JSON, в котором событие уходит наружу: в outbox, вебхуки и прочие приёмники.
+type eventEnvelope struct {
+	ID         string           `json:"id"`
+	Type       domain.EventType `json:"type"`
+	TeamName   string           `json:"team_name"`
+	OccurredAt time.Time        `json:"occurred_at"`
+	Data       any              `json:"data"`
+}
+
+func marshalEvent(event domain.Event) ([]byte, error) {
+	payload, err := json.Marshal(eventEnvelope{
+		ID:         event.ID,
+		Type:       event.Type,
+		TeamName:   event.TeamName,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal event %s: %w", event.Type, err)
+	}
+	return payload, nil
+}
+
+// unmarshalEvent восстанавливает событие из конверта; Data остаётся сырым JSON.
+func unmarshalEvent(payload []byte) (domain.Event, error) {
+	var env struct {
+		eventEnvelope
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &env); err != nil {
+		return domain.Event{}, fmt.Errorf("unmarshal event: %w", err)
+	}
+	return domain.Event{
+		ID:         env.ID,
+		Type:       env.Type,
+		TeamName:   env.TeamName,
+		OccurredAt: env.OccurredAt,
+		Data:       env.Data,
+	}, nil
+}
+
+func newEvent(t domain.EventType, teamName string, data any) domain.Event {
+	return domain.Event{
+		ID:         newEventID(),
+		Type:       t,
+		TeamName:   teamName,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// publishEvents отдаёт события издателю; nil-издатель означает, что события никуда не уходят.
+func publishEvents(ctx context.Context, publisher domain.EventPublisher, events ...domain.Event) error {
+	if publisher == nil {
+		return nil
+	}
+	for _, e := range events {
+		if err := publisher.Publish(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	GitHubSignatureHeader = "X-Hub-Signature-256"
+	GitHubEventHeader     = "X-GitHub-Event"
+)
+
+// GitHubService переводит вебхуки GitHub о pull request в операции PRService.
+type GitHubService struct {
+	prService *PRService
+	accounts  *AccountService
+	secret    string
+}
+
+func NewGitHubService(prService *PRService, accounts *AccountService, secret string) *GitHubService {
+	return &GitHubService{
+		prService: prService,
+		accounts:  accounts,
+		secret:    secret,
+	}
+}
+
+// GitHubPullRequestID — идентификатор PR из GitHub в сервисе: "github:owner/repo#N".
+func GitHubPullRequestID(repoFullName string, number int) string {
+	return domain.ExternalPR{Host: domain.CodeHostGitHub, Project: repoFullName, Number: number}.ID()
+}
+
+// VerifySignature проверяет X-Hub-Signature-256. Без настроенного секрета не проходит ни один запрос.
+func (s *GitHubService) VerifySignature(body []byte, signature string) bool {
+	if s.secret == "" || signature == "" {
+		return false
+	}
+	return hmac.Equal([]byte(SignWebhookPayload(s.secret, body)), []byte(signature))
+}
+
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+type gitHubPullRequestEvent struct {
+	Action      string `json:"action"`
+	PullRequest struct {
+		Number int        `json:"number"`
+		Title  string     `json:"title"`
+		Draft  bool       `json:"draft"`
+		Merged bool       `json:"merged"`
+		User   gitHubUser `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+	Sender gitHubUser `json:"sender"`
+}
+
+// HandleEvent обрабатывает одно событие; event — значение заголовка X-GitHub-Event.
+func (s *GitHubService) HandleEvent(ctx context.Context, event string, body []byte) (IntegrationResult, error) {
+	if event != "pull_request" {
+		return integrationIgnored("", "event %q is not handled", event), nil
+	}
+
+	var payload gitHubPullRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return IntegrationResult{}, fmt.Errorf("%w: malformed pull_request payload", domain.ErrInvalidInput)
+	}
+	if payload.Repository.FullName == "" || payload.PullRequest.Number <= 0 {
+		return IntegrationResult{}, fmt.Errorf("%w: repository and pull request number are required", domain.ErrInvalidInput)
+	}
+
+	prID := GitHubPullRequestID(payload.Repository.FullName, payload.PullRequest.Number)
+	ctx, err := s.accounts.WithActor(ctx, domain.CodeHostGitHub, payload.Sender.Login)
+	if err != nil {
+		return IntegrationResult{}, err
+	}
+
+	switch payload.Action {
+	case "opened":
+		return s.open(ctx, prID, payload)
+	case "ready_for_review":
+		_, err = s.prService.MarkReady(ctx, prID, nil)
+	case "closed":
+		if payload.PullRequest.Merged {
+			_, err = s.prService.RecordExternalMerge(ctx, prID)
+		} else {
+			_, err = s.prService.ClosePR(ctx, prID)
+		}
+	case "reopened":
+		_, err = s.prService.ReopenPR(ctx, prID)
+	default:
+		return integrationIgnored(prID, "action %q is not handled", payload.Action), nil
+	}
+
+	return integrationOutcome(prID, err)
+}
+
+func (s *GitHubService) open(ctx context.Context, prID string, payload gitHubPullRequestEvent) (IntegrationResult, error) {
+	login := payload.PullRequest.User.Login
+	authorID, err := s.accounts.ResolveUser(ctx, domain.CodeHostGitHub, login)
+	if errors.Is(err, domain.ErrNotFound) {
+		return integrationIgnored(prID, "github user %q is not linked to a user", login), nil
+	}
+	if err != nil {
+		return IntegrationResult{}, err
+	}
+
+	pr := &domain.PullRequest{
+		ID:       prID,
+		Name:     payload.PullRequest.Title,
+		AuthorID: authorID,
+	}
+	_, err = s.prService.CreatePR(ctx, pr, CreatePROptions{Draft: payload.PullRequest.Draft})
+	return integrationOutcome(prID, err)
+}
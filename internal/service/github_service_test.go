@@ -0,0 +1,33 @@
+package service
+
+import "testing"
+
+func TestGitHubService_VerifySignature(t *testing.T) {
+	// Пример из документации GitHub по проверке доставок вебхуков.
+	const (
+		secret    = "It's a Secret to Everybody"
+		payload   = "Hello, World!"
+		signature = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	)
+
+	svc := NewGitHubService(nil, nil, secret)
+	if !svc.VerifySignature([]byte(payload), signature) {
+		t.Fatal("expected documented signature to be valid")
+	}
+	if svc.VerifySignature([]byte(payload+" "), signature) {
+		t.Fatal("signature must not match a modified payload")
+	}
+	if svc.VerifySignature([]byte(payload), "") {
+		t.Fatal("missing signature must be rejected")
+	}
+
+	if NewGitHubService(nil, nil, "").VerifySignature([]byte(payload), SignWebhookPayload("", []byte(payload))) {
+		t.Fatal("requests must be rejected while the secret is not configured")
+	}
+}
+
+func TestGitHubPullRequestID(t *testing.T) {
+	if got := GitHubPullRequestID("acme/backend", 42); got != "github:acme/backend#42" {
+		t.Fatalf("unexpected id %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	GitLabTokenHeader = "X-Gitlab-Token"
+	GitLabEventHeader = "X-Gitlab-Event"
+
+	gitLabMergeRequestHook = "Merge Request Hook"
+)
+
+// GitLabService переводит Merge Request Hook из GitLab в операции PRService.
+// Обрабатываются только проекты из projectTeams (path_with_namespace -> команда).
+type GitLabService struct {
+	prService    *PRService
+	accounts     *AccountService
+	teamRepo     domain.TeamRepository
+	token        string
+	projectTeams map[string]string
+}
+
+func NewGitLabService(
+	prService *PRService,
+	accounts *AccountService,
+	teamRepo domain.TeamRepository,
+	token string,
+	projectTeams map[string]string,
+) *GitLabService {
+	// Пути проектов в GitLab регистронезависимы.
+	normalized := make(map[string]string, len(projectTeams))
+	for project, team := range projectTeams {
+		normalized[strings.ToLower(strings.TrimSpace(project))] = strings.TrimSpace(team)
+	}
+
+	return &GitLabService{
+		prService:    prService,
+		accounts:     accounts,
+		teamRepo:     teamRepo,
+		token:        token,
+		projectTeams: normalized,
+	}
+}
+
+// GitLabMergeRequestID — идентификатор MR из GitLab в сервисе: "gitlab:group/project!IID".
+func GitLabMergeRequestID(project string, iid int) string {
+	return domain.ExternalPR{Host: domain.CodeHostGitLab, Project: project, Number: iid}.ID()
+}
+
+// VerifyToken сравнивает X-Gitlab-Token с настроенным секретом. Без секрета не проходит ни один запрос.
+func (s *GitLabService) VerifyToken(token string) bool {
+	if s.token == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(s.token), []byte(token)) == 1
+}
+
+type gitLabBoolChange struct {
+	Previous bool `json:"previous"`
+	Current  bool `json:"current"`
+}
+
+type gitLabMergeRequestEvent struct {
+	ObjectKind string `json:"object_kind"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID            int    `json:"iid"`
+		Title          string `json:"title"`
+		Action         string `json:"action"`
+		Draft          bool   `json:"draft"`
+		WorkInProgress bool   `json:"work_in_progress"`
+	} `json:"object_attributes"`
+	// Старые версии GitLab присылают work_in_progress вместо draft.
+	Changes struct {
+		Draft          *gitLabBoolChange `json:"draft"`
+		WorkInProgress *gitLabBoolChange `json:"work_in_progress"`
+	} `json:"changes"`
+}
+
+func (e gitLabMergeRequestEvent) isDraft() bool {
+	return e.ObjectAttributes.Draft || e.ObjectAttributes.WorkInProgress
+}
+
+// markedReady — снят ли в этом update признак черновика.
+func (e gitLabMergeRequestEvent) markedReady() bool {
+	for _, c := range []*gitLabBoolChange{e.Changes.Draft, e.Changes.WorkInProgress} {
+		if c != nil && c.Previous && !c.Current {
+			return true
+		}
+	}
+	return false
+}
+
+// HandleEvent обрабатывает одно событие; event — значение заголовка X-Gitlab-Event.
+func (s *GitLabService) HandleEvent(ctx context.Context, event string, body []byte) (IntegrationResult, error) {
+	if event != gitLabMergeRequestHook {
+		return integrationIgnored("", "event %q is not handled", event), nil
+	}
+
+	var payload gitLabMergeRequestEvent
+	if err := json.Unmarshal(body, &payload); err != nil || payload.ObjectKind != "merge_request" {
+		return IntegrationResult{}, fmt.Errorf("%w: malformed merge_request payload", domain.ErrInvalidInput)
+	}
+	project := payload.Project.PathWithNamespace
+	if project == "" || payload.ObjectAttributes.IID <= 0 {
+		return IntegrationResult{}, fmt.Errorf("%w: project and merge request iid are required", domain.ErrInvalidInput)
+	}
+
+	prID := GitLabMergeRequestID(project, payload.ObjectAttributes.IID)
+	teamName, ok := s.projectTeams[strings.ToLower(project)]
+	if !ok {
+		return integrationIgnored(prID, "project %q is not mapped to a team", project), nil
+	}
+
+	ctx, err := s.accounts.WithActor(ctx, domain.CodeHostGitLab, payload.User.Username)
+	if err != nil {
+		return IntegrationResult{}, err
+	}
+
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		return s.open(ctx, prID, teamName, payload)
+	case "update":
+		if !payload.markedReady() {
+			return integrationIgnored(prID, "update does not change merge request status"), nil
+		}
+		_, err = s.prService.MarkReady(ctx, prID, nil)
+	case "merge":
+		_, err = s.prService.RecordExternalMerge(ctx, prID)
+	case "close":
+		_, err = s.prService.ClosePR(ctx, prID)
+	case "reopen":
+		_, err = s.prService.ReopenPR(ctx, prID)
+	default:
+		return integrationIgnored(prID, "action %q is not handled", payload.ObjectAttributes.Action), nil
+	}
+
+	return integrationOutcome(prID, err)
+}
+
+func (s *GitLabService) open(
+	ctx context.Context,
+	prID string,
+	teamName string,
+	payload gitLabMergeRequestEvent,
+) (IntegrationResult, error) {
+	username := payload.User.Username
+	authorID, err := s.resolveAuthor(ctx, teamName, username)
+	if errors.Is(err, domain.ErrNotFound) {
+		return integrationIgnored(prID, "gitlab user %q is neither linked nor a member of team %s", username, teamName), nil
+	}
+	if err != nil {
+		return IntegrationResult{}, err
+	}
+
+	pr := &domain.PullRequest{
+		ID:       prID,
+		Name:     payload.ObjectAttributes.Title,
+		AuthorID: authorID,
+	}
+	_, err = s.prService.CreatePR(ctx, pr, CreatePROptions{Draft: payload.isDraft(), ReviewerTeam: teamName})
+	return integrationOutcome(prID, err)
+}
+
+// resolveAuthor ищет автора MR: сначала по привязке в external_accounts,
+// затем среди участников команды проекта с тем же username.
+func (s *GitLabService) resolveAuthor(ctx context.Context, teamName, username string) (string, error) {
+	userID, err := s.accounts.ResolveUser(ctx, domain.CodeHostGitLab, username)
+	if !errors.Is(err, domain.ErrNotFound) {
+		return userID, err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range team.Members {
+		if strings.EqualFold(m.Username, username) {
+			return m.ID, nil
+		}
+	}
+	return "", domain.ErrNotFound
+}
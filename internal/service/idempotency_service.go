@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	// idempotencyTTL — сколько хранится ответ по ключу; после этого ключ можно использовать заново.
+	idempotencyTTL = 24 * time.Hour
+	// idempotencyLease — сколько ключ считается занятым запросом, который ещё не ответил. С запасом
+	// больше самого долгого обработчика (массовая деактивация ограничена 30 секундами).
+	idempotencyLease = 2 * time.Minute
+)
+
+type IdempotencyService struct {
+	repo domain.IdempotencyRepository
+}
+
+func NewIdempotencyService(repo domain.IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{repo: repo}
+}
+
+// Begin занимает ключ под запрос. Возвращает сохранённую запись, если ответ
+// на такой же запрос уже есть и его нужно просто повторить; nil — запрос надо выполнить.
+func (s *IdempotencyService) Begin(ctx context.Context, key, endpoint string, body []byte) (*domain.IdempotencyRecord, error) {
+	rec := domain.IdempotencyRecord{
+		Key:         key,
+		Endpoint:    endpoint,
+		RequestHash: hashPayload(body),
+	}
+
+	existing, reserved, err := s.repo.Reserve(ctx, rec, idempotencyTTL, idempotencyLease)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	if existing.RequestHash != rec.RequestHash {
+		return nil, domain.ErrIdempotencyKeyReused
+	}
+	if existing.InProgress() {
+		return nil, fmt.Errorf("%w: request with this idempotency key is still in progress", domain.ErrConflict)
+	}
+
+	return &existing, nil
+}
+
+// Finish сохраняет ответ. Ответы 5xx и 409 не запоминаются: ключ освобождается,
+// и повтор выполнит запрос заново.
+func (s *IdempotencyService) Finish(ctx context.Context, key, endpoint string, statusCode int, body []byte) error {
+	if statusCode >= http.StatusInternalServerError || statusCode == http.StatusConflict {
+		return s.repo.Release(ctx, key, endpoint)
+	}
+	return s.repo.Complete(ctx, key, endpoint, statusCode, body)
+}
+
+// Release освобождает ключ без сохранения ответа, например если обработчик запаниковал.
+func (s *IdempotencyService) Release(ctx context.Context, key, endpoint string) error {
+	return s.repo.Release(ctx, key, endpoint)
+}
+
+// hashPayload — sha256 тела запроса в hex, общий для идемпотентности и аудита.
+func hashPayload(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
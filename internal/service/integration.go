@@ -0,0 +1,45 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// IntegrationResult — чем закончилась обработка входящего события внешней системы.
+// Неинтересные и неразобранные события не считаются ошибкой: отправитель не должен их повторять.
+type IntegrationResult struct {
+	Status        string
+	PullRequestID string
+	Reason        string
+}
+
+const (
+	IntegrationProcessed = "processed"
+	IntegrationIgnored   = "ignored"
+)
+
+func integrationIgnored(prID, reason string, args ...any) IntegrationResult {
+	return IntegrationResult{
+		Status:        IntegrationIgnored,
+		PullRequestID: prID,
+		Reason:        fmt.Sprintf(reason, args...),
+	}
+}
+
+// integrationOutcome превращает результат операции над PR в ответ отправителю события.
+func integrationOutcome(prID string, err error) (IntegrationResult, error) {
+	switch {
+	case err == nil:
+		return IntegrationResult{Status: IntegrationProcessed, PullRequestID: prID}, nil
+	// Отправители повторяют доставку при таймауте — повторное открытие ничего не меняет.
+	case errors.Is(err, domain.ErrPRExists):
+		return integrationIgnored(prID, "pull request already exists"), nil
+	// PR, открытые до подключения интеграции, сервису неизвестны — это не ошибка отправителя.
+	case errors.Is(err, domain.ErrNotFound):
+		return integrationIgnored(prID, "pull request is not tracked"), nil
+	default:
+		return IntegrationResult{}, err
+	}
+}
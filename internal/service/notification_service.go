@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// maxNotificationTemplate — ограничение на длину шаблона; Slack всё равно обрежет длинный текст.
+const maxNotificationTemplate = 4000
+
+var defaultNotificationTemplates = map[domain.NotificationKind]string{
+	domain.NotificationAssigned: `{{.Mention}}, you were assigned to review "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) by {{.PullRequest.AuthorID}}`,
+	domain.NotificationReplaced: `{{.Mention}}, you were removed from "{{.PullRequest.Name}}" ({{.PullRequest.ID}})` +
+		`{{if .ReplacedBy}}, {{.ReplacedBy}} reviews it now{{end}}`,
+	domain.NotificationMerged: `{{.Mention}}, "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) was merged`,
+	domain.NotificationSLABreach: `{{.Mention}}, {{.Reviewer}} has not reviewed "{{.PullRequest.Name}}" ({{.PullRequest.ID}}) within the team SLA` +
+		`{{if .ReplacedBy}}; the review was passed to {{.ReplacedBy}}{{end}}`,
+}
+
+// NotificationData — данные, доступные в шаблоне сообщения.
+type NotificationData struct {
+	Kind string
+	Team string
+	User struct {
+		ID       string
+		Username string
+	}
+	// Mention — упоминание в Slack (<@U0123>), если известен slack_user_id, иначе username.
+	Mention     string
+	PullRequest struct {
+		ID       string
+		Name     string
+		AuthorID string
+		Status   string
+	}
+	// Reviewer — ревьювер, нарушивший SLA (для sla_breach).
+	Reviewer string
+	// ReplacedBy — новый ревьювер для replaced и sla_breach; пусто, если замены не было.
+	ReplacedBy string
+	Reason     string
+}
+
+// sampleNotificationData — на этих данных шаблон проверяется при сохранении.
+func sampleNotificationData(kind domain.NotificationKind, team string) NotificationData {
+	var d NotificationData
+	d.Kind = string(kind)
+	d.Team = team
+	d.User.ID = "u1"
+	d.User.Username = "alice"
+	d.Mention = "<@U0123>"
+	d.PullRequest.ID = "pr-1"
+	d.PullRequest.Name = "Add search"
+	d.PullRequest.AuthorID = "u2"
+	d.PullRequest.Status = string(domain.PullRequestStatusOpen)
+	d.Reviewer = "u4"
+	d.ReplacedBy = "u3"
+	d.Reason = "manual"
+	return d
+}
+
+// TeamNotificationTemplate — действующий шаблон команды; Custom=false — используется встроенный.
+type TeamNotificationTemplate struct {
+	domain.NotificationTemplate
+	Custom bool
+}
+
+// NotificationService хранит настройки уведомлений пользователей и шаблоны сообщений команд.
+type NotificationService struct {
+	repo     domain.NotificationRepository
+	userRepo domain.UserRepository
+	teamRepo domain.TeamRepository
+}
+
+func NewNotificationService(
+	repo domain.NotificationRepository,
+	userRepo domain.UserRepository,
+	teamRepo domain.TeamRepository,
+) *NotificationService {
+	return &NotificationService{
+		repo:     repo,
+		userRepo: userRepo,
+		teamRepo: teamRepo,
+	}
+}
+
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	return s.preferences(ctx, userID)
+}
+
+func (s *NotificationService) SetPreferences(
+	ctx context.Context,
+	prefs domain.NotificationPreferences,
+) (domain.NotificationPreferences, error) {
+	if err := prefs.Validate(); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	if _, err := s.userRepo.GetByID(ctx, prefs.UserID); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	kinds := make([]domain.NotificationKind, 0, len(prefs.Kinds))
+	seen := make(map[domain.NotificationKind]bool, len(prefs.Kinds))
+	for _, k := range prefs.Kinds {
+		if !seen[k] {
+			seen[k] = true
+			kinds = append(kinds, k)
+		}
+	}
+	prefs.Kinds = kinds
+
+	if err := s.repo.UpsertPreferences(ctx, &prefs); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+	return prefs, nil
+}
+
+func (s *NotificationService) preferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.DefaultNotificationPreferences(userID), nil
+	}
+	return prefs, err
+}
+
+// ListTemplates возвращает шаблоны команды для всех видов уведомлений, включая встроенные.
+func (s *NotificationService) ListTemplates(ctx context.Context, teamName string) ([]TeamNotificationTemplate, error) {
+	if _, err := s.teamRepo.GetSettings(ctx, teamName); err != nil {
+		return nil, err
+	}
+
+	custom, err := s.repo.ListTemplates(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	byKind := make(map[domain.NotificationKind]domain.NotificationTemplate, len(custom))
+	for _, t := range custom {
+		byKind[t.Kind] = t
+	}
+
+	res := make([]TeamNotificationTemplate, 0, len(domain.AllNotificationKinds()))
+	for _, kind := range domain.AllNotificationKinds() {
+		if t, ok := byKind[kind]; ok {
+			res = append(res, TeamNotificationTemplate{NotificationTemplate: t, Custom: true})
+			continue
+		}
+		res = append(res, defaultTemplate(teamName, kind))
+	}
+	return res, nil
+}
+
+// SetTemplate сохраняет шаблон команды; пустой шаблон возвращает встроенный.
+func (s *NotificationService) SetTemplate(
+	ctx context.Context,
+	tpl domain.NotificationTemplate,
+) (TeamNotificationTemplate, error) {
+	if !tpl.Kind.IsValid() {
+		return TeamNotificationTemplate{}, fmt.Errorf("%w: unknown notification event %q", domain.ErrInvalidInput, tpl.Kind)
+	}
+	if _, err := s.teamRepo.GetSettings(ctx, tpl.TeamName); err != nil {
+		return TeamNotificationTemplate{}, err
+	}
+
+	if strings.TrimSpace(tpl.Template) == "" {
+		if err := s.repo.DeleteTemplate(ctx, tpl.TeamName, tpl.Kind); err != nil {
+			return TeamNotificationTemplate{}, err
+		}
+		return defaultTemplate(tpl.TeamName, tpl.Kind), nil
+	}
+
+	if len(tpl.Template) > maxNotificationTemplate {
+		return TeamNotificationTemplate{}, fmt.Errorf("%w: template must be at most %d bytes",
+			domain.ErrInvalidInput, maxNotificationTemplate)
+	}
+	if _, err := renderNotification(tpl.Template, sampleNotificationData(tpl.Kind, tpl.TeamName)); err != nil {
+		return TeamNotificationTemplate{}, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+	}
+
+	if err := s.repo.UpsertTemplate(ctx, &tpl); err != nil {
+		return TeamNotificationTemplate{}, err
+	}
+	return TeamNotificationTemplate{NotificationTemplate: tpl, Custom: true}, nil
+}
+
+// Render собирает текст уведомления по шаблону команды или встроенному.
+func (s *NotificationService) Render(ctx context.Context, data NotificationData) (string, error) {
+	kind := domain.NotificationKind(data.Kind)
+
+	if data.Team != "" {
+		tpl, err := s.repo.GetTemplate(ctx, data.Team, kind)
+		switch {
+		case err == nil:
+			text, err := renderNotification(tpl.Template, data)
+			if err == nil {
+				return text, nil
+			}
+			// Шаблон проверяется при сохранении, но на реальных данных всё же может упасть —
+			// тогда лучше отправить встроенный текст, чем не отправить ничего.
+			log.Printf("notification template %s/%s: %v", data.Team, kind, err)
+		case !errors.Is(err, domain.ErrNotFound):
+			return "", err
+		}
+	}
+
+	return renderNotification(defaultNotificationTemplates[kind], data)
+}
+
+func defaultTemplate(teamName string, kind domain.NotificationKind) TeamNotificationTemplate {
+	return TeamNotificationTemplate{NotificationTemplate: domain.NotificationTemplate{
+		TeamName: teamName,
+		Kind:     kind,
+		Template: defaultNotificationTemplates[kind],
+	}}
+}
+
+func renderNotification(text string, data NotificationData) (string, error) {
+	tpl, err := template.New(data.Kind).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("execute template: %w", err)
+	}
+	return strings.TrimSpace(b.String()), nil
+}
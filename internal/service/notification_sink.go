@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// Notifier доставляет уведомление пользователю (Slack, почта и т.п.).
+// Ошибку с методом Permanent() bool == true повторять бесполезно — например, webhook удалён.
+type Notifier interface {
+	Notify(ctx context.Context, n domain.Notification) error
+}
+
+func isPermanent(err error) bool {
+	var p interface{ Permanent() bool }
+	return errors.As(err, &p) && p.Permanent()
+}
+
+// NotificationSink — приёмник outbox: превращает события о ревьюверах и merge в личные уведомления.
+// Если доставка одному из получателей временно не удалась, outbox повторит событие целиком,
+// и остальные получатели могут получить сообщение повторно.
+type NotificationSink struct {
+	notifications *NotificationService
+	prRepo        domain.PullRequestRepository
+	userRepo      domain.UserRepository
+	notifier      Notifier
+}
+
+func NewNotificationSink(
+	notifications *NotificationService,
+	prRepo domain.PullRequestRepository,
+	userRepo domain.UserRepository,
+	notifier Notifier,
+) *NotificationSink {
+	return &NotificationSink{
+		notifications: notifications,
+		prRepo:        prRepo,
+		userRepo:      userRepo,
+		notifier:      notifier,
+	}
+}
+
+func (s *NotificationSink) Name() string { return "notify" }
+
+// notificationRecipient — кому и о чём сообщить по одному событию.
+type notificationRecipient struct {
+	userID     string
+	kind       domain.NotificationKind
+	reviewer   string
+	replacedBy string
+	reason     string
+}
+
+func (s *NotificationSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	event, err := unmarshalEvent(msg.Payload)
+	if err != nil {
+		return err
+	}
+	raw, _ := event.Data.(json.RawMessage)
+
+	var (
+		prID       string
+		recipients []notificationRecipient
+	)
+	switch event.Type {
+	case domain.EventReviewerAssigned:
+		var d ReviewerAssignedData
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("decode %s: %w", event.Type, err)
+		}
+		prID = d.PullRequestID
+		recipients = append(recipients, notificationRecipient{userID: d.ReviewerID, kind: domain.NotificationAssigned, reason: d.Reason})
+	case domain.EventReviewerReassigned:
+		var d ReviewerReassignedData
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("decode %s: %w", event.Type, err)
+		}
+		prID = d.PullRequestID
+		if d.NewReviewerID != "" {
+			recipients = append(recipients, notificationRecipient{userID: d.NewReviewerID, kind: domain.NotificationAssigned, reason: d.Reason})
+		}
+		recipients = append(recipients, notificationRecipient{
+			userID:     d.OldReviewerID,
+			kind:       domain.NotificationReplaced,
+			replacedBy: d.NewReviewerID,
+			reason:     d.Reason,
+		})
+	case domain.EventPRMerged:
+		var d PREventData
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("decode %s: %w", event.Type, err)
+		}
+		prID = d.PullRequestID
+		for _, userID := range append([]string{d.AuthorID}, d.AssignedReviewers...) {
+			recipients = append(recipients, notificationRecipient{userID: userID, kind: domain.NotificationMerged})
+		}
+	case domain.EventReviewSLABreached:
+		var d ReviewSLABreachedData
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("decode %s: %w", event.Type, err)
+		}
+		prID = d.PullRequestID
+		if d.TeamLead != "" {
+			recipients = append(recipients, notificationRecipient{
+				userID:     d.TeamLead,
+				kind:       domain.NotificationSLABreach,
+				reviewer:   d.ReviewerID,
+				replacedBy: d.NewReviewerID,
+				reason:     domain.ReviewerEventReasonSLABreach,
+			})
+		}
+	default:
+		return nil
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	pr, err := s.prRepo.GetByID(ctx, prID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, r := range recipients {
+		if err := s.notify(ctx, event.TeamName, pr, r); err != nil {
+			if !isPermanent(err) {
+				return err
+			}
+			log.Printf("notification %s for %s on %s dropped: %v", r.kind, r.userID, pr.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *NotificationSink) notify(
+	ctx context.Context,
+	teamName string,
+	pr domain.PullRequest,
+	r notificationRecipient,
+) error {
+	prefs, err := s.notifications.preferences(ctx, r.userID)
+	if err != nil {
+		return err
+	}
+	if !prefs.Wants(r.kind) {
+		return nil
+	}
+
+	user, err := s.userRepo.GetByID(ctx, r.userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var data NotificationData
+	data.Kind = string(r.kind)
+	data.Team = teamName
+	data.User.ID = user.ID
+	data.User.Username = user.Username
+	data.Mention = mention(user, prefs)
+	data.PullRequest.ID = pr.ID
+	data.PullRequest.Name = pr.Name
+	data.PullRequest.AuthorID = pr.AuthorID
+	data.PullRequest.Status = string(pr.Status)
+	data.Reviewer = r.reviewer
+	data.ReplacedBy = r.replacedBy
+	data.Reason = r.reason
+
+	text, err := s.notifications.Render(ctx, data)
+	if err != nil {
+		return err
+	}
+
+	return s.notifier.Notify(ctx, domain.Notification{
+		Kind:   r.kind,
+		UserID: user.ID,
+		Text:   text,
+		Target: prefs.SlackWebhookURL,
+	})
+}
+
+func mention(user domain.User, prefs domain.NotificationPreferences) string {
+	switch {
+	case prefs.SlackUserID != "":
+		return "<@" + prefs.SlackUserID + ">"
+	case user.Username != "":
+		return user.Username
+	}
+	return user.ID
+}
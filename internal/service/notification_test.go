@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeNotificationRepo struct {
+	prefs     map[string]domain.NotificationPreferences
+	templates map[domain.NotificationKind]string
+}
+
+func (r *fakeNotificationRepo) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	p, ok := r.prefs[userID]
+	if !ok {
+		return domain.NotificationPreferences{}, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (r *fakeNotificationRepo) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	r.prefs[prefs.UserID] = *prefs
+	return nil
+}
+
+func (r *fakeNotificationRepo) ListTemplates(ctx context.Context, teamName string) ([]domain.NotificationTemplate, error) {
+	return nil, nil
+}
+
+func (r *fakeNotificationRepo) GetTemplate(
+	ctx context.Context,
+	teamName string,
+	kind domain.NotificationKind,
+) (domain.NotificationTemplate, error) {
+	text, ok := r.templates[kind]
+	if !ok {
+		return domain.NotificationTemplate{}, domain.ErrNotFound
+	}
+	return domain.NotificationTemplate{TeamName: teamName, Kind: kind, Template: text}, nil
+}
+
+func (r *fakeNotificationRepo) UpsertTemplate(ctx context.Context, tpl *domain.NotificationTemplate) error {
+	r.templates[tpl.Kind] = tpl.Template
+	return nil
+}
+
+func (r *fakeNotificationRepo) DeleteTemplate(ctx context.Context, teamName string, kind domain.NotificationKind) error {
+	delete(r.templates, kind)
+	return nil
+}
+
+type permanentTestError struct{}
+
+func (permanentTestError) Error() string   { return "channel_is_archived" }
+func (permanentTestError) Permanent() bool { return true }
+
+type fakeNotifier struct {
+	sent []domain.Notification
+	fail map[string]error
+}
+
+func (n *fakeNotifier) Notify(ctx context.Context, msg domain.Notification) error {
+	if err := n.fail[msg.UserID]; err != nil {
+		return err
+	}
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+func newNotificationFixture() (*NotificationSink, *fakeNotificationRepo, *fakeNotifier) {
+	userRepo := &userRepoFake{usersByID: map[string]domain.User{
+		"author": {ID: "author", Username: "Author", TeamName: "core"},
+		"r1":     {ID: "r1", Username: "R1", TeamName: "core"},
+		"r2":     {ID: "r2", Username: "R2", TeamName: "core"},
+	}}
+	prRepo := &prRepoFake{prs: map[string]domain.PullRequest{
+		"pr-1": {ID: "pr-1", Name: "Add search", AuthorID: "author", AssignedReviewers: []string{"r2"}},
+	}}
+	repo := &fakeNotificationRepo{
+		prefs:     map[string]domain.NotificationPreferences{},
+		templates: map[domain.NotificationKind]string{},
+	}
+	notifier := &fakeNotifier{fail: map[string]error{}}
+
+	svc := NewNotificationService(repo, userRepo, &fakeTeamRepo{})
+	return NewNotificationSink(svc, prRepo, userRepo, notifier), repo, notifier
+}
+
+func outboxMessage(t *testing.T, event domain.Event) domain.OutboxMessage {
+	t.Helper()
+	payload, err := marshalEvent(event)
+	if err != nil {
+		t.Fatalf("marshalEvent error: %v", err)
+	}
+	return domain.OutboxMessage{EventID: event.ID, EventType: event.Type, Payload: payload}
+}
+
+func TestNotificationSink_Reassigned(t *testing.T) {
+	ctx := context.Background()
+	sink, repo, notifier := newNotificationFixture()
+	repo.prefs["r1"] = domain.NotificationPreferences{UserID: "r1", Enabled: true, Kinds: domain.AllNotificationKinds(), SlackUserID: "U01"}
+	repo.templates[domain.NotificationAssigned] = `{{.Mention}}: {{.PullRequest.Name}} is yours ({{.Reason}})`
+
+	event := newEvent(domain.EventReviewerReassigned, "core", ReviewerReassignedData{
+		PullRequestID: "pr-1",
+		OldReviewerID: "r1",
+		NewReviewerID: "r2",
+		Reason:        "manual",
+	})
+	if err := sink.Send(ctx, outboxMessage(t, event)); err != nil {
+		t.Fatalf("Send error: %v", err)
+	}
+
+	if len(notifier.sent) != 2 {
+		t.Fatalf("expected notifications for new and old reviewer, got %+v", notifier.sent)
+	}
+	if n := notifier.sent[0]; n.UserID != "r2" || n.Text != "R2: Add search is yours (manual)" {
+		t.Fatalf("unexpected assigned notification: %+v", n)
+	}
+	want := `<@U01>, you were removed from "Add search" (pr-1), r2 reviews it now`
+	if n := notifier.sent[1]; n.UserID != "r1" || n.Kind != domain.NotificationReplaced || n.Text != want {
+		t.Fatalf("unexpected replaced notification: %+v", n)
+	}
+}
+
+func TestNotificationSink_MergedSkipsPermanentFailures(t *testing.T) {
+	ctx := context.Background()
+	sink, repo, notifier := newNotificationFixture()
+	repo.prefs["author"] = domain.NotificationPreferences{UserID: "author", Enabled: true, Kinds: []domain.NotificationKind{domain.NotificationAssigned}}
+	notifier.fail["r2"] = permanentTestError{}
+
+	event := newEvent(domain.EventPRMerged, "core", prEventData(domain.PullRequest{
+		ID:                "pr-1",
+		AuthorID:          "author",
+		AssignedReviewers: []string{"r2"},
+	}))
+	if err := sink.Send(ctx, outboxMessage(t, event)); err != nil {
+		t.Fatalf("permanent failure must not fail the event, got %v", err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("author opted out of merged, r2 failed permanently; got %+v", notifier.sent)
+	}
+
+	notifier.fail["r2"] = errors.New("timeout")
+	if err := sink.Send(ctx, outboxMessage(t, event)); err == nil {
+		t.Fatal("expected transient failure to be returned for retry")
+	}
+}
+
+func TestNotificationService_SetTemplateValidates(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeNotificationRepo{templates: map[domain.NotificationKind]string{}}
+	teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"core": domain.DefaultTeamSettings()}}
+	svc := NewNotificationService(repo, &userRepoFake{}, teamRepo)
+
+	for _, text := range []string{"{{.PullRequest.Nope}}", "{{if}}"} {
+		_, err := svc.SetTemplate(ctx, domain.NotificationTemplate{TeamName: "core", Kind: domain.NotificationMerged, Template: text})
+		if !errors.Is(err, domain.ErrInvalidInput) {
+			t.Fatalf("template %q: expected ErrInvalidInput, got %v", text, err)
+		}
+	}
+
+	tpl, err := svc.SetTemplate(ctx, domain.NotificationTemplate{TeamName: "core", Kind: domain.NotificationMerged, Template: "merged {{.PullRequest.ID}}"})
+	if err != nil || !tpl.Custom {
+		t.Fatalf("SetTemplate: %+v err=%v", tpl, err)
+	}
+
+	tpl, err = svc.SetTemplate(ctx, domain.NotificationTemplate{TeamName: "core", Kind: domain.NotificationMerged})
+	if err != nil || tpl.Custom || tpl.Template != defaultNotificationTemplates[domain.NotificationMerged] {
+		t.Fatalf("empty template must reset to default, got %+v err=%v", tpl, err)
+	}
+	if _, ok := repo.templates[domain.NotificationMerged]; ok {
+		t.Fatal("custom template must be deleted")
+	}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// outboxLease — на сколько сообщение из пачки скрыто от других relay, пока его рассылают.
+const outboxLease = time.Minute
+
+// OutboxPublisher — EventPublisher, который только сохраняет событие в outbox.
+// Вызывается внутри транзакции изменения, поэтому событие появляется тогда и только тогда,
+// когда изменение закоммичено.
+type OutboxPublisher struct {
+	repo domain.OutboxRepository
+}
+
+func NewOutboxPublisher(repo domain.OutboxRepository) *OutboxPublisher {
+	return &OutboxPublisher{repo: repo}
+}
+
+func (p *OutboxPublisher) Publish(ctx context.Context, event domain.Event) error {
+	payload, err := marshalEvent(event)
+	if err != nil {
+		return err
+	}
+
+	return p.repo.Add(ctx, domain.OutboxMessage{
+		EventID:   event.ID,
+		EventType: event.Type,
+		TeamName:  event.TeamName,
+		Payload:   payload,
+	})
+}
+
+// EventSink — приёмник событий из outbox. Доставка at-least-once: после сбоя сообщение
+// может прийти повторно, дубли отсекаются по id события.
+type EventSink interface {
+	Name() string
+	Send(ctx context.Context, msg domain.OutboxMessage) error
+}
+
+type OutboxRelayConfig struct {
+	PollInterval time.Duration
+	BatchSize    int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+}
+
+// OutboxRelay в фоне раздаёт сообщения outbox всем приёмникам. Сообщение считается
+// опубликованным, когда его приняли все; упавшие приёмники повторяются с экспоненциальной
+// задержкой без ограничения числа попыток, уже принявшие — не дёргаются повторно.
+type OutboxRelay struct {
+	repo  domain.OutboxRepository
+	sinks []EventSink
+	cfg   OutboxRelayConfig
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, sinks []EventSink, cfg OutboxRelayConfig) *OutboxRelay {
+	return &OutboxRelay{
+		repo:  repo,
+		sinks: sinks,
+		cfg:   cfg,
+	}
+}
+
+func (r *OutboxRelay) Run(ctx context.Context) {
+	defer r.closeSinks()
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RelayPending(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("outbox: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayPending обрабатывает одну пачку сообщений и возвращает её размер.
+// Порядок доставки не гарантируется: пока упавшее сообщение ждёт повтора, следующие уходят
+// без него, а несколько relay разбирают outbox параллельно. Упорядочивать — по occurred_at события.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	messages, err := r.repo.ClaimPending(ctx, r.cfg.BatchSize, outboxLease)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox: %w", err)
+	}
+
+	for _, msg := range messages {
+		if err := r.relay(ctx, msg); err != nil {
+			return 0, fmt.Errorf("update outbox message %d: %w", msg.ID, err)
+		}
+	}
+
+	return len(messages), nil
+}
+
+func (r *OutboxRelay) relay(ctx context.Context, msg domain.OutboxMessage) error {
+	delivered := append([]string(nil), msg.DeliveredSinks...)
+	var failures []string
+	for _, sink := range r.sinks {
+		if msg.DeliveredTo(sink.Name()) {
+			continue
+		}
+		if err := sink.Send(ctx, msg); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", sink.Name(), err))
+			continue
+		}
+		delivered = append(delivered, sink.Name())
+	}
+
+	if len(failures) == 0 {
+		return r.repo.MarkPublished(ctx, msg.ID)
+	}
+
+	attempts := msg.Attempts + 1
+	next := time.Now().UTC().Add(retryBackoff(attempts, r.cfg.BackoffBase, r.cfg.BackoffMax))
+	return r.repo.MarkRetry(ctx, msg.ID, attempts, next, strings.Join(failures, "; "), delivered)
+}
+
+func (r *OutboxRelay) closeSinks() {
+	for _, sink := range r.sinks {
+		if c, ok := sink.(io.Closer); ok {
+			if err := c.Close(); err != nil {
+				log.Printf("outbox: close sink %s: %v", sink.Name(), err)
+			}
+		}
+	}
+}
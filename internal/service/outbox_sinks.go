@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/nats-io/nats.go"
+)
+
+// LogSink пишет события в лог приложения — удобно для отладки и как самый простой приёмник.
+type LogSink struct{}
+
+func NewLogSink() *LogSink {
+	return &LogSink{}
+}
+
+func (s *LogSink) Name() string { return "log" }
+
+func (s *LogSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	log.Printf("event %s %s team=%s: %s", msg.EventType, msg.EventID, msg.TeamName, msg.Payload)
+	return nil
+}
+
+// WebhookSink передаёт события в очередь вебхуков: дальше их разносит WebhookDispatcher.
+type WebhookSink struct {
+	publisher domain.EventPublisher
+}
+
+func NewWebhookSink(publisher domain.EventPublisher) *WebhookSink {
+	return &WebhookSink{publisher: publisher}
+}
+
+func (s *WebhookSink) Name() string { return "webhook" }
+
+func (s *WebhookSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	event, err := unmarshalEvent(msg.Payload)
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, event)
+}
+
+// FileSink дописывает события в файл построчно (JSON Lines).
+type FileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Name() string { return "file" }
+
+func (s *FileSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Файл открывается на каждую запись, чтобы ротация логов не требовала рестарта.
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	line := append(append([]byte(nil), msg.Payload...), '\n')
+	if _, err := f.Write(line); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// NATSSink публикует события в NATS. Тема — "<prefix>.<тип события>".
+// После каждой публикации делаем Flush: так ошибка сервера не теряется и сообщение
+// не помечается опубликованным раньше времени.
+type NATSSink struct {
+	url     string
+	prefix  string
+	timeout time.Duration
+
+	mu   sync.Mutex
+	conn *nats.Conn
+}
+
+// NewNATSSink принимает адрес вида nats://host:port или host:port.
+// Подключение откладывается до первой публикации, чтобы недоступный NATS не мешал старту.
+func NewNATSSink(url, subjectPrefix string, timeout time.Duration) *NATSSink {
+	return &NATSSink{
+		url:     url,
+		prefix:  strings.Trim(subjectPrefix, "."),
+		timeout: timeout,
+	}
+}
+
+func (s *NATSSink) Name() string { return "nats" }
+
+func (s *NATSSink) Subject(t domain.EventType) string {
+	if s.prefix == "" {
+		return string(t)
+	}
+	return s.prefix + "." + string(t)
+}
+
+func (s *NATSSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	conn, err := s.connection()
+	if err != nil {
+		return err
+	}
+
+	if err := conn.Publish(s.Subject(msg.EventType), msg.Payload); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	if err := conn.FlushWithContext(ctx); err != nil {
+		return fmt.Errorf("nats publish: %w", err)
+	}
+	return nil
+}
+
+func (s *NATSSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		s.conn.Close()
+		s.conn = nil
+	}
+	return nil
+}
+
+// connection возвращает живое соединение. Обрывы nats.go переживает сам,
+// заново подключаемся только если соединение закрыто окончательно.
+func (s *NATSSink) connection() (*nats.Conn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil && !s.conn.IsClosed() {
+		return s.conn, nil
+	}
+
+	conn, err := nats.Connect(s.url, nats.Name("pr-reviewer-service"), nats.Timeout(s.timeout))
+	if err != nil {
+		return nil, fmt.Errorf("nats connect: %w", err)
+	}
+	s.conn = conn
+	return conn, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeOutboxRepo struct {
+	messages []*domain.OutboxMessage
+}
+
+func (r *fakeOutboxRepo) Add(ctx context.Context, messages ...domain.OutboxMessage) error {
+	for _, m := range messages {
+		m.ID = int64(len(r.messages) + 1)
+		m.NextAttemptAt = time.Now().Add(-time.Second)
+		r.messages = append(r.messages, &m)
+	}
+	return nil
+}
+
+func (r *fakeOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var res []domain.OutboxMessage
+	for _, m := range r.messages {
+		if m.PublishedAt == nil && !m.NextAttemptAt.After(time.Now()) && len(res) < limit {
+			m.NextAttemptAt = time.Now().Add(lease)
+			res = append(res, *m)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeOutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	now := time.Now()
+	m := r.messages[id-1]
+	m.PublishedAt = &now
+	m.Attempts++
+	return nil
+}
+
+func (r *fakeOutboxRepo) MarkRetry(
+	ctx context.Context,
+	id int64,
+	attempts int,
+	next time.Time,
+	lastError string,
+	deliveredSinks []string,
+) error {
+	m := r.messages[id-1]
+	m.Attempts, m.NextAttemptAt, m.LastError, m.DeliveredSinks = attempts, next, lastError, deliveredSinks
+	return nil
+}
+
+type recordingSink struct {
+	name  string
+	fail  bool
+	got   []string
+	calls int
+}
+
+func (s *recordingSink) Name() string { return s.name }
+
+func (s *recordingSink) Send(ctx context.Context, msg domain.OutboxMessage) error {
+	s.calls++
+	if s.fail {
+		return errors.New("unavailable")
+	}
+	s.got = append(s.got, msg.EventID)
+	return nil
+}
+
+func TestOutboxPublisher_StoresEnvelope(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepo{}
+
+	event := newEvent(domain.EventPRMerged, "core", PREventData{PullRequestID: "pr-1"})
+	if err := NewOutboxPublisher(repo).Publish(ctx, event); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	if len(repo.messages) != 1 {
+		t.Fatalf("expected 1 outbox message, got %d", len(repo.messages))
+	}
+	msg := repo.messages[0]
+	if msg.EventID != event.ID || msg.EventType != domain.EventPRMerged || msg.TeamName != "core" {
+		t.Fatalf("unexpected outbox message: %+v", msg)
+	}
+
+	decoded, err := unmarshalEvent(msg.Payload)
+	if err != nil {
+		t.Fatalf("unmarshalEvent error: %v", err)
+	}
+	var data PREventData
+	if err := json.Unmarshal(decoded.Data.(json.RawMessage), &data); err != nil {
+		t.Fatalf("unmarshal data: %v", err)
+	}
+	if decoded.ID != event.ID || data.PullRequestID != "pr-1" {
+		t.Fatalf("unexpected decoded event: %+v, data %+v", decoded, data)
+	}
+}
+
+func TestOutboxRelay_RetriesOnlyFailedSinks(t *testing.T) {
+	ctx := context.Background()
+	repo := &fakeOutboxRepo{}
+	publisher := NewOutboxPublisher(repo)
+
+	first := newEvent(domain.EventPRCreated, "core", PREventData{PullRequestID: "pr-1"})
+	second := newEvent(domain.EventPRMerged, "core", PREventData{PullRequestID: "pr-1"})
+	if err := publishEvents(ctx, publisher, first, second); err != nil {
+		t.Fatalf("publishEvents error: %v", err)
+	}
+
+	ok := &recordingSink{name: "ok"}
+	flaky := &recordingSink{name: "flaky", fail: true}
+	relay := NewOutboxRelay(repo, []EventSink{ok, flaky}, OutboxRelayConfig{
+		BatchSize:   10,
+		BackoffBase: time.Millisecond,
+		BackoffMax:  time.Millisecond,
+	})
+
+	n, err := relay.RelayPending(ctx)
+	if err != nil || n != 2 {
+		t.Fatalf("RelayPending: n=%d err=%v", n, err)
+	}
+	if strings.Join(ok.got, ",") != first.ID+","+second.ID {
+		t.Fatalf("expected events in order, got %v", ok.got)
+	}
+	for _, m := range repo.messages {
+		if m.PublishedAt != nil || m.Attempts != 1 || !strings.Contains(m.LastError, "flaky") {
+			t.Fatalf("expected message to wait for retry, got %+v", m)
+		}
+		if len(m.DeliveredSinks) != 1 || m.DeliveredSinks[0] != "ok" {
+			t.Fatalf("expected ok sink to be recorded, got %v", m.DeliveredSinks)
+		}
+	}
+
+	flaky.fail = false
+	time.Sleep(5 * time.Millisecond)
+	if _, err := relay.RelayPending(ctx); err != nil {
+		t.Fatalf("RelayPending retry error: %v", err)
+	}
+
+	if ok.calls != 2 {
+		t.Fatalf("delivered sink must not be called again, got %d calls", ok.calls)
+	}
+	if len(flaky.got) != 2 {
+		t.Fatalf("expected flaky sink to receive both events, got %v", flaky.got)
+	}
+	for _, m := range repo.messages {
+		if m.PublishedAt == nil {
+			t.Fatalf("expected message %d to be published", m.ID)
+		}
+	}
+
+	if n, _ := relay.RelayPending(ctx); n != 0 {
+		t.Fatalf("published messages must not be relayed again, got %d", n)
+	}
+}
+
+func TestFileSink_AppendsLines(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink := NewFileSink(path)
+
+	for _, payload := range []string{`{"id":"1"}`, `{"id":"2"}`} {
+		if err := sink.Send(ctx, domain.OutboxMessage{Payload: []byte(payload)}); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if string(got) != "{\"id\":\"1\"}\n{\"id\":\"2\"}\n" {
+		t.Fatalf("unexpected file content: %q", got)
+	}
+}
+
+// natsStub — минимальный NATS-сервер: здоровается INFO, принимает PUB и отвечает на PING.
+func natsStub(t *testing.T, published chan<- string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				fmt.Fprint(conn, "INFO {\"server_id\":\"stub\",\"max_payload\":1048576}\r\n")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					line = strings.TrimRight(line, "\r\n")
+					switch {
+					case strings.HasPrefix(line, "PUB "):
+						var subject string
+						var size int
+						if _, err := fmt.Sscanf(line, "PUB %s %d", &subject, &size); err != nil {
+							fmt.Fprint(conn, "-ERR 'Unknown Protocol Operation'\r\n")
+							return
+						}
+						buf := make([]byte, size+2)
+						if _, err := io.ReadFull(r, buf); err != nil {
+							return
+						}
+						published <- subject + " " + string(buf[:size])
+					case line == "PING":
+						fmt.Fprint(conn, "PONG\r\n")
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	return "nats://" + ln.Addr().String()
+}
+
+func TestNATSSink_Publishes(t *testing.T) {
+	ctx := context.Background()
+	published := make(chan string, 4)
+	addr := natsStub(t, published)
+
+	sink := NewNATSSink(addr, "pr_reviewer", time.Second)
+	defer sink.Close()
+
+	for _, id := range []string{"1", "2"} {
+		msg := domain.OutboxMessage{EventType: domain.EventPRMerged, Payload: []byte(`{"id":"` + id + `"}`)}
+		if err := sink.Send(ctx, msg); err != nil {
+			t.Fatalf("Send error: %v", err)
+		}
+	}
+
+	for _, want := range []string{`pr_reviewer.pr.merged {"id":"1"}`, `pr_reviewer.pr.merged {"id":"2"}`} {
+		select {
+		case got := <-published:
+			if got != want {
+				t.Fatalf("expected %q, got %q", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("stub did not receive %q", want)
+		}
+	}
+}
+
+func TestNATSSink_ServerUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	sink := NewNATSSink(addr, "", 200*time.Millisecond)
+	if err := sink.Send(context.Background(), domain.OutboxMessage{EventType: domain.EventPRCreated}); err == nil {
+		t.Fatal("expected error when server is unavailable")
+	}
+}
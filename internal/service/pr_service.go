@@ -2,71 +2,339 @@ package service
 
 import (
 	"context"
-	"math/rand"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
 )
 
 type PRService struct {
-	prRepo   domain.PullRequestRepository
-	userRepo domain.UserRepository
-	teamRepo domain.TeamRepository
+	prRepo    domain.PullRequestRepository
+	userRepo  domain.UserRepository
+	teamRepo  domain.TeamRepository
+	eventRepo domain.ReviewerEventRepository
+	syncRepo  domain.ReviewerSyncRepository
+	publisher domain.EventPublisher
+	txManager domain.TxManager
+	selectors ReviewerSelectors
 }
 
 func NewPRService(
 	prRepo domain.PullRequestRepository,
 	userRepo domain.UserRepository,
 	teamRepo domain.TeamRepository,
+	eventRepo domain.ReviewerEventRepository,
+	syncRepo domain.ReviewerSyncRepository,
+	publisher domain.EventPublisher,
+	txManager domain.TxManager,
 ) *PRService {
 	return &PRService{
-		prRepo:   prRepo,
-		userRepo: userRepo,
-		teamRepo: teamRepo,
+		prRepo:    prRepo,
+		userRepo:  userRepo,
+		teamRepo:  teamRepo,
+		eventRepo: eventRepo,
+		syncRepo:  syncRepo,
+		publisher: publisher,
+		txManager: txManager,
+		selectors: DefaultReviewerSelectors(prRepo, teamRepo),
 	}
 }
 
-func (s *PRService) CreatePR(ctx context.Context, pr *domain.PullRequest) (domain.PullRequest, error) {
-	pr.Status = domain.PullRequestStatusOpen
+// recordEvents дописывает события в историю ревьюверов, проставляя автора изменения и время,
+// публикует их для подписчиков команды teamName и ставит PR из внешних систем в очередь
+// выгрузки ревьюверов. Вызывается в той же транзакции, что и само изменение.
+func (s *PRService) recordEvents(ctx context.Context, teamName string, events ...domain.ReviewerEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
 
-	if pr.CreatedAt.IsZero() {
-		pr.CreatedAt = time.Now().UTC()
+	actorID := domain.ActorFromContext(ctx)
+	now := time.Now().UTC()
+	for i := range events {
+		events[i].ActorID = actorID
+		events[i].CreatedAt = now
+	}
+	if err := s.eventRepo.Append(ctx, events...); err != nil {
+		return err
+	}
+	if err := s.requestReviewerSync(ctx, events); err != nil {
+		return err
 	}
 
-	if err := s.prRepo.Create(ctx, pr); err != nil {
-		return domain.PullRequest{}, err
+	published := make([]domain.Event, 0, len(events))
+	for _, e := range events {
+		if e.Type == domain.ReviewerEventAssigned {
+			published = append(published, newEvent(domain.EventReviewerAssigned, teamName, ReviewerAssignedData{
+				PullRequestID: e.PullRequestID,
+				ReviewerID:    e.ReviewerID,
+				Reason:        e.Reason,
+			}))
+			continue
+		}
+		published = append(published, newEvent(domain.EventReviewerReassigned, teamName, ReviewerReassignedData{
+			PullRequestID: e.PullRequestID,
+			OldReviewerID: e.ReviewerID,
+			NewReviewerID: e.ReplacedBy,
+			Reason:        e.Reason,
+		}))
+	}
+	return publishEvents(ctx, s.publisher, published...)
+}
+
+func (s *PRService) requestReviewerSync(ctx context.Context, events []domain.ReviewerEvent) error {
+	if s.syncRepo == nil {
+		return nil
 	}
 
+	requested := make(map[string]struct{})
+	for _, e := range events {
+		if _, ok := requested[e.PullRequestID]; ok {
+			continue
+		}
+		if _, ok := domain.ParseExternalPRID(e.PullRequestID); !ok {
+			continue
+		}
+		if err := s.syncRepo.Request(ctx, e.PullRequestID); err != nil {
+			return err
+		}
+		requested[e.PullRequestID] = struct{}{}
+	}
+	return nil
+}
+
+// prTeam — команда, к которой относится PR: явно заданная команда ревьюверов или команда автора;
+// пусто, если автора уже нет в команде.
+func (s *PRService) prTeam(ctx context.Context, pr domain.PullRequest) (string, error) {
+	if pr.ReviewerTeam != "" {
+		return pr.ReviewerTeam, nil
+	}
 	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
 	if err != nil {
-		return domain.PullRequest{}, err
+		if errors.Is(err, domain.ErrNotFound) {
+			return "", nil
+		}
+		return "", err
 	}
+	return author.TeamName, nil
+}
 
-	candidates, err := s.userRepo.ListActiveByTeam(ctx, author.TeamName)
-	if err != nil {
-		return domain.PullRequest{}, err
+// reviewerTeam — команда, из которой PR получает ревьюверов и политику.
+func reviewerTeam(pr domain.PullRequest, author domain.User) string {
+	if pr.ReviewerTeam != "" {
+		return pr.ReviewerTeam
 	}
+	return author.TeamName
+}
 
-	if len(candidates) > 1 {
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		r.Shuffle(len(candidates), func(i, j int) {
-			candidates[i], candidates[j] = candidates[j], candidates[i]
+func assignedEvents(prID string, reviewers []string, reason string) []domain.ReviewerEvent {
+	events := make([]domain.ReviewerEvent, 0, len(reviewers))
+	for _, id := range reviewers {
+		events = append(events, domain.ReviewerEvent{
+			PullRequestID: prID,
+			Type:          domain.ReviewerEventAssigned,
+			ReviewerID:    id,
+			Reason:        reason,
 		})
 	}
+	return events
+}
 
-	var reviewers []string
-	for _, u := range candidates {
-		if u.ID == pr.AuthorID {
-			continue
+// History возвращает историю назначений ревьюверов PR в порядке появления.
+func (s *PRService) History(ctx context.Context, prID string) ([]domain.ReviewerEvent, error) {
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.eventRepo.ListByPR(ctx, prID)
+}
+
+// CreatePROptions — необязательные параметры создания PR.
+type CreatePROptions struct {
+	// ReviewersCount переопределяет max_reviewers команды для конкретного PR.
+	ReviewersCount *int
+	// Draft создаёт PR в статусе DRAFT без ревьюверов.
+	Draft bool
+	// ReviewerTeam задаёт команду, из которой назначаются ревьюверы (например, команду проекта GitLab);
+	// по умолчанию — команда автора. Сохраняется в PR и используется при /pullRequest/ready.
+	ReviewerTeam string
+}
+
+// teamSettings возвращает настройки команды.
+// Если команды нет (например, у пользователя обнулился team_name) — используются настройки по умолчанию.
+func (s *PRService) teamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	settings, err := s.teamRepo.GetSettings(ctx, teamName)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return domain.TeamSettings{}, err
 		}
+		return domain.DefaultTeamSettings(), nil
+	}
+	return settings, nil
+}
+
+func (s *PRService) selector(strategy domain.AssignmentStrategy) ReviewerSelector {
+	if sel, ok := s.selectors[strategy]; ok {
+		return sel
+	}
+	return s.selectors[domain.AssignmentStrategyRandom]
+}
+
+// availableReviewers возвращает активных участников команды, которых можно назначить:
+// не попавших в skip и не упёршихся в свой max_open_reviews.
+func (s *PRService) availableReviewers(ctx context.Context, teamName string, skip map[string]struct{}) ([]domain.User, error) {
+	active, err := s.userRepo.ListActiveByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		candidates []domain.User
+		limited    []string
+	)
+	for _, u := range active {
 		if !u.IsActive {
 			continue
 		}
-		reviewers = append(reviewers, u.ID)
+		if _, ok := skip[u.ID]; ok {
+			continue
+		}
+		candidates = append(candidates, u)
+		if u.MaxOpenReviews > 0 {
+			limited = append(limited, u.ID)
+		}
+	}
+
+	if len(limited) == 0 {
+		return candidates, nil
+	}
+
+	load, err := s.prRepo.OpenReviewCounts(ctx, limited)
+	if err != nil {
+		return nil, err
+	}
+
+	res := candidates[:0]
+	for _, u := range candidates {
+		if u.MaxOpenReviews > 0 && load[u.ID] >= u.MaxOpenReviews {
+			continue
+		}
+		res = append(res, u)
+	}
+	return res, nil
+}
+
+// pickReviewers выбирает до n ревьюверов из команды по её стратегии.
+// Если своих свободных кандидатов не хватает, недостающих добирает из fallback-команды
+// (только один уровень, цепочки fallback'ов не раскручиваются).
+func (s *PRService) pickReviewers(
+	ctx context.Context,
+	teamName string,
+	settings domain.TeamSettings,
+	skip map[string]struct{},
+	n int,
+) ([]domain.User, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	candidates, err := s.availableReviewers(ctx, teamName, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	picked, err := s.selector(settings.AssignmentStrategy).Select(ctx, teamName, candidates, n)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(picked) >= n || settings.FallbackTeam == "" || settings.FallbackTeam == teamName {
+		return picked, nil
+	}
+
+	fallbackSkip := make(map[string]struct{}, len(skip)+len(picked))
+	for id := range skip {
+		fallbackSkip[id] = struct{}{}
+	}
+	for _, u := range picked {
+		fallbackSkip[u.ID] = struct{}{}
+	}
+
+	fallbackSettings, err := s.teamSettings(ctx, settings.FallbackTeam)
+	if err != nil {
+		return nil, err
+	}
+
+	fallback, err := s.availableReviewers(ctx, settings.FallbackTeam, fallbackSkip)
+	if err != nil {
+		return nil, err
+	}
+
+	more, err := s.selector(fallbackSettings.AssignmentStrategy).Select(ctx, settings.FallbackTeam, fallback, n-len(picked))
+	if err != nil {
+		return nil, err
+	}
+
+	return append(picked, more...), nil
+}
+
+func (s *PRService) CreatePR(ctx context.Context, pr *domain.PullRequest, opts CreatePROptions) (domain.PullRequest, error) {
+	var created domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = s.createPR(ctx, pr, opts)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
 	}
+	return created, nil
+}
 
-	if len(reviewers) > 2 {
-		reviewers = reviewers[:2]
+func (s *PRService) createPR(ctx context.Context, pr *domain.PullRequest, opts CreatePROptions) (domain.PullRequest, error) {
+	if pr.CreatedAt.IsZero() {
+		pr.CreatedAt = time.Now().UTC()
+	}
+
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	pr.ReviewerTeam = opts.ReviewerTeam
+	teamName := reviewerTeam(*pr, author)
+
+	// Черновику ревьюверы не назначаются до /pullRequest/ready.
+	if opts.Draft {
+		if opts.ReviewersCount != nil {
+			return domain.PullRequest{}, fmt.Errorf(
+				"%w: reviewers_count is set when a draft is marked ready", domain.ErrInvalidInput,
+			)
+		}
+
+		pr.Status = domain.PullRequestStatusDraft
+		if err := s.prRepo.Create(ctx, pr); err != nil {
+			return domain.PullRequest{}, err
+		}
+
+		created, err := s.prRepo.GetByID(ctx, pr.ID)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		event := newEvent(domain.EventPRCreated, teamName, prEventData(created))
+		if err := publishEvents(ctx, s.publisher, event); err != nil {
+			return domain.PullRequest{}, err
+		}
+		return created, nil
+	}
+
+	pr.Status = domain.PullRequestStatusOpen
+
+	reviewers, err := s.initialReviewers(ctx, author, teamName, opts.ReviewersCount)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if err := s.prRepo.Create(ctx, pr); err != nil {
+		return domain.PullRequest{}, err
 	}
 
 	if len(reviewers) > 0 {
@@ -86,11 +354,253 @@ func (s *PRService) CreatePR(ctx context.Context, pr *domain.PullRequest) (domai
 		created.AssignedReviewers = append([]string(nil), reviewers...)
 	}
 
+	event := newEvent(domain.EventPRCreated, teamName, prEventData(created))
+	if err := publishEvents(ctx, s.publisher, event); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if err := s.recordEvents(ctx, teamName, assignedEvents(pr.ID, reviewers, domain.ReviewerEventReasonPRCreated)...); err != nil {
+		return domain.PullRequest{}, err
+	}
+
 	return created, nil
 }
 
+// initialReviewers подбирает ревьюверов для PR, который становится OPEN:
+// reviewersCount (если задан) проверяется по границам команды автора, затем проверяется min_reviewers.
+// Ревьюверов не может быть меньше required_approvals, иначе PR никогда не набрал бы апрувы.
+func (s *PRService) initialReviewers(
+	ctx context.Context,
+	author domain.User,
+	teamName string,
+	reviewersCount *int,
+) ([]string, error) {
+	settings, err := s.teamSettings(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	count := settings.MaxReviewers
+	if reviewersCount != nil {
+		count = *reviewersCount
+		if count < settings.MinReviewers || count > settings.MaxReviewers {
+			return nil, fmt.Errorf(
+				"%w: reviewers_count must be between %d and %d for team %s",
+				domain.ErrInvalidInput, settings.MinReviewers, settings.MaxReviewers, teamName,
+			)
+		}
+	}
+	// Настройки, сохранённые до проверки required_approvals <= min_reviewers, могут её нарушать.
+	if count < settings.RequiredApprovals {
+		return nil, fmt.Errorf(
+			"%w: reviewers_count must be at least required_approvals (%d) for team %s",
+			domain.ErrInvalidInput, settings.RequiredApprovals, teamName,
+		)
+	}
+	required := max(settings.MinReviewers, settings.RequiredApprovals)
+
+	skip := map[string]struct{}{author.ID: {}}
+	picked, err := s.pickReviewers(ctx, teamName, settings, skip, count)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(picked) < required {
+		return nil, fmt.Errorf(
+			"%w: team %s requires %d, only %d available",
+			domain.ErrNotEnoughReviewers, teamName, required, len(picked),
+		)
+	}
+
+	reviewers := make([]string, 0, len(picked))
+	for _, u := range picked {
+		reviewers = append(reviewers, u.ID)
+	}
+	return reviewers, nil
+}
+
+// MarkReady переводит черновик в OPEN и назначает ревьюверов так же, как при создании PR.
+func (s *PRService) MarkReady(ctx context.Context, prID string, reviewersCount *int) (domain.PullRequest, error) {
+	var ready domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		ready, err = s.markReady(ctx, prID, reviewersCount)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return ready, nil
+}
+
+func (s *PRService) markReady(ctx context.Context, prID string, reviewersCount *int) (domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if pr.Status == domain.PRTransitionReady.Target() {
+		return pr, nil
+	}
+
+	next, err := pr.Status.Next(domain.PRTransitionReady)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	teamName := reviewerTeam(pr, author)
+	reviewers, err := s.initialReviewers(ctx, author, teamName, reviewersCount)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if err := s.prRepo.SetStatus(ctx, prID, next); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if len(reviewers) > 0 {
+		if err := s.prRepo.AssignReviewers(ctx, prID, reviewers); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	if err := s.recordEvents(ctx, teamName, assignedEvents(prID, reviewers, domain.ReviewerEventReasonPRReady)...); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// ClosePR закрывает PR без merge. Ревьюверы остаются в истории, но PR перестаёт считаться в их нагрузке.
+func (s *PRService) ClosePR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return s.changeStatus(ctx, prID, domain.PRTransitionClose)
+}
+
+// ReopenPR возвращает закрытый PR в OPEN. Прежние ревьюверы сохраняются; если их нет
+// (закрыт был черновик), они назначаются так же, как при переводе черновика в OPEN.
+func (s *PRService) ReopenPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	var reopened domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		reopened, err = s.reopenPR(ctx, prID)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return reopened, nil
+}
+
+func (s *PRService) reopenPR(ctx context.Context, prID string) (domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if pr.Status == domain.PRTransitionReopen.Target() {
+		return pr, nil
+	}
+
+	next, err := pr.Status.Next(domain.PRTransitionReopen)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	current, err := s.prRepo.ListReviewers(ctx, prID)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	var (
+		teamName  string
+		reviewers []string
+	)
+	if len(current) == 0 {
+		author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			return domain.PullRequest{}, err
+		}
+		teamName = reviewerTeam(pr, author)
+		if reviewers, err = s.initialReviewers(ctx, author, teamName, nil); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	if err := s.prRepo.SetStatus(ctx, prID, next); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	if len(reviewers) > 0 {
+		if err := s.prRepo.AssignReviewers(ctx, prID, reviewers); err != nil {
+			return domain.PullRequest{}, err
+		}
+		if err := s.recordEvents(ctx, teamName, assignedEvents(prID, reviewers, domain.ReviewerEventReasonPRReopened)...); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
+	return s.prRepo.GetByID(ctx, prID)
+}
+
+// changeStatus выполняет переход, не требующий ничего, кроме смены статуса.
+// Повторный вызов для PR, уже находящегося в целевом статусе, ничего не меняет.
+func (s *PRService) changeStatus(ctx context.Context, prID string, t domain.PRTransition) (domain.PullRequest, error) {
+	var updated domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == t.Target() {
+			updated = pr
+			return nil
+		}
+
+		next, err := pr.Status.Next(t)
+		if err != nil {
+			return err
+		}
+
+		if err := s.prRepo.SetStatus(ctx, prID, next); err != nil {
+			return err
+		}
+
+		updated, err = s.prRepo.GetByID(ctx, prID)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return updated, nil
+}
+
 func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID string) (domain.PullRequest, string, error) {
-	pr, err := s.prRepo.GetByID(ctx, prID)
+	var (
+		updated domain.PullRequest
+		newID   string
+	)
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		updated, newID, err = s.reassignReviewer(ctx, prID, oldReviewerID, domain.ReviewerEventReasonReassign)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	return updated, newID, nil
+}
+
+func (s *PRService) reassignReviewer(
+	ctx context.Context,
+	prID, oldReviewerID, reason string,
+) (domain.PullRequest, string, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
@@ -98,6 +608,9 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 	if pr.Status == domain.PullRequestStatusMerged {
 		return domain.PullRequest{}, "", domain.ErrPRMerged
 	}
+	if pr.Status != domain.PullRequestStatusOpen {
+		return domain.PullRequest{}, "", fmt.Errorf("%w: pr %s is %s", domain.ErrPRNotOpen, prID, pr.Status)
+	}
 
 	reviewers, err := s.prRepo.ListReviewers(ctx, prID)
 	if err != nil {
@@ -120,59 +633,57 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 		return domain.PullRequest{}, "", err
 	}
 
-	active, err := s.userRepo.ListActiveByTeam(ctx, oldReviewer.TeamName)
+	settings, err := s.teamSettings(ctx, oldReviewer.TeamName)
 	if err != nil {
 		return domain.PullRequest{}, "", err
 	}
 
-	if len(active) > 1 {
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		r.Shuffle(len(active), func(i, j int) {
-			active[i], active[j] = active[j], active[i]
-		})
+	skip := map[string]struct{}{
+		pr.AuthorID:   {},
+		oldReviewerID: {},
 	}
-
-	assigned := make(map[string]struct{}, len(reviewers))
 	for _, rID := range reviewers {
-		assigned[rID] = struct{}{}
+		skip[rID] = struct{}{}
 	}
 
-	var newID string
-	for _, u := range active {
-		if u.ID == pr.AuthorID {
-			continue
-		}
-		if u.ID == oldReviewerID {
-			continue
-		}
-		if _, used := assigned[u.ID]; used {
-			continue
-		}
-		newID = u.ID
-		break
+	// Сначала пробуем тех, кто ещё не назначен на PR (в том числе из fallback-команды),
+	// и только если таких нет — переиспользуем второго ревьювера (маленькая команда).
+	picked, err := s.pickReviewers(ctx, oldReviewer.TeamName, settings, skip, 1)
+	if err != nil {
+		return domain.PullRequest{}, "", err
 	}
 
 	reuseAssigned := false
-	if newID == "" {
+	if len(picked) == 0 {
+		active, err := s.userRepo.ListActiveByTeam(ctx, oldReviewer.TeamName)
+		if err != nil {
+			return domain.PullRequest{}, "", err
+		}
+
+		var alreadyAssigned []domain.User
 		for _, u := range active {
-			if u.ID == pr.AuthorID {
+			if u.ID == pr.AuthorID || u.ID == oldReviewerID {
 				continue
 			}
-			if u.ID == oldReviewerID {
-				continue
+			for _, rID := range reviewers {
+				if u.ID == rID {
+					alreadyAssigned = append(alreadyAssigned, u)
+					break
+				}
 			}
-			if _, used := assigned[u.ID]; !used {
-				continue
-			}
-			newID = u.ID
-			reuseAssigned = true
-			break
 		}
+
+		picked, err = s.selector(settings.AssignmentStrategy).Select(ctx, oldReviewer.TeamName, alreadyAssigned, 1)
+		if err != nil {
+			return domain.PullRequest{}, "", err
+		}
+		reuseAssigned = true
 	}
 
-	if newID == "" {
+	if len(picked) == 0 {
 		return domain.PullRequest{}, "", domain.ErrNoCandidate
 	}
+	newID := picked[0].ID
 
 	if reuseAssigned {
 		if err := s.prRepo.RemoveReviewer(ctx, prID, newID); err != nil {
@@ -184,6 +695,26 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 		return domain.PullRequest{}, "", err
 	}
 
+	// Если место занял уже назначенный ревьювер, новых людей на PR не появилось — старый просто снят.
+	event := domain.ReviewerEvent{
+		PullRequestID: prID,
+		Type:          domain.ReviewerEventReplaced,
+		ReviewerID:    oldReviewerID,
+		ReplacedBy:    newID,
+		Reason:        reason,
+	}
+	if reuseAssigned {
+		event.Type = domain.ReviewerEventRemoved
+		event.ReplacedBy = ""
+	}
+	teamName, err := s.prTeam(ctx, pr)
+	if err != nil {
+		return domain.PullRequest{}, "", err
+	}
+	if err := s.recordEvents(ctx, teamName, event); err != nil {
+		return domain.PullRequest{}, "", err
+	}
+
 	updated, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
 		return domain.PullRequest{}, "", err
@@ -192,8 +723,72 @@ func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID st
 	return updated, newID, nil
 }
 
+// ReassignOpenReviews переносит все OPEN ревью пользователя на других по тем же правилам,
+// что и ReassignReviewer. PR, для которых замены нет, остаются как есть и попадают в отчёт
+// с пустым NewReviewerID. Транзакцией управляет вызывающий код.
+func (s *PRService) ReassignOpenReviews(ctx context.Context, reviewerID string) ([]domain.ReviewReassignment, error) {
+	prs, err := s.prRepo.ListByReviewer(ctx, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]domain.ReviewReassignment, 0, len(prs))
+	for _, pr := range prs {
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+
+		item := domain.ReviewReassignment{
+			PullRequestID: pr.ID,
+			OldReviewerID: reviewerID,
+		}
+
+		_, newID, err := s.reassignReviewer(ctx, pr.ID, reviewerID, domain.ReviewerEventReasonUserDeactivated)
+		switch {
+		case err == nil:
+			item.NewReviewerID = newID
+		case errors.Is(err, domain.ErrNoCandidate):
+		default:
+			return nil, err
+		}
+
+		report = append(report, item)
+	}
+
+	return report, nil
+}
+
 func (s *PRService) MergePR(ctx context.Context, prID string) (domain.PullRequest, error) {
-	pr, err := s.prRepo.GetByID(ctx, prID)
+	var merged domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		merged, err = s.mergePR(ctx, prID, false)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return merged, nil
+}
+
+// RecordExternalMerge фиксирует мёрж, который уже произошёл в GitHub или GitLab.
+// Политика апрувов не проверяется: изменения уже влиты, статус должен это отражать.
+// PR принимается как из OPEN, так и из DRAFT.
+func (s *PRService) RecordExternalMerge(ctx context.Context, prID string) (domain.PullRequest, error) {
+	var merged domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		merged, err = s.mergePR(ctx, prID, true)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	return merged, nil
+}
+
+func (s *PRService) mergePR(ctx context.Context, prID string, external bool) (domain.PullRequest, error) {
+	pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
 	if err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -202,6 +797,19 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (domain.PullReques
 		return pr, nil
 	}
 
+	if external {
+		if pr.Status != domain.PullRequestStatusOpen && pr.Status != domain.PullRequestStatusDraft {
+			return domain.PullRequest{}, fmt.Errorf("%w: cannot record merge of pr in status %s", domain.ErrInvalidTransition, pr.Status)
+		}
+	} else {
+		if _, err := pr.Status.Next(domain.PRTransitionMerge); err != nil {
+			return domain.PullRequest{}, err
+		}
+		if err := s.checkApprovals(ctx, pr); err != nil {
+			return domain.PullRequest{}, err
+		}
+	}
+
 	if err := s.prRepo.Merge(ctx, prID); err != nil {
 		return domain.PullRequest{}, err
 	}
@@ -211,5 +819,106 @@ func (s *PRService) MergePR(ctx context.Context, prID string) (domain.PullReques
 		return domain.PullRequest{}, err
 	}
 
+	teamName, err := s.prTeam(ctx, updated)
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
+	event := newEvent(domain.EventPRMerged, teamName, prEventData(updated))
+	if err := publishEvents(ctx, s.publisher, event); err != nil {
+		return domain.PullRequest{}, err
+	}
+
+	return updated, nil
+}
+
+// checkApprovals применяет политику команды PR: нужно не меньше required_approvals
+// APPROVED от текущих ревьюверов и ни одного CHANGES_REQUESTED.
+func (s *PRService) checkApprovals(ctx context.Context, pr domain.PullRequest) error {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		return err
+	}
+
+	settings, err := s.teamSettings(ctx, reviewerTeam(pr, author))
+	if err != nil {
+		return err
+	}
+	if settings.RequiredApprovals == 0 {
+		return nil
+	}
+
+	reviews, err := s.prRepo.ListReviews(ctx, pr.ID)
+	if err != nil {
+		return err
+	}
+
+	approvals := 0
+	for _, r := range reviews {
+		switch r.Decision {
+		case domain.ReviewDecisionApproved:
+			approvals++
+		case domain.ReviewDecisionChangesRequested:
+			return fmt.Errorf("%w: %s requested changes", domain.ErrNotApproved, r.ReviewerID)
+		}
+	}
+
+	if approvals < settings.RequiredApprovals {
+		return fmt.Errorf("%w: %d of %d approvals", domain.ErrNotApproved, approvals, settings.RequiredApprovals)
+	}
+	return nil
+}
+
+// SubmitReview записывает решение назначенного ревьювера по OPEN PR.
+// Повторный вызов заменяет его предыдущее решение.
+func (s *PRService) SubmitReview(
+	ctx context.Context,
+	prID, reviewerID string,
+	decision domain.ReviewDecisionType,
+) (domain.PullRequest, error) {
+	if !decision.IsValid() {
+		return domain.PullRequest{}, fmt.Errorf("%w: unknown review decision %q", domain.ErrInvalidInput, decision)
+	}
+
+	var updated domain.PullRequest
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prRepo.GetByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == domain.PullRequestStatusMerged {
+			return domain.ErrPRMerged
+		}
+		if pr.Status != domain.PullRequestStatusOpen {
+			return fmt.Errorf("%w: pr %s is %s", domain.ErrPRNotOpen, prID, pr.Status)
+		}
+
+		assigned := false
+		for _, id := range pr.AssignedReviewers {
+			if id == reviewerID {
+				assigned = true
+				break
+			}
+		}
+		if !assigned {
+			return domain.ErrNotAssigned
+		}
+
+		review := domain.ReviewDecision{
+			PullRequestID: prID,
+			ReviewerID:    reviewerID,
+			Decision:      decision,
+			DecidedAt:     time.Now().UTC(),
+		}
+		if err := s.prRepo.SaveReview(ctx, review); err != nil {
+			return err
+		}
+
+		updated, err = s.prRepo.GetByID(ctx, prID)
+		return err
+	})
+	if err != nil {
+		return domain.PullRequest{}, err
+	}
 	return updated, nil
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
 	"testing"
 	"time"
 
@@ -12,7 +13,11 @@ import (
 type prRepoFake struct {
 	prs       map[string]domain.PullRequest
 	reviewers map[string][]string
-	createErr error
+	reviews   map[string]map[string]domain.ReviewDecision
+	// authorTeams — команда автора для PR без reviewer_team, как join с users в настоящем репозитории.
+	authorTeams map[string]string
+	createErr   error
+	locked      []string
 }
 
 func (r *prRepoFake) Create(ctx context.Context, pr *domain.PullRequest) error {
@@ -44,9 +49,15 @@ func (r *prRepoFake) GetByID(ctx context.Context, id string) (domain.PullRequest
 	if !ok {
 		return domain.PullRequest{}, domain.ErrNotFound
 	}
+	pr.Reviews, _ = r.ListReviews(ctx, id)
 	return pr, nil
 }
 
+func (r *prRepoFake) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	r.locked = append(r.locked, id)
+	return r.GetByID(ctx, id)
+}
+
 func (r *prRepoFake) ListReviewers(ctx context.Context, prID string) ([]string, error) {
 	return append([]string(nil), r.reviewers[prID]...), nil
 }
@@ -76,8 +87,118 @@ func (r *prRepoFake) Merge(ctx context.Context, prID string) error {
 	return nil
 }
 
+func (r *prRepoFake) SetStatus(ctx context.Context, prID string, status domain.PRStatus) error {
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	pr.Status = status
+	pr.ClosedAt = nil
+	pr.StaleSince = nil
+	if status == domain.PullRequestStatusClosed {
+		now := time.Now().UTC()
+		pr.ClosedAt = &now
+	}
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *prRepoFake) SaveReview(ctx context.Context, review domain.ReviewDecision) error {
+	if r.reviews == nil {
+		r.reviews = make(map[string]map[string]domain.ReviewDecision)
+	}
+	if r.reviews[review.PullRequestID] == nil {
+		r.reviews[review.PullRequestID] = make(map[string]domain.ReviewDecision)
+	}
+	r.reviews[review.PullRequestID][review.ReviewerID] = review
+	return nil
+}
+
+func (r *prRepoFake) ListReviews(ctx context.Context, prID string) ([]domain.ReviewDecision, error) {
+	var res []domain.ReviewDecision
+	for _, id := range r.reviewers[prID] {
+		if d, ok := r.reviews[prID][id]; ok {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
 func (r *prRepoFake) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
-	return nil, nil
+	var res []domain.PullRequest
+	for prID, list := range r.reviewers {
+		for _, id := range list {
+			if id == reviewerID {
+				res = append(res, r.prs[prID])
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+func (r *prRepoFake) OpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	res := make(map[string]int)
+	for prID, list := range r.reviewers {
+		if r.prs[prID].Status != domain.PullRequestStatusOpen {
+			continue
+		}
+		for _, id := range list {
+			res[id]++
+		}
+	}
+	return res, nil
+}
+
+func (r *prRepoFake) ListOpenReviews(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error) {
+	wanted := make(map[string]struct{}, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		wanted[id] = struct{}{}
+	}
+
+	var res []domain.OpenReview
+	for prID, list := range r.reviewers {
+		pr := r.prs[prID]
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+		for _, id := range list {
+			if _, ok := wanted[id]; !ok {
+				continue
+			}
+			team := pr.ReviewerTeam
+			if team == "" {
+				team = r.authorTeams[pr.AuthorID]
+			}
+			res = append(res, domain.OpenReview{
+				PullRequestID: prID,
+				AuthorID:      pr.AuthorID,
+				TeamName:      team,
+				Reviewers:     append([]string(nil), list...),
+			})
+			r.locked = append(r.locked, prID)
+			break
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PullRequestID < res[j].PullRequestID
+	})
+	return res, nil
+}
+
+func (r *prRepoFake) ApplyReviewerChanges(ctx context.Context, changes []domain.ReviewerChange) error {
+	for _, c := range changes {
+		if c.NewReviewerID == "" {
+			if err := r.RemoveReviewer(ctx, c.PullRequestID, c.OldReviewerID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.ReassignReviewer(ctx, c.PullRequestID, c.OldReviewerID, c.NewReviewerID); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *prRepoFake) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
@@ -104,6 +225,32 @@ func (r *prRepoFake) RemoveReviewer(ctx context.Context, prID, reviewerID string
 	return nil
 }
 
+type fakeEventRepo struct {
+	events []domain.ReviewerEvent
+	err    error
+}
+
+func (r *fakeEventRepo) Append(ctx context.Context, events ...domain.ReviewerEvent) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, e := range events {
+		e.ID = int64(len(r.events) + 1)
+		r.events = append(r.events, e)
+	}
+	return nil
+}
+
+func (r *fakeEventRepo) ListByPR(ctx context.Context, prID string) ([]domain.ReviewerEvent, error) {
+	var res []domain.ReviewerEvent
+	for _, e := range r.events {
+		if e.PullRequestID == prID {
+			res = append(res, e)
+		}
+	}
+	return res, nil
+}
+
 type userRepoFake struct {
 	usersByID    map[string]domain.User
 	activeByTeam map[string][]domain.User
@@ -129,14 +276,39 @@ func (r *userRepoFake) ListActiveByTeam(ctx context.Context, teamName string) ([
 	return r.activeByTeam[teamName], nil
 }
 
-func (r *userRepoFake) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
+func (r *userRepoFake) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, bool, error) {
 	u, ok := r.usersByID[userID]
 	if !ok {
-		return domain.User{}, domain.ErrNotFound
+		return domain.User{}, false, domain.ErrNotFound
 	}
+	wasActive := u.IsActive
 	u.IsActive = isActive
 	r.usersByID[userID] = u
-	return u, nil
+	return u, wasActive, nil
+}
+
+func (r *userRepoFake) DeactivateMany(ctx context.Context, teamName string, userIDs []string) ([]domain.User, map[string]bool, error) {
+	var res []domain.User
+	wasActive := make(map[string]bool)
+	for _, id := range userIDs {
+		u, ok := r.usersByID[id]
+		if !ok || u.TeamName != teamName {
+			continue
+		}
+		wasActive[id] = u.IsActive
+		u.IsActive = false
+		r.usersByID[id] = u
+		res = append(res, u)
+
+		active := r.activeByTeam[teamName][:0]
+		for _, a := range r.activeByTeam[teamName] {
+			if a.ID != id {
+				active = append(active, a)
+			}
+		}
+		r.activeByTeam[teamName] = active
+	}
+	return res, wasActive, nil
 }
 
 func TestPRService_CreatePR_AssignReviewers(t *testing.T) {
@@ -193,7 +365,7 @@ func TestPRService_CreatePR_AssignReviewers(t *testing.T) {
 			}
 
 			prRepo := &prRepoFake{}
-			svc := NewPRService(prRepo, userRepo, nil)
+			svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 
 			pr := &domain.PullRequest{
 				ID:       "pr-" + tt.name,
@@ -201,7 +373,7 @@ func TestPRService_CreatePR_AssignReviewers(t *testing.T) {
 				AuthorID: tt.authorID,
 			}
 
-			created, err := svc.CreatePR(ctx, pr)
+			created, err := svc.CreatePR(ctx, pr, CreatePROptions{})
 			if err != nil {
 				t.Fatalf("CreatePR error: %v", err)
 			}
@@ -249,14 +421,67 @@ func TestPRService_CreatePR_RepoError(t *testing.T) {
 		},
 	}
 
-	svc := NewPRService(prRepo, userRepo, nil)
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 	pr := &domain.PullRequest{ID: "pr-fail", Name: "fail", AuthorID: "u1"}
 
-	if _, err := svc.CreatePR(ctx, pr); !errors.Is(err, repoErr) {
+	if _, err := svc.CreatePR(ctx, pr, CreatePROptions{}); !errors.Is(err, repoErr) {
 		t.Fatalf("expected repo error, got %v", err)
 	}
 }
 
+// Настройки, сохранённые до проверки required_approvals <= min_reviewers: PR не должен открыться
+// с меньшим числом ревьюверов, чем нужно апрувов.
+func TestPRService_CreatePR_RequiresReviewersForApprovals(t *testing.T) {
+	ctx := context.Background()
+
+	team := []domain.User{
+		{ID: "author", TeamName: "team", IsActive: true},
+		{ID: "u1", TeamName: "team", IsActive: true},
+		{ID: "u2", TeamName: "team", IsActive: true},
+	}
+	legacy := domain.TeamSettings{
+		AssignmentStrategy: domain.AssignmentStrategyRandom,
+		MaxReviewers:       2,
+		RequiredApprovals:  2,
+	}
+
+	newSvc := func(active []domain.User) (*PRService, *prRepoFake) {
+		users := make(map[string]domain.User)
+		for _, u := range team {
+			users[u.ID] = u
+		}
+		prRepo := &prRepoFake{}
+		userRepo := &userRepoFake{usersByID: users, activeByTeam: map[string][]domain.User{"team": active}}
+		teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"team": legacy}}
+		return NewPRService(prRepo, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{}), prRepo
+	}
+
+	svc, _ := newSvc(team)
+	one := 1
+	_, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR", AuthorID: "author"}, CreatePROptions{ReviewersCount: &one})
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("reviewers_count below required_approvals: expected ErrInvalidInput, got %v", err)
+	}
+
+	svc, prRepo := newSvc(team[:2])
+	_, err = svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "PR", AuthorID: "author"}, CreatePROptions{})
+	if !errors.Is(err, domain.ErrNotEnoughReviewers) {
+		t.Fatalf("one candidate for two approvals: expected ErrNotEnoughReviewers, got %v", err)
+	}
+	if _, ok := prRepo.prs["pr-2"]; ok {
+		t.Fatal("PR must not be created without enough reviewers for required approvals")
+	}
+
+	if err := (domain.TeamSettings{
+		AssignmentStrategy: domain.AssignmentStrategyRandom,
+		MinReviewers:       1,
+		MaxReviewers:       2,
+		RequiredApprovals:  2,
+	}).Validate(); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("required_approvals above min_reviewers: expected ErrInvalidInput, got %v", err)
+	}
+}
+
 func TestPRService_ReassignReviewer(t *testing.T) {
 	ctx := context.Background()
 
@@ -291,7 +516,7 @@ func TestPRService_ReassignReviewer(t *testing.T) {
 		},
 	}
 
-	svc := NewPRService(prRepo, userRepo, nil)
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 
 	updated, newID, err := svc.ReassignReviewer(ctx, "pr-1", "u2")
 	if err != nil {
@@ -347,7 +572,7 @@ func TestPRService_ReassignReviewer_ReusesExisting(t *testing.T) {
 		},
 	}
 
-	svc := NewPRService(prRepo, userRepo, nil)
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 
 	updated, newID, err := svc.ReassignReviewer(ctx, "pr-small", "u2")
 	if err != nil {
@@ -393,7 +618,7 @@ func TestPRService_ReassignReviewer_Merged(t *testing.T) {
 		},
 	}
 
-	svc := NewPRService(prRepo, userRepo, nil)
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 
 	_, _, err := svc.ReassignReviewer(ctx, "pr-merged", "u2")
 	if !errors.Is(err, domain.ErrPRMerged) {
@@ -410,19 +635,25 @@ func TestPRService_Merge(t *testing.T) {
 			"pr-merge": {
 				ID:        "pr-merge",
 				Name:      "Merge test",
+				AuthorID:  "author",
 				Status:    domain.PullRequestStatusOpen,
 				CreatedAt: now,
 			},
 			"pr-merged": {
 				ID:        "pr-merged",
 				Name:      "Already merged",
+				AuthorID:  "author",
 				Status:    domain.PullRequestStatusMerged,
 				CreatedAt: now,
 			},
 		},
 	}
 
-	svc := NewPRService(prRepo, nil, nil)
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{"author": {ID: "author", TeamName: "team", IsActive: true}},
+	}
+
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
 
 	merged, err := svc.MergePR(ctx, "pr-merge")
 	if err != nil {
@@ -451,3 +682,514 @@ func TestPRService_Merge(t *testing.T) {
 		t.Fatalf("expected MERGED status on repeated merge, got %s and %s", first.Status, second.Status)
 	}
 }
+
+func TestPRService_CreatePR_ReviewerLimits(t *testing.T) {
+	ctx := context.Background()
+
+	users := []domain.User{
+		{ID: "author", TeamName: "sec", IsActive: true},
+		{ID: "u1", TeamName: "sec", IsActive: true},
+		{ID: "u2", TeamName: "sec", IsActive: true},
+		{ID: "u3", TeamName: "sec", IsActive: true},
+	}
+
+	one := 1
+	five := 5
+
+	tests := []struct {
+		name      string
+		settings  domain.TeamSettings
+		count     *int
+		wantCount int
+		wantErr   error
+	}{
+		{
+			name:      "team max",
+			settings:  domain.TeamSettings{AssignmentStrategy: domain.AssignmentStrategyRandom, MinReviewers: 3, MaxReviewers: 3},
+			wantCount: 3,
+		},
+		{
+			name:      "per PR override",
+			settings:  domain.TeamSettings{AssignmentStrategy: domain.AssignmentStrategyRandom, MinReviewers: 0, MaxReviewers: 3},
+			count:     &one,
+			wantCount: 1,
+		},
+		{
+			name:     "override out of range",
+			settings: domain.TeamSettings{AssignmentStrategy: domain.AssignmentStrategyRandom, MinReviewers: 0, MaxReviewers: 3},
+			count:    &five,
+			wantErr:  domain.ErrInvalidInput,
+		},
+		{
+			name:     "minimum not satisfied",
+			settings: domain.TeamSettings{AssignmentStrategy: domain.AssignmentStrategyRandom, MinReviewers: 4, MaxReviewers: 4},
+			wantErr:  domain.ErrNotEnoughReviewers,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &userRepoFake{
+				usersByID:    make(map[string]domain.User),
+				activeByTeam: map[string][]domain.User{"sec": users},
+			}
+			for _, u := range users {
+				userRepo.usersByID[u.ID] = u
+			}
+			teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"sec": tt.settings}}
+			prRepo := &prRepoFake{}
+
+			svc := NewPRService(prRepo, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+			pr := &domain.PullRequest{ID: "pr-limits", Name: "Limits", AuthorID: "author"}
+			created, err := svc.CreatePR(ctx, pr, CreatePROptions{ReviewersCount: tt.count})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+				if _, ok := prRepo.prs[pr.ID]; ok {
+					t.Fatalf("PR must not be stored on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CreatePR error: %v", err)
+			}
+
+			if len(created.AssignedReviewers) != tt.wantCount {
+				t.Fatalf("expected %d reviewers, got %v", tt.wantCount, created.AssignedReviewers)
+			}
+		})
+	}
+}
+
+func TestPRService_CreatePR_CapacityAndFallback(t *testing.T) {
+	ctx := context.Background()
+
+	backend := []domain.User{
+		{ID: "author", TeamName: "backend", IsActive: true},
+		{ID: "busy", TeamName: "backend", IsActive: true, MaxOpenReviews: 1},
+	}
+	platform := []domain.User{
+		{ID: "p1", TeamName: "platform", IsActive: true},
+	}
+
+	userRepo := &userRepoFake{
+		usersByID: make(map[string]domain.User),
+		activeByTeam: map[string][]domain.User{
+			"backend":  backend,
+			"platform": platform,
+		},
+	}
+	for _, u := range append(append([]domain.User(nil), backend...), platform...) {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	backendSettings := domain.DefaultTeamSettings()
+	backendSettings.FallbackTeam = "platform"
+	teamRepo := &fakeTeamRepo{
+		settings: map[string]domain.TeamSettings{
+			"backend":  backendSettings,
+			"platform": domain.DefaultTeamSettings(),
+		},
+	}
+
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-old": {ID: "pr-old", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{
+			"pr-old": {"busy"},
+		},
+	}
+
+	svc := NewPRService(prRepo, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	created, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-new", Name: "New", AuthorID: "author"}, CreatePROptions{})
+	if err != nil {
+		t.Fatalf("CreatePR error: %v", err)
+	}
+
+	if len(created.AssignedReviewers) != 1 || created.AssignedReviewers[0] != "p1" {
+		t.Fatalf("expected fallback reviewer [p1] because busy is at capacity, got %v", created.AssignedReviewers)
+	}
+}
+
+func TestPRService_ReassignReviewer_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-1": {ID: "pr-1", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{
+			"pr-1": {"u2"},
+		},
+	}
+
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"author": {ID: "author", TeamName: "backend", IsActive: true},
+			"u2":     {ID: "u2", TeamName: "backend", IsActive: true},
+			"p1":     {ID: "p1", TeamName: "platform", IsActive: true},
+		},
+		activeByTeam: map[string][]domain.User{
+			"backend": {
+				{ID: "author", TeamName: "backend", IsActive: true},
+				{ID: "u2", TeamName: "backend", IsActive: true},
+			},
+			"platform": {
+				{ID: "p1", TeamName: "platform", IsActive: true},
+			},
+		},
+	}
+
+	backendSettings := domain.DefaultTeamSettings()
+	backendSettings.FallbackTeam = "platform"
+	teamRepo := &fakeTeamRepo{
+		settings: map[string]domain.TeamSettings{"backend": backendSettings},
+	}
+
+	svc := NewPRService(prRepo, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	_, newID, err := svc.ReassignReviewer(ctx, "pr-1", "u2")
+	if err != nil {
+		t.Fatalf("ReassignReviewer error: %v", err)
+	}
+	if newID != "p1" {
+		t.Fatalf("expected fallback reviewer p1, got %s", newID)
+	}
+}
+
+func TestPRService_LocksPRBeforeChanges(t *testing.T) {
+	ctx := context.Background()
+
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-1": {ID: "pr-1", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{
+			"pr-1": {"u2"},
+		},
+	}
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"author": {ID: "author", TeamName: "other", IsActive: true},
+			"u2":     {ID: "u2", TeamName: "team", IsActive: true},
+			"u3":     {ID: "u3", TeamName: "team", IsActive: true},
+		},
+		activeByTeam: map[string][]domain.User{
+			"team": {
+				{ID: "u2", TeamName: "team", IsActive: true},
+				{ID: "u3", TeamName: "team", IsActive: true},
+			},
+		},
+	}
+
+	tx := &fakeTxManager{}
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, tx)
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "u2"); err != nil {
+		t.Fatalf("ReassignReviewer error: %v", err)
+	}
+	if _, err := svc.MergePR(ctx, "pr-1"); err != nil {
+		t.Fatalf("MergePR error: %v", err)
+	}
+
+	if len(prRepo.locked) != 2 || prRepo.locked[0] != "pr-1" || prRepo.locked[1] != "pr-1" {
+		t.Fatalf("expected pr-1 to be locked by reassign and merge, got %v", prRepo.locked)
+	}
+	if tx.calls != 2 {
+		t.Fatalf("expected each operation in its own transaction, got %d", tx.calls)
+	}
+}
+
+func TestPRService_Lifecycle(t *testing.T) {
+	ctx := context.Background()
+
+	team := []domain.User{
+		{ID: "author", TeamName: "team", IsActive: true},
+		{ID: "r1", TeamName: "team", IsActive: true},
+		{ID: "r2", TeamName: "team", IsActive: true},
+	}
+	userRepo := &userRepoFake{
+		usersByID:    make(map[string]domain.User),
+		activeByTeam: map[string][]domain.User{"team": team},
+	}
+	for _, u := range team {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	prRepo := &prRepoFake{}
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	draft, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "draft", AuthorID: "author"}, CreatePROptions{Draft: true})
+	if err != nil {
+		t.Fatalf("CreatePR draft error: %v", err)
+	}
+	if draft.Status != domain.PullRequestStatusDraft || len(draft.AssignedReviewers) != 0 {
+		t.Fatalf("expected DRAFT without reviewers, got %s %v", draft.Status, draft.AssignedReviewers)
+	}
+
+	if _, err := svc.MergePR(ctx, "pr-1"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("merge of draft: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "r1"); !errors.Is(err, domain.ErrPRNotOpen) {
+		t.Fatalf("reassign on draft: expected ErrPRNotOpen, got %v", err)
+	}
+
+	ready, err := svc.MarkReady(ctx, "pr-1", nil)
+	if err != nil {
+		t.Fatalf("MarkReady error: %v", err)
+	}
+	if ready.Status != domain.PullRequestStatusOpen || len(ready.AssignedReviewers) != 2 {
+		t.Fatalf("expected OPEN with 2 reviewers, got %s %v", ready.Status, ready.AssignedReviewers)
+	}
+
+	closed, err := svc.ClosePR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("ClosePR error: %v", err)
+	}
+	if closed.Status != domain.PullRequestStatusClosed || closed.ClosedAt == nil {
+		t.Fatalf("expected CLOSED with closed_at, got %s %v", closed.Status, closed.ClosedAt)
+	}
+	if load, _ := prRepo.OpenReviewCounts(ctx, []string{"r1", "r2"}); load["r1"] != 0 || load["r2"] != 0 {
+		t.Fatalf("closed pr must not count as open review, got %v", load)
+	}
+	if _, err := svc.ClosePR(ctx, "pr-1"); err != nil {
+		t.Fatalf("repeated ClosePR error: %v", err)
+	}
+	if _, err := svc.MarkReady(ctx, "pr-1", nil); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("ready on closed: expected ErrInvalidTransition, got %v", err)
+	}
+
+	reopened, err := svc.ReopenPR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("ReopenPR error: %v", err)
+	}
+	if reopened.Status != domain.PullRequestStatusOpen || reopened.ClosedAt != nil || len(reopened.AssignedReviewers) != 2 {
+		t.Fatalf("expected OPEN with previous reviewers, got %+v", reopened)
+	}
+
+	if _, err := svc.MergePR(ctx, "pr-1"); err != nil {
+		t.Fatalf("MergePR error: %v", err)
+	}
+	if _, err := svc.ReopenPR(ctx, "pr-1"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("reopen of merged: expected ErrInvalidTransition, got %v", err)
+	}
+	if _, err := svc.ClosePR(ctx, "pr-1"); !errors.Is(err, domain.ErrInvalidTransition) {
+		t.Fatalf("close of merged: expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestPRService_MergeRequiresApprovals(t *testing.T) {
+	ctx := context.Background()
+
+	team := []domain.User{
+		{ID: "author", TeamName: "team", IsActive: true},
+		{ID: "r1", TeamName: "team", IsActive: true},
+		{ID: "r2", TeamName: "team", IsActive: true},
+	}
+	userRepo := &userRepoFake{
+		usersByID:    make(map[string]domain.User),
+		activeByTeam: map[string][]domain.User{"team": team},
+	}
+	for _, u := range team {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	settings := domain.DefaultTeamSettings()
+	settings.RequiredApprovals = 2
+	teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"team": settings}}
+
+	prRepo := &prRepoFake{}
+	svc := NewPRService(prRepo, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	if _, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR", AuthorID: "author"}, CreatePROptions{}); err != nil {
+		t.Fatalf("CreatePR error: %v", err)
+	}
+
+	if _, err := svc.SubmitReview(ctx, "pr-1", "author", domain.ReviewDecisionApproved); !errors.Is(err, domain.ErrNotAssigned) {
+		t.Fatalf("review by non-reviewer: expected ErrNotAssigned, got %v", err)
+	}
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r1", "LGTM"); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("unknown decision: expected ErrInvalidInput, got %v", err)
+	}
+
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r1", domain.ReviewDecisionApproved); err != nil {
+		t.Fatalf("SubmitReview r1 error: %v", err)
+	}
+	if _, err := svc.SubmitReview(ctx, "pr-1", "r2", domain.ReviewDecisionChangesRequested); err != nil {
+		t.Fatalf("SubmitReview r2 error: %v", err)
+	}
+	if _, err := svc.MergePR(ctx, "pr-1"); !errors.Is(err, domain.ErrNotApproved) {
+		t.Fatalf("merge with changes requested: expected ErrNotApproved, got %v", err)
+	}
+
+	pr, err := svc.SubmitReview(ctx, "pr-1", "r2", domain.ReviewDecisionApproved)
+	if err != nil {
+		t.Fatalf("SubmitReview r2 error: %v", err)
+	}
+	if len(pr.Reviews) != 2 {
+		t.Fatalf("expected 2 review decisions, got %v", pr.Reviews)
+	}
+
+	merged, err := svc.MergePR(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("MergePR error: %v", err)
+	}
+	if merged.Status != domain.PullRequestStatusMerged {
+		t.Fatalf("expected MERGED, got %s", merged.Status)
+	}
+}
+
+func TestPRService_History(t *testing.T) {
+	ctx := domain.WithActor(context.Background(), "lead")
+
+	team := []domain.User{
+		{ID: "author", TeamName: "team", IsActive: true},
+		{ID: "r1", TeamName: "team", IsActive: true},
+		{ID: "r2", TeamName: "team", IsActive: true},
+		{ID: "r3", TeamName: "team", IsActive: true},
+	}
+	userRepo := &userRepoFake{
+		usersByID:    make(map[string]domain.User),
+		activeByTeam: map[string][]domain.User{"team": team},
+	}
+	for _, u := range team {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	prRepo := &prRepoFake{}
+	events := &fakeEventRepo{}
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, events, nil, nil, &fakeTxManager{})
+
+	created, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "PR", AuthorID: "author"}, CreatePROptions{})
+	if err != nil {
+		t.Fatalf("CreatePR error: %v", err)
+	}
+	old := created.AssignedReviewers[0]
+
+	_, newID, err := svc.ReassignReviewer(ctx, "pr-1", old)
+	if err != nil {
+		t.Fatalf("ReassignReviewer error: %v", err)
+	}
+
+	history, err := svc.History(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("History error: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected 2 assigned + 1 replaced events, got %+v", history)
+	}
+	for _, e := range history[:2] {
+		if e.Type != domain.ReviewerEventAssigned || e.Reason != domain.ReviewerEventReasonPRCreated {
+			t.Fatalf("expected assigned/pr_created, got %+v", e)
+		}
+	}
+
+	last := history[2]
+	if last.Type != domain.ReviewerEventReplaced || last.ReviewerID != old || last.ReplacedBy != newID {
+		t.Fatalf("expected %s replaced by %s, got %+v", old, newID, last)
+	}
+	if last.ActorID != "lead" || last.Reason != domain.ReviewerEventReasonReassign {
+		t.Fatalf("expected actor lead and reason reassign, got %+v", last)
+	}
+
+	if _, err := svc.History(ctx, "missing"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("history of unknown pr: expected ErrNotFound, got %v", err)
+	}
+}
+
+// rollbackTxManager ведёт себя как настоящая транзакция для prRepoFake:
+// если fn вернула ошибку, всё, что она успела записать, откатывается.
+type rollbackTxManager struct {
+	prRepo *prRepoFake
+}
+
+func (m *rollbackTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	snapshot := m.prRepo.clone()
+	if err := fn(ctx); err != nil {
+		*m.prRepo = snapshot
+		return err
+	}
+	return nil
+}
+
+func (r *prRepoFake) clone() prRepoFake {
+	c := *r
+	c.prs = make(map[string]domain.PullRequest, len(r.prs))
+	for id, pr := range r.prs {
+		c.prs[id] = pr
+	}
+	c.reviewers = make(map[string][]string, len(r.reviewers))
+	for id, list := range r.reviewers {
+		c.reviewers[id] = append([]string(nil), list...)
+	}
+	c.reviews = make(map[string]map[string]domain.ReviewDecision, len(r.reviews))
+	for id, byReviewer := range r.reviews {
+		c.reviews[id] = make(map[string]domain.ReviewDecision, len(byReviewer))
+		for reviewerID, d := range byReviewer {
+			c.reviews[id][reviewerID] = d
+		}
+	}
+	return c
+}
+
+func newRollbackFixture() (*PRService, *prRepoFake, *fakeEventRepo) {
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-1": {ID: "pr-1", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{"pr-1": {"r1"}},
+	}
+	users := []domain.User{
+		{ID: "author", TeamName: "core", IsActive: true},
+		{ID: "r1", TeamName: "core", IsActive: true},
+		{ID: "r2", TeamName: "core", IsActive: true},
+	}
+	userRepo := &userRepoFake{
+		usersByID:    map[string]domain.User{},
+		activeByTeam: map[string][]domain.User{"core": users},
+	}
+	for _, u := range users {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	events := &fakeEventRepo{}
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, events, nil, nil, &rollbackTxManager{prRepo: prRepo})
+	return svc, prRepo, events
+}
+
+func TestPRService_CreatePR_RollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, prRepo, events := newRollbackFixture()
+
+	// История пишется уже после prRepo.Create и AssignReviewers.
+	events.err = errors.New("history is unavailable")
+
+	_, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "Orphan", AuthorID: "author"}, CreatePROptions{})
+	if !errors.Is(err, events.err) {
+		t.Fatalf("expected history error, got %v", err)
+	}
+	if _, ok := prRepo.prs["pr-2"]; ok {
+		t.Fatalf("failed CreatePR must not leave a PR behind")
+	}
+	if _, ok := prRepo.reviewers["pr-2"]; ok {
+		t.Fatalf("failed CreatePR must not leave reviewers behind, got %v", prRepo.reviewers["pr-2"])
+	}
+}
+
+func TestPRService_ReassignReviewer_RollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, prRepo, events := newRollbackFixture()
+
+	events.err = errors.New("history is unavailable")
+
+	if _, _, err := svc.ReassignReviewer(ctx, "pr-1", "r1"); !errors.Is(err, events.err) {
+		t.Fatalf("expected history error, got %v", err)
+	}
+	if got := prRepo.reviewers["pr-1"]; len(got) != 1 || got[0] != "r1" {
+		t.Fatalf("failed reassign must keep reviewers unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// ReviewerSelector выбирает до n ревьюверов из уже отфильтрованных кандидатов
+// (без автора, неактивных и уже назначенных).
+type ReviewerSelector interface {
+	Select(ctx context.Context, teamName string, candidates []domain.User, n int) ([]domain.User, error)
+}
+
+type ReviewerSelectors map[domain.AssignmentStrategy]ReviewerSelector
+
+func DefaultReviewerSelectors(prRepo domain.PullRequestRepository, teamRepo domain.TeamRepository) ReviewerSelectors {
+	return ReviewerSelectors{
+		domain.AssignmentStrategyRandom:      NewRandomSelector(),
+		domain.AssignmentStrategyRoundRobin:  NewRoundRobinSelector(teamRepo),
+		domain.AssignmentStrategyWeighted:    NewWeightedSelector(),
+		domain.AssignmentStrategyLeastLoaded: NewLeastLoadedSelector(prRepo),
+	}
+}
+
+func newRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) Select(ctx context.Context, teamName string, candidates []domain.User, n int) ([]domain.User, error) {
+	shuffled := append([]domain.User(nil), candidates...)
+	if len(shuffled) > 1 {
+		r := newRand()
+		r.Shuffle(len(shuffled), func(i, j int) {
+			shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		})
+	}
+
+	if len(shuffled) > n {
+		shuffled = shuffled[:n]
+	}
+	return shuffled, nil
+}
+
+// RoundRobinSelector идёт по участникам команды по кругу в порядке user_id. В команде хранится
+// user_id последнего назначенного, и следующим берётся первый кандидат после него: автор и
+// недоступные участники, которых нет среди кандидатов, не сдвигают круг. Позиция общая для всех
+// экземпляров сервиса, параллельные выборы для одной команды идут по очереди.
+type RoundRobinSelector struct {
+	teamRepo domain.TeamRepository
+}
+
+func NewRoundRobinSelector(teamRepo domain.TeamRepository) *RoundRobinSelector {
+	return &RoundRobinSelector{teamRepo: teamRepo}
+}
+
+func (s *RoundRobinSelector) Select(ctx context.Context, teamName string, candidates []domain.User, n int) ([]domain.User, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	ordered := append([]domain.User(nil), candidates...)
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].ID < ordered[j].ID
+	})
+
+	if n > len(ordered) {
+		n = len(ordered)
+	}
+
+	last, err := s.teamRepo.RoundRobinCursor(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	start := sort.Search(len(ordered), func(i int) bool {
+		return ordered[i].ID > last
+	})
+
+	res := make([]domain.User, 0, n)
+	for i := 0; i < n; i++ {
+		res = append(res, ordered[(start+i)%len(ordered)])
+	}
+
+	if err := s.teamRepo.SetRoundRobinCursor(ctx, teamName, res[n-1].ID); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// WeightedSelector делает случайную выборку без возвращения,
+// где шанс участника пропорционален его review_weight.
+type WeightedSelector struct{}
+
+func NewWeightedSelector() *WeightedSelector {
+	return &WeightedSelector{}
+}
+
+func (s *WeightedSelector) Select(ctx context.Context, teamName string, candidates []domain.User, n int) ([]domain.User, error) {
+	pool := append([]domain.User(nil), candidates...)
+	r := newRand()
+
+	res := make([]domain.User, 0, n)
+	for len(res) < n && len(pool) > 0 {
+		total := 0
+		for _, u := range pool {
+			total += reviewWeight(u)
+		}
+
+		pick := r.Intn(total)
+		idx := 0
+		for i, u := range pool {
+			pick -= reviewWeight(u)
+			if pick < 0 {
+				idx = i
+				break
+			}
+		}
+
+		res = append(res, pool[idx])
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+
+	return res, nil
+}
+
+// LeastLoadedSelector отдаёт предпочтение тем, у кого меньше всего OPEN PR на ревью.
+// При равной нагрузке порядок случайный.
+type LeastLoadedSelector struct {
+	prRepo domain.PullRequestRepository
+}
+
+func NewLeastLoadedSelector(prRepo domain.PullRequestRepository) *LeastLoadedSelector {
+	return &LeastLoadedSelector{prRepo: prRepo}
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, teamName string, candidates []domain.User, n int) ([]domain.User, error) {
+	if len(candidates) == 0 || n <= 0 {
+		return nil, nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, u := range candidates {
+		ids = append(ids, u.ID)
+	}
+
+	load, err := s.prRepo.OpenReviewCounts(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered := append([]domain.User(nil), candidates...)
+	r := newRand()
+	r.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return load[ordered[i].ID] < load[ordered[j].ID]
+	})
+
+	if len(ordered) > n {
+		ordered = ordered[:n]
+	}
+	return ordered, nil
+}
+
+func reviewWeight(u domain.User) int {
+	if u.ReviewWeight <= 0 {
+		return domain.DefaultReviewWeight
+	}
+	return u.ReviewWeight
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+func TestRoundRobinSelector_Rotates(t *testing.T) {
+	ctx := context.Background()
+	teamRepo := &fakeTeamRepo{}
+	sel := NewRoundRobinSelector(teamRepo)
+
+	candidates := []domain.User{
+		{ID: "u3"},
+		{ID: "u1"},
+		{ID: "u2"},
+	}
+
+	want := [][]string{
+		{"u1", "u2"},
+		{"u3", "u1"},
+		{"u2", "u3"},
+	}
+
+	for i, w := range want {
+		got, err := sel.Select(ctx, "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("round %d: Select error: %v", i, err)
+		}
+		if len(got) != len(w) || got[0].ID != w[0] || got[1].ID != w[1] {
+			t.Fatalf("round %d: expected %v, got %v", i, w, got)
+		}
+	}
+
+	other, err := sel.Select(ctx, "other", candidates, 1)
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if other[0].ID != "u1" {
+		t.Fatalf("expected independent cursor per team, got %s", other[0].ID)
+	}
+
+	// Позиция живёт в команде, а не в селекторе: другой экземпляр продолжает тот же круг.
+	next, err := NewRoundRobinSelector(teamRepo).Select(ctx, "team", candidates, 1)
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if next[0].ID != "u1" {
+		t.Fatalf("expected shared cursor to continue with u1, got %s", next[0].ID)
+	}
+}
+
+func TestRoundRobinSelector_SkipsAuthorWithoutBreakingRotation(t *testing.T) {
+	ctx := context.Background()
+	sel := NewRoundRobinSelector(&fakeTeamRepo{})
+
+	all := []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}, {ID: "u4"}}
+	without := func(authorID string) []domain.User {
+		res := make([]domain.User, 0, len(all))
+		for _, u := range all {
+			if u.ID != authorID {
+				res = append(res, u)
+			}
+		}
+		return res
+	}
+
+	// Автор в середине порядка и меняется от PR к PR: круг не должен пропускать
+	// и повторять остальных.
+	rounds := []struct {
+		author string
+		want   string
+	}{
+		{"u2", "u1"},
+		{"u2", "u3"},
+		{"u1", "u4"},
+		{"u3", "u1"},
+		{"u3", "u2"},
+		{"u3", "u4"},
+		{"u4", "u1"},
+	}
+
+	for i, r := range rounds {
+		got, err := sel.Select(ctx, "team", without(r.author), 1)
+		if err != nil {
+			t.Fatalf("round %d: Select error: %v", i, err)
+		}
+		if got[0].ID != r.want {
+			t.Fatalf("round %d (author %s): expected %s, got %s", i, r.author, r.want, got[0].ID)
+		}
+	}
+}
+
+func TestWeightedSelector_DistinctAndBounded(t *testing.T) {
+	ctx := context.Background()
+	sel := NewWeightedSelector()
+
+	candidates := []domain.User{
+		{ID: "heavy", ReviewWeight: 100},
+		{ID: "light", ReviewWeight: 1},
+		{ID: "default"},
+	}
+
+	for i := 0; i < 50; i++ {
+		got, err := sel.Select(ctx, "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("Select error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", got)
+		}
+		if got[0].ID == got[1].ID {
+			t.Fatalf("expected distinct reviewers, got %v", got)
+		}
+	}
+
+	all, err := sel.Select(ctx, "team", candidates, 10)
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if len(all) != len(candidates) {
+		t.Fatalf("expected all %d candidates, got %v", len(candidates), all)
+	}
+}
+
+func TestLeastLoadedSelector_PrefersIdleReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	open := domain.PullRequest{Status: domain.PullRequestStatusOpen}
+	merged := domain.PullRequest{Status: domain.PullRequestStatusMerged}
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-1": open,
+			"pr-2": open,
+			"pr-3": open,
+			"pr-4": merged,
+		},
+		reviewers: map[string][]string{
+			"pr-1": {"busy", "medium"},
+			"pr-2": {"busy"},
+			"pr-3": {"busy"},
+			"pr-4": {"idle", "idle2"},
+		},
+	}
+
+	sel := NewLeastLoadedSelector(prRepo)
+	candidates := []domain.User{{ID: "busy"}, {ID: "medium"}, {ID: "idle"}, {ID: "idle2"}}
+
+	for i := 0; i < 20; i++ {
+		got, err := sel.Select(ctx, "team", candidates, 2)
+		if err != nil {
+			t.Fatalf("Select error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 reviewers, got %v", got)
+		}
+		for _, u := range got {
+			if u.ID != "idle" && u.ID != "idle2" {
+				t.Fatalf("expected only idle reviewers (merged PRs do not count), got %v", got)
+			}
+		}
+	}
+
+	got, err := sel.Select(ctx, "team", candidates, 3)
+	if err != nil {
+		t.Fatalf("Select error: %v", err)
+	}
+	if got[2].ID != "medium" {
+		t.Fatalf("expected medium as third reviewer, got %v", got)
+	}
+}
+
+func TestPRService_CreatePR_UsesTeamStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	users := []domain.User{
+		{ID: "author", TeamName: "rr", IsActive: true},
+		{ID: "u1", TeamName: "rr", IsActive: true},
+		{ID: "u2", TeamName: "rr", IsActive: true},
+		{ID: "u3", TeamName: "rr", IsActive: true},
+	}
+	userRepo := &userRepoFake{
+		usersByID:    make(map[string]domain.User),
+		activeByTeam: map[string][]domain.User{"rr": users},
+	}
+	for _, u := range users {
+		userRepo.usersByID[u.ID] = u
+	}
+
+	settings := domain.DefaultTeamSettings()
+	settings.AssignmentStrategy = domain.AssignmentStrategyRoundRobin
+	teamRepo := &fakeTeamRepo{
+		settings: map[string]domain.TeamSettings{"rr": settings},
+	}
+
+	svc := NewPRService(&prRepoFake{}, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	first, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-1", Name: "one", AuthorID: "author"}, CreatePROptions{})
+	if err != nil {
+		t.Fatalf("CreatePR error: %v", err)
+	}
+	second, err := svc.CreatePR(ctx, &domain.PullRequest{ID: "pr-2", Name: "two", AuthorID: "author"}, CreatePROptions{})
+	if err != nil {
+		t.Fatalf("CreatePR error: %v", err)
+	}
+
+	if got := first.AssignedReviewers; len(got) != 2 || got[0] != "u1" || got[1] != "u2" {
+		t.Fatalf("expected [u1 u2] for the first PR, got %v", got)
+	}
+	if got := second.AssignedReviewers; len(got) != 2 || got[0] != "u3" || got[1] != "u1" {
+		t.Fatalf("expected [u3 u1] for the second PR, got %v", got)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// CodeHostClient выставляет ревьюверов PR во внешней системе (GitHub, GitLab).
+type CodeHostClient interface {
+	// SetReviewers запрашивает ревью у недостающих из logins и снимает запрос только с replaced —
+	// ревьюверов, которых сервис назначал и потом заменил. Ревьюверов, запрошенных людьми,
+	// не трогает. Повторный вызов с теми же списками ничего не меняет.
+	SetReviewers(ctx context.Context, pr domain.ExternalPR, logins, replaced []string) error
+}
+
+type CodeHostClients map[domain.CodeHost]CodeHostClient
+
+type ReviewerSyncConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	BackoffBase    time.Duration
+	BackoffMax     time.Duration
+	RequestTimeout time.Duration
+}
+
+// ReviewerSyncWorker в фоне выгружает назначенных ревьюверов в PR на стороне GitHub/GitLab.
+// Неудачная попытка повторяется с экспоненциальной задержкой, после MaxAttempts PR получает
+// статус failed; ошибка видна в reviewer_sync самого PR.
+type ReviewerSyncWorker struct {
+	syncRepo  domain.ReviewerSyncRepository
+	prRepo    domain.PullRequestRepository
+	eventRepo domain.ReviewerEventRepository
+	accounts  domain.ExternalAccountRepository
+	clients   CodeHostClients
+	cfg       ReviewerSyncConfig
+}
+
+func NewReviewerSyncWorker(
+	syncRepo domain.ReviewerSyncRepository,
+	prRepo domain.PullRequestRepository,
+	eventRepo domain.ReviewerEventRepository,
+	accounts domain.ExternalAccountRepository,
+	clients CodeHostClients,
+	cfg ReviewerSyncConfig,
+) *ReviewerSyncWorker {
+	return &ReviewerSyncWorker{
+		syncRepo:  syncRepo,
+		prRepo:    prRepo,
+		eventRepo: eventRepo,
+		accounts:  accounts,
+		clients:   clients,
+		cfg:       cfg,
+	}
+}
+
+func (w *ReviewerSyncWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.SyncDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("reviewer sync: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncDue обрабатывает одну пачку PR, ожидающих выгрузки, и возвращает её размер.
+func (w *ReviewerSyncWorker) SyncDue(ctx context.Context) (int, error) {
+	lease := w.cfg.RequestTimeout + 30*time.Second
+	due, err := w.syncRepo.ClaimDue(ctx, w.cfg.BatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim reviewer sync: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, item := range due {
+		wg.Add(1)
+		go func(item domain.ReviewerSync) {
+			defer wg.Done()
+			if err := w.finish(ctx, item, w.sync(ctx, item.PullRequestID)); err != nil {
+				log.Printf("reviewer sync: update %s: %v", item.PullRequestID, err)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	return len(due), nil
+}
+
+// permanentSyncError — повторять бессмысленно, пока не поменяется конфигурация сервиса.
+type permanentSyncError struct {
+	msg string
+}
+
+func (e *permanentSyncError) Error() string { return e.msg }
+
+func (w *ReviewerSyncWorker) sync(ctx context.Context, prID string) error {
+	ext, ok := domain.ParseExternalPRID(prID)
+	if !ok {
+		return &permanentSyncError{msg: prID + " is not an external pull request"}
+	}
+	client, ok := w.clients[ext.Host]
+	if !ok {
+		return &permanentSyncError{msg: string(ext.Host) + " client is not configured"}
+	}
+
+	pr, err := w.prRepo.GetByID(ctx, prID)
+	if err != nil {
+		return err
+	}
+
+	logins := make([]string, 0, len(pr.AssignedReviewers))
+	var unlinked []string
+	for _, reviewerID := range pr.AssignedReviewers {
+		login, err := w.accounts.GetLogin(ctx, ext.Host, reviewerID)
+		if errors.Is(err, domain.ErrNotFound) {
+			unlinked = append(unlinked, reviewerID)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+
+	replaced, err := w.replacedLogins(ctx, ext.Host, pr)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, w.cfg.RequestTimeout)
+	defer cancel()
+	if err := client.SetReviewers(ctx, ext, logins, replaced); err != nil {
+		return err
+	}
+
+	// Привязанных выгрузили; остальных доберём на следующих попытках, если к тому времени их привяжут.
+	if len(unlinked) > 0 {
+		return fmt.Errorf("reviewers without %s account: %s", ext.Host, strings.Join(unlinked, ", "))
+	}
+	return nil
+}
+
+// replacedLogins — логины ревьюверов, которых сервис назначал на PR, а потом заменил или снял.
+// Только с них можно снимать запрос на стороне хоста.
+func (w *ReviewerSyncWorker) replacedLogins(ctx context.Context, host domain.CodeHost, pr domain.PullRequest) ([]string, error) {
+	events, err := w.eventRepo.ListByPR(ctx, pr.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[string]struct{}, len(pr.AssignedReviewers)+len(events))
+	for _, id := range pr.AssignedReviewers {
+		skip[id] = struct{}{}
+	}
+
+	var logins []string
+	for _, e := range events {
+		if e.Type != domain.ReviewerEventReplaced && e.Type != domain.ReviewerEventRemoved {
+			continue
+		}
+		if _, ok := skip[e.ReviewerID]; ok {
+			continue
+		}
+		skip[e.ReviewerID] = struct{}{}
+
+		login, err := w.accounts.GetLogin(ctx, host, e.ReviewerID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, login)
+	}
+	sort.Strings(logins)
+	return logins, nil
+}
+
+func (w *ReviewerSyncWorker) finish(ctx context.Context, item domain.ReviewerSync, syncErr error) error {
+	if syncErr == nil {
+		return w.syncRepo.MarkSynced(ctx, item.PullRequestID, item.Version)
+	}
+
+	attempts := item.Attempts + 1
+	var permanent *permanentSyncError
+	if attempts >= w.cfg.MaxAttempts || errors.As(syncErr, &permanent) {
+		return w.syncRepo.MarkFailed(ctx, item.PullRequestID, item.Version, attempts, syncErr.Error())
+	}
+
+	next := time.Now().UTC().Add(retryBackoff(attempts, w.cfg.BackoffBase, w.cfg.BackoffMax))
+	return w.syncRepo.MarkRetry(ctx, item.PullRequestID, item.Version, attempts, next, syncErr.Error())
+}
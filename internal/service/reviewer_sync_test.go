@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/codehost"
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeSyncRepo struct {
+	items map[string]*domain.ReviewerSync
+}
+
+func (r *fakeSyncRepo) Request(ctx context.Context, prID string) error {
+	if r.items == nil {
+		r.items = make(map[string]*domain.ReviewerSync)
+	}
+	item, ok := r.items[prID]
+	if !ok {
+		item = &domain.ReviewerSync{PullRequestID: prID}
+		r.items[prID] = item
+	}
+	item.Status = domain.ReviewerSyncPending
+	item.Version++
+	item.Attempts = 0
+	item.NextAttemptAt = time.Now().Add(-time.Second)
+	item.LastError = ""
+	return nil
+}
+
+func (r *fakeSyncRepo) Get(ctx context.Context, prID string) (domain.ReviewerSync, error) {
+	item, ok := r.items[prID]
+	if !ok {
+		return domain.ReviewerSync{}, domain.ErrNotFound
+	}
+	return *item, nil
+}
+
+func (r *fakeSyncRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.ReviewerSync, error) {
+	var res []domain.ReviewerSync
+	for _, item := range r.items {
+		if item.Status == domain.ReviewerSyncPending && !item.NextAttemptAt.After(time.Now()) && len(res) < limit {
+			item.NextAttemptAt = time.Now().Add(lease)
+			res = append(res, *item)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeSyncRepo) current(prID string, version int64) *domain.ReviewerSync {
+	item := r.items[prID]
+	if item == nil || item.Version != version {
+		return nil
+	}
+	return item
+}
+
+func (r *fakeSyncRepo) MarkSynced(ctx context.Context, prID string, version int64) error {
+	if item := r.current(prID, version); item != nil {
+		item.Status, item.Attempts, item.LastError = domain.ReviewerSyncSynced, item.Attempts+1, ""
+	}
+	return nil
+}
+
+func (r *fakeSyncRepo) MarkRetry(
+	ctx context.Context,
+	prID string,
+	version int64,
+	attempts int,
+	next time.Time,
+	lastError string,
+) error {
+	if item := r.current(prID, version); item != nil {
+		item.Attempts, item.NextAttemptAt, item.LastError = attempts, next, lastError
+	}
+	return nil
+}
+
+func (r *fakeSyncRepo) MarkFailed(ctx context.Context, prID string, version int64, attempts int, lastError string) error {
+	if item := r.current(prID, version); item != nil {
+		item.Status, item.Attempts, item.LastError = domain.ReviewerSyncFailed, attempts, lastError
+	}
+	return nil
+}
+
+type fakeAccountRepo struct {
+	accounts []domain.ExternalAccount
+}
+
+func (r *fakeAccountRepo) Upsert(ctx context.Context, account *domain.ExternalAccount) error {
+	r.accounts = append(r.accounts, *account)
+	return nil
+}
+
+func (r *fakeAccountRepo) GetUserID(ctx context.Context, provider domain.CodeHost, login string) (string, error) {
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.Login == login {
+			return a.UserID, nil
+		}
+	}
+	return "", domain.ErrNotFound
+}
+
+func (r *fakeAccountRepo) GetLogin(ctx context.Context, provider domain.CodeHost, userID string) (string, error) {
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.UserID == userID {
+			return a.Login, nil
+		}
+	}
+	return "", domain.ErrNotFound
+}
+
+func (r *fakeAccountRepo) List(ctx context.Context, provider domain.CodeHost) ([]domain.ExternalAccount, error) {
+	return r.accounts, nil
+}
+
+func newSyncFixture() (*prRepoFake, *userRepoFake, *fakeAccountRepo) {
+	userRepo := &userRepoFake{
+		usersByID:    make(map[string]domain.User),
+		activeByTeam: make(map[string][]domain.User),
+	}
+	for _, id := range []string{"u1", "u2", "u3"} {
+		u := domain.User{ID: id, TeamName: "core", IsActive: true}
+		userRepo.usersByID[id] = u
+		userRepo.activeByTeam["core"] = append(userRepo.activeByTeam["core"], u)
+	}
+
+	accounts := &fakeAccountRepo{accounts: []domain.ExternalAccount{
+		{Provider: domain.CodeHostGitHub, Login: "alice", UserID: "u2"},
+		{Provider: domain.CodeHostGitHub, Login: "bob", UserID: "u3"},
+	}}
+	return &prRepoFake{}, userRepo, accounts
+}
+
+func TestPRService_RequestsReviewerSyncForExternalPRs(t *testing.T) {
+	ctx := context.Background()
+	prRepo, userRepo, _ := newSyncFixture()
+	syncRepo := &fakeSyncRepo{}
+	svc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, syncRepo, nil, &fakeTxManager{})
+
+	for _, id := range []string{"github:acme/api#1", "pr-local"} {
+		if _, err := svc.CreatePR(ctx, &domain.PullRequest{ID: id, Name: "PR", AuthorID: "u1"}, CreatePROptions{}); err != nil {
+			t.Fatalf("CreatePR %s error: %v", id, err)
+		}
+	}
+
+	if len(syncRepo.items) != 1 {
+		t.Fatalf("expected sync only for external PR, got %v", syncRepo.items)
+	}
+	item, err := syncRepo.Get(ctx, "github:acme/api#1")
+	if err != nil || item.Status != domain.ReviewerSyncPending || item.Version != 1 {
+		t.Fatalf("expected pending sync, got %+v err=%v", item, err)
+	}
+}
+
+func TestReviewerSyncWorker_RetriesThenSyncs(t *testing.T) {
+	ctx := context.Background()
+	prRepo, _, accounts := newSyncFixture()
+	const prID = "github:acme/api#7"
+	prRepo.prs = map[string]domain.PullRequest{prID: {ID: prID, AuthorID: "u1", AssignedReviewers: []string{"u3", "u2"}}}
+
+	syncRepo := &fakeSyncRepo{}
+	_ = syncRepo.Request(ctx, prID)
+
+	client := codehost.NewFake()
+	client.Err, client.FailTimes = errors.New("502 bad gateway"), 1
+	worker := NewReviewerSyncWorker(syncRepo, prRepo, &fakeEventRepo{}, accounts, CodeHostClients{domain.CodeHostGitHub: client}, ReviewerSyncConfig{
+		BatchSize:      10,
+		MaxAttempts:    5,
+		BackoffBase:    time.Millisecond,
+		BackoffMax:     time.Millisecond,
+		RequestTimeout: time.Second,
+	})
+
+	if n, err := worker.SyncDue(ctx); err != nil || n != 1 {
+		t.Fatalf("SyncDue: n=%d err=%v", n, err)
+	}
+	item, _ := syncRepo.Get(ctx, prID)
+	if item.Status != domain.ReviewerSyncPending || item.Attempts != 1 || !strings.Contains(item.LastError, "502") {
+		t.Fatalf("expected retry after failure, got %+v", item)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if _, err := worker.SyncDue(ctx); err != nil {
+		t.Fatalf("SyncDue retry error: %v", err)
+	}
+	item, _ = syncRepo.Get(ctx, prID)
+	if item.Status != domain.ReviewerSyncSynced || item.LastError != "" {
+		t.Fatalf("expected synced, got %+v", item)
+	}
+	got, _ := client.Reviewers(prID)
+	if strings.Join(got, ",") != "alice,bob" {
+		t.Fatalf("expected sorted logins alice,bob, got %v", got)
+	}
+}
+
+func TestReviewerSyncWorker_StaleAttemptDoesNotOverwrite(t *testing.T) {
+	ctx := context.Background()
+	prRepo, _, accounts := newSyncFixture()
+	const prID = "github:acme/api#8"
+	prRepo.prs = map[string]domain.PullRequest{prID: {ID: prID, AuthorID: "u1", AssignedReviewers: []string{"u2"}}}
+
+	syncRepo := &fakeSyncRepo{}
+	_ = syncRepo.Request(ctx, prID)
+	claimed, _ := syncRepo.ClaimDue(ctx, 10, time.Minute)
+
+	// Пока шла попытка, ревьюверов сменили — результат старой версии не должен закрыть новую.
+	_ = syncRepo.Request(ctx, prID)
+
+	worker := NewReviewerSyncWorker(syncRepo, prRepo, &fakeEventRepo{}, accounts, CodeHostClients{domain.CodeHostGitHub: codehost.NewFake()}, ReviewerSyncConfig{
+		MaxAttempts:    5,
+		RequestTimeout: time.Second,
+	})
+	if err := worker.finish(ctx, claimed[0], nil); err != nil {
+		t.Fatalf("finish error: %v", err)
+	}
+
+	item, _ := syncRepo.Get(ctx, prID)
+	if item.Status != domain.ReviewerSyncPending || item.Version != 2 {
+		t.Fatalf("expected newer version to stay pending, got %+v", item)
+	}
+}
+
+func TestReviewerSyncWorker_FailsWithoutClient(t *testing.T) {
+	ctx := context.Background()
+	prRepo, _, accounts := newSyncFixture()
+	const prID = "gitlab:acme/api!3"
+	prRepo.prs = map[string]domain.PullRequest{prID: {ID: prID, AuthorID: "u1", AssignedReviewers: []string{"u2"}}}
+
+	syncRepo := &fakeSyncRepo{}
+	_ = syncRepo.Request(ctx, prID)
+
+	worker := NewReviewerSyncWorker(syncRepo, prRepo, &fakeEventRepo{}, accounts, CodeHostClients{domain.CodeHostGitHub: codehost.NewFake()}, ReviewerSyncConfig{
+		BatchSize:      10,
+		MaxAttempts:    5,
+		RequestTimeout: time.Second,
+	})
+	if _, err := worker.SyncDue(ctx); err != nil {
+		t.Fatalf("SyncDue error: %v", err)
+	}
+
+	item, _ := syncRepo.Get(ctx, prID)
+	if item.Status != domain.ReviewerSyncFailed || !strings.Contains(item.LastError, "not configured") {
+		t.Fatalf("expected permanent failure, got %+v", item)
+	}
+}
+
+func TestReviewerSyncWorker_RemovesOnlyReplacedReviewers(t *testing.T) {
+	ctx := context.Background()
+	prRepo, _, accounts := newSyncFixture()
+	accounts.accounts = append(accounts.accounts, domain.ExternalAccount{Provider: domain.CodeHostGitHub, Login: "dave", UserID: "u4"})
+	const prID = "github:acme/api#9"
+	prRepo.prs = map[string]domain.PullRequest{prID: {ID: prID, AuthorID: "u1", AssignedReviewers: []string{"u3"}}}
+
+	// Сервис назначил alice (u2), потом заменил её на bob (u3); dave запросили вручную на GitHub.
+	events := &fakeEventRepo{events: []domain.ReviewerEvent{
+		{PullRequestID: prID, Type: domain.ReviewerEventAssigned, ReviewerID: "u2"},
+		{PullRequestID: prID, Type: domain.ReviewerEventReplaced, ReviewerID: "u2", ReplacedBy: "u3"},
+	}}
+	client := codehost.NewFake()
+	ext, _ := domain.ParseExternalPRID(prID)
+	client.Request(ext, "alice", "dave")
+
+	syncRepo := &fakeSyncRepo{}
+	_ = syncRepo.Request(ctx, prID)
+	worker := NewReviewerSyncWorker(syncRepo, prRepo, events, accounts, CodeHostClients{domain.CodeHostGitHub: client}, ReviewerSyncConfig{
+		BatchSize:      10,
+		MaxAttempts:    5,
+		RequestTimeout: time.Second,
+	})
+	if _, err := worker.SyncDue(ctx); err != nil {
+		t.Fatalf("SyncDue error: %v", err)
+	}
+
+	got, _ := client.Reviewers(prID)
+	if strings.Join(got, ",") != "dave,bob" {
+		t.Fatalf("expected dave kept and alice replaced by bob, got %v", got)
+	}
+}
@@ -1,15 +1,43 @@
 package service
 
 type Services struct {
-	Team *TeamService
-	User *UserService
-	PR   *PRService
+	Team          *TeamService
+	User          *UserService
+	PR            *PRService
+	Idempotency   *IdempotencyService
+	Audit         *AuditService
+	Webhook       *WebhookService
+	Accounts      *AccountService
+	GitHub        *GitHubService
+	GitLab        *GitLabService
+	Notifications *NotificationService
+	Stats         *StatsService
 }
 
-func NewServices(team *TeamService, user *UserService, pr *PRService) *Services {
+func NewServices(
+	team *TeamService,
+	user *UserService,
+	pr *PRService,
+	idempotency *IdempotencyService,
+	audit *AuditService,
+	webhook *WebhookService,
+	accounts *AccountService,
+	github *GitHubService,
+	gitlab *GitLabService,
+	notifications *NotificationService,
+	stats *StatsService,
+) *Services {
 	return &Services{
-		Team: team,
-		User: user,
-		PR:   pr,
+		Team:          team,
+		User:          user,
+		PR:            pr,
+		Idempotency:   idempotency,
+		Audit:         audit,
+		Webhook:       webhook,
+		Accounts:      accounts,
+		GitHub:        github,
+		GitLab:        gitlab,
+		Notifications: notifications,
+		Stats:         stats,
 	}
 }
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+// slaActor — автор изменений, которые делает планировщик SLA; виден в истории ревьюверов.
+const slaActor = "system:sla"
+
+type SLAConfig struct {
+	Calendar      domain.WorkCalendar
+	CheckInterval time.Duration
+}
+
+// SLAScheduler отмечает назначения, по которым ревьювер не оставил решения за SLA команды,
+// при sla_auto_reassign передаёт ревью другому и публикует review.sla_breached —
+// по нему тимлид получает уведомление. Каждое назначение отмечается один раз.
+type SLAScheduler struct {
+	repo      domain.SLARepository
+	prService *PRService
+	publisher domain.EventPublisher
+	txManager domain.TxManager
+	cfg       SLAConfig
+}
+
+func NewSLAScheduler(
+	repo domain.SLARepository,
+	prService *PRService,
+	publisher domain.EventPublisher,
+	txManager domain.TxManager,
+	cfg SLAConfig,
+) *SLAScheduler {
+	return &SLAScheduler{
+		repo:      repo,
+		prService: prService,
+		publisher: publisher,
+		txManager: txManager,
+		cfg:       cfg,
+	}
+}
+
+func (s *SLAScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Check(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("review sla: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check отмечает нарушения на момент now и возвращает, сколько новых найдено.
+// Ошибка по одному назначению не мешает остальным: оно будет проверено снова.
+func (s *SLAScheduler) Check(ctx context.Context, now time.Time) (int, error) {
+	overdue, err := s.repo.ListOverdue(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("list overdue reviews: %w", err)
+	}
+
+	found := 0
+	for _, o := range overdue {
+		deadline := s.cfg.Calendar.SLADeadline(o.AssignedAt, o.SLAHours, o.SLAWorkingHours)
+		if now.Before(deadline) {
+			continue
+		}
+
+		recorded, err := s.breach(ctx, o, deadline, now)
+		if err != nil {
+			log.Printf("review sla for %s on %s: %v", o.ReviewerID, o.PullRequestID, err)
+			continue
+		}
+		if recorded {
+			found++
+		}
+	}
+	return found, nil
+}
+
+func (s *SLAScheduler) breach(ctx context.Context, o domain.OverdueReview, deadline, now time.Time) (bool, error) {
+	ctx = domain.WithActor(ctx, slaActor)
+
+	recorded := false
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		breach := domain.SLABreach{
+			PullRequestID: o.PullRequestID,
+			TeamName:      o.TeamName,
+			ReviewerID:    o.ReviewerID,
+			AssignedAt:    o.AssignedAt,
+			Deadline:      deadline,
+			DetectedAt:    now,
+		}
+		ok, err := s.repo.RecordBreach(ctx, &breach)
+		if err != nil || !ok {
+			return err
+		}
+		recorded = true
+
+		if o.SLAAutoReassign {
+			_, newID, err := s.prService.reassignReviewer(ctx, o.PullRequestID, o.ReviewerID, domain.ReviewerEventReasonSLABreach)
+			switch {
+			case err == nil:
+				breach.NewReviewerID = newID
+				if err := s.repo.SetNewReviewer(ctx, breach.ID, newID); err != nil {
+					return err
+				}
+			case errors.Is(err, domain.ErrNoCandidate):
+				// Заменить некем — нарушение всё равно фиксируется и уходит тимлиду.
+			default:
+				return err
+			}
+		}
+
+		return publishEvents(ctx, s.publisher, newEvent(domain.EventReviewSLABreached, o.TeamName, ReviewSLABreachedData{
+			PullRequestID: o.PullRequestID,
+			ReviewerID:    o.ReviewerID,
+			AssignedAt:    o.AssignedAt,
+			Deadline:      deadline,
+			NewReviewerID: breach.NewReviewerID,
+			TeamLead:      o.TeamLead,
+		}))
+	})
+	if err != nil {
+		return false, err
+	}
+	return recorded, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeSLARepo struct {
+	overdue  []domain.OverdueReview
+	breaches []domain.SLABreach
+}
+
+func (r *fakeSLARepo) ListOverdue(ctx context.Context, now time.Time) ([]domain.OverdueReview, error) {
+	return r.overdue, nil
+}
+
+func (r *fakeSLARepo) RecordBreach(ctx context.Context, breach *domain.SLABreach) (bool, error) {
+	for _, b := range r.breaches {
+		if b.PullRequestID == breach.PullRequestID && b.ReviewerID == breach.ReviewerID && b.AssignedAt.Equal(breach.AssignedAt) {
+			return false, nil
+		}
+	}
+	breach.ID = int64(len(r.breaches) + 1)
+	r.breaches = append(r.breaches, *breach)
+	return true, nil
+}
+
+func (r *fakeSLARepo) SetNewReviewer(ctx context.Context, breachID int64, newReviewerID string) error {
+	r.breaches[breachID-1].NewReviewerID = newReviewerID
+	return nil
+}
+
+func (r *fakeSLARepo) ListBreaches(ctx context.Context, filter domain.SLABreachFilter) ([]domain.SLABreach, error) {
+	return r.breaches, nil
+}
+
+type recordingPublisher struct {
+	events []domain.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event domain.Event) error {
+	p.events = append(p.events, event)
+	return nil
+}
+
+var testWorkCalendar = domain.WorkCalendar{Start: 9 * time.Hour, End: 18 * time.Hour, Location: time.UTC}
+
+func TestWorkCalendar_AddWorkingHours(t *testing.T) {
+	cases := []struct {
+		name  string
+		start time.Time
+		hours time.Duration
+		want  time.Time
+	}{
+		{"same day", time.Date(2025, 3, 4, 10, 0, 0, 0, time.UTC), 3 * time.Hour, time.Date(2025, 3, 4, 13, 0, 0, 0, time.UTC)},
+		{"friday evening rolls to monday", time.Date(2025, 3, 7, 17, 0, 0, 0, time.UTC), 3 * time.Hour, time.Date(2025, 3, 10, 11, 0, 0, 0, time.UTC)},
+		{"weekend starts on monday", time.Date(2025, 3, 8, 10, 0, 0, 0, time.UTC), time.Hour, time.Date(2025, 3, 10, 10, 0, 0, 0, time.UTC)},
+		{"before work day", time.Date(2025, 3, 4, 6, 0, 0, 0, time.UTC), 24 * time.Hour, time.Date(2025, 3, 6, 15, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := testWorkCalendar.AddWorkingHours(tc.start, tc.hours); !got.Equal(tc.want) {
+				t.Fatalf("expected %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func newSLAFixture() (*SLAScheduler, *fakeSLARepo, *prRepoFake, *recordingPublisher) {
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-1": {ID: "pr-1", Name: "Add search", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{"pr-1": {"slow"}},
+	}
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"author": {ID: "author", TeamName: "core", IsActive: true},
+			"slow":   {ID: "slow", TeamName: "core", IsActive: true},
+			"fast":   {ID: "fast", TeamName: "core", IsActive: true},
+		},
+		activeByTeam: map[string][]domain.User{
+			"core": {
+				{ID: "author", TeamName: "core", IsActive: true},
+				{ID: "slow", TeamName: "core", IsActive: true},
+				{ID: "fast", TeamName: "core", IsActive: true},
+			},
+		},
+	}
+	events := &fakeEventRepo{}
+	publisher := &recordingPublisher{}
+	prSvc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, events, nil, nil, &fakeTxManager{})
+
+	repo := &fakeSLARepo{}
+	scheduler := NewSLAScheduler(repo, prSvc, publisher, &fakeTxManager{}, SLAConfig{Calendar: testWorkCalendar})
+	return scheduler, repo, prRepo, publisher
+}
+
+func TestSLAScheduler_FlagsBreachAndReassigns(t *testing.T) {
+	ctx := context.Background()
+	scheduler, repo, prRepo, publisher := newSLAFixture()
+
+	// Пятница 12:00 + 24 календарных часа — дедлайн прошёл; 24 рабочих часа — ещё нет.
+	assignedAt := time.Date(2025, 3, 7, 12, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 3, 8, 13, 0, 0, 0, time.UTC)
+	repo.overdue = []domain.OverdueReview{
+		{PullRequestID: "pr-1", ReviewerID: "slow", AssignedAt: assignedAt, TeamName: "core", SLAHours: 24, SLAAutoReassign: true, TeamLead: "lead"},
+		{PullRequestID: "pr-2", ReviewerID: "slow", AssignedAt: assignedAt, TeamName: "docs", SLAHours: 24, SLAWorkingHours: true},
+	}
+
+	found, err := scheduler.Check(ctx, now)
+	if err != nil || found != 1 {
+		t.Fatalf("expected one breach, got %d err=%v", found, err)
+	}
+
+	b := repo.breaches[0]
+	if b.PullRequestID != "pr-1" || !b.Deadline.Equal(assignedAt.Add(24*time.Hour)) || b.NewReviewerID != "fast" {
+		t.Fatalf("unexpected breach: %+v", b)
+	}
+	if got := prRepo.reviewers["pr-1"]; len(got) != 1 || got[0] != "fast" {
+		t.Fatalf("expected review passed to fast, got %v", got)
+	}
+
+	if len(publisher.events) != 1 || publisher.events[0].Type != domain.EventReviewSLABreached {
+		t.Fatalf("expected review.sla_breached event, got %+v", publisher.events)
+	}
+	data := publisher.events[0].Data.(ReviewSLABreachedData)
+	if data.TeamLead != "lead" || data.NewReviewerID != "fast" || data.ReviewerID != "slow" {
+		t.Fatalf("unexpected event data: %+v", data)
+	}
+
+	// Повторная проверка того же назначения ничего не добавляет.
+	if found, _ := scheduler.Check(ctx, now.Add(time.Minute)); found != 0 {
+		t.Fatalf("breach must be recorded once, found %d", found)
+	}
+}
+
+func TestSLAScheduler_FlagsWithoutCandidate(t *testing.T) {
+	ctx := context.Background()
+	scheduler, repo, prRepo, publisher := newSLAFixture()
+	scheduler.prService.userRepo.(*userRepoFake).activeByTeam["core"] = []domain.User{
+		{ID: "author", TeamName: "core", IsActive: true},
+		{ID: "slow", TeamName: "core", IsActive: true},
+	}
+
+	assignedAt := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	repo.overdue = []domain.OverdueReview{
+		{PullRequestID: "pr-1", ReviewerID: "slow", AssignedAt: assignedAt, TeamName: "core", SLAHours: 4, SLAAutoReassign: true},
+	}
+
+	if found, err := scheduler.Check(ctx, assignedAt.Add(5*time.Hour)); err != nil || found != 1 {
+		t.Fatalf("expected one breach, got %d err=%v", found, err)
+	}
+	if repo.breaches[0].NewReviewerID != "" {
+		t.Fatalf("nobody to pass the review to, got %+v", repo.breaches[0])
+	}
+	if got := prRepo.reviewers["pr-1"]; len(got) != 1 || got[0] != "slow" {
+		t.Fatalf("reviewer must stay when there is no replacement, got %v", got)
+	}
+	if len(publisher.events) != 1 {
+		t.Fatalf("breach must still be reported, got %+v", publisher.events)
+	}
+}
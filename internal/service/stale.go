@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	// staleActor — автор закрытия устаревших PR в истории.
+	staleActor = "system:stale"
+	// staleLockName — имя блокировки: проход делает только тот экземпляр, который её взял.
+	staleLockName = "stale-prs"
+)
+
+// StaleWorker применяет политику команд к PR без активности: через stale_after_days
+// помечает PR устаревшим, ещё через stale_close_after_days закрывает его.
+// Любая активность по PR (ревьюверы, решения, смена статуса) снимает пометку.
+type StaleWorker struct {
+	repo      domain.StaleRepository
+	prRepo    domain.PullRequestRepository
+	prService *PRService
+	locker    domain.Locker
+	txManager domain.TxManager
+	interval  time.Duration
+}
+
+func NewStaleWorker(
+	repo domain.StaleRepository,
+	prRepo domain.PullRequestRepository,
+	prService *PRService,
+	locker domain.Locker,
+	txManager domain.TxManager,
+	interval time.Duration,
+) *StaleWorker {
+	return &StaleWorker{
+		repo:      repo,
+		prRepo:    prRepo,
+		prService: prService,
+		locker:    locker,
+		txManager: txManager,
+		interval:  interval,
+	}
+}
+
+func (w *StaleWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, _, err := w.Check(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("stale prs: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check выполняет один проход на момент now и возвращает, сколько PR помечено и закрыто.
+// Если блокировку держит другой экземпляр, проход пропускается.
+func (w *StaleWorker) Check(ctx context.Context, now time.Time) (marked, closed int, err error) {
+	unlock, ok, err := w.locker.TryLock(ctx, staleLockName)
+	if err != nil {
+		return 0, 0, fmt.Errorf("take lock: %w", err)
+	}
+	if !ok {
+		return 0, 0, nil
+	}
+	defer unlock()
+
+	stale, err := w.repo.MarkStale(ctx, now)
+	if err != nil {
+		return 0, 0, fmt.Errorf("mark stale prs: %w", err)
+	}
+
+	toClose, err := w.repo.ListToClose(ctx, now)
+	if err != nil {
+		return len(stale), 0, fmt.Errorf("list stale prs to close: %w", err)
+	}
+
+	for _, pr := range toClose {
+		ok, err := w.close(ctx, pr)
+		if err != nil {
+			log.Printf("stale prs: close %s: %v", pr.PullRequestID, err)
+			continue
+		}
+		if ok {
+			closed++
+		}
+	}
+	return len(stale), closed, nil
+}
+
+// close закрывает PR, если с момента выборки по нему не было активности.
+func (w *StaleWorker) close(ctx context.Context, stale domain.StalePR) (bool, error) {
+	ctx = domain.WithActor(ctx, staleActor)
+
+	closed := false
+	err := w.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		pr, err := w.prRepo.GetByIDForUpdate(ctx, stale.PullRequestID)
+		if err != nil {
+			return err
+		}
+		if pr.StaleSince == nil || !pr.StaleSince.Equal(stale.StaleSince) {
+			return nil
+		}
+
+		if _, err := w.prService.ClosePR(ctx, pr.ID); err != nil {
+			return err
+		}
+		closed = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return closed, nil
+}
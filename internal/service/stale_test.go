@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeStaleRepo struct {
+	marked  []domain.StalePR
+	toClose []domain.StalePR
+}
+
+func (r *fakeStaleRepo) MarkStale(ctx context.Context, now time.Time) ([]domain.StalePR, error) {
+	return r.marked, nil
+}
+
+func (r *fakeStaleRepo) ListToClose(ctx context.Context, now time.Time) ([]domain.StalePR, error) {
+	return r.toClose, nil
+}
+
+type fakeLocker struct {
+	busy     bool
+	held     bool
+	unlocked int
+}
+
+func (l *fakeLocker) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	if l.busy || l.held {
+		return nil, false, nil
+	}
+	l.held = true
+	return func() {
+		l.held = false
+		l.unlocked++
+	}, true, nil
+}
+
+func newStaleFixture(staleSince time.Time) (*StaleWorker, *fakeStaleRepo, *prRepoFake, *fakeLocker) {
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-old":    {ID: "pr-old", AuthorID: "author", Status: domain.PullRequestStatusOpen, StaleSince: &staleSince},
+			"pr-active": {ID: "pr-active", AuthorID: "author", Status: domain.PullRequestStatusDraft},
+		},
+	}
+	prSvc := NewPRService(prRepo, &userRepoFake{}, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+
+	repo := &fakeStaleRepo{}
+	locker := &fakeLocker{}
+	worker := NewStaleWorker(repo, prRepo, prSvc, locker, &fakeTxManager{}, time.Hour)
+	return worker, repo, prRepo, locker
+}
+
+func TestStaleWorker_ClosesStalePRs(t *testing.T) {
+	ctx := context.Background()
+	staleSince := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	worker, repo, prRepo, locker := newStaleFixture(staleSince)
+
+	repo.marked = []domain.StalePR{{PullRequestID: "pr-new", TeamName: "core", StaleSince: staleSince.Add(time.Hour)}}
+	repo.toClose = []domain.StalePR{
+		{PullRequestID: "pr-old", TeamName: "core", StaleSince: staleSince},
+		// Пометку сняла активность после выборки — PR не закрывается.
+		{PullRequestID: "pr-active", TeamName: "core", StaleSince: staleSince},
+	}
+
+	marked, closed, err := worker.Check(ctx, staleSince.Add(7*24*time.Hour))
+	if err != nil || marked != 1 || closed != 1 {
+		t.Fatalf("expected 1 marked and 1 closed, got %d/%d err=%v", marked, closed, err)
+	}
+
+	if got := prRepo.prs["pr-old"]; got.Status != domain.PullRequestStatusClosed || got.StaleSince != nil {
+		t.Fatalf("stale pr must be closed, got %+v", got)
+	}
+	if got := prRepo.prs["pr-active"].Status; got != domain.PullRequestStatusDraft {
+		t.Fatalf("active pr must stay %s, got %s", domain.PullRequestStatusDraft, got)
+	}
+	if locker.held || locker.unlocked != 1 {
+		t.Fatalf("lock must be released after the pass, held=%v unlocked=%d", locker.held, locker.unlocked)
+	}
+}
+
+func TestStaleWorker_SkipsWithoutLock(t *testing.T) {
+	ctx := context.Background()
+	staleSince := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	worker, repo, prRepo, locker := newStaleFixture(staleSince)
+	locker.busy = true
+
+	repo.toClose = []domain.StalePR{{PullRequestID: "pr-old", TeamName: "core", StaleSince: staleSince}}
+
+	marked, closed, err := worker.Check(ctx, staleSince.Add(7*24*time.Hour))
+	if err != nil || marked != 0 || closed != 0 {
+		t.Fatalf("another replica holds the lock, got %d/%d err=%v", marked, closed, err)
+	}
+	if got := prRepo.prs["pr-old"].Status; got != domain.PullRequestStatusOpen {
+		t.Fatalf("pr must not be touched without the lock, got %s", got)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	defaultStatsLimit = 100
+	maxStatsLimit     = 1000
+)
+
+// StatsService отдаёт отчёты о работе ревьюверов.
+type StatsService struct {
+	slaRepo   domain.SLARepository
+	statsRepo domain.StatsRepository
+	teamRepo  domain.TeamRepository
+}
+
+func NewStatsService(
+	slaRepo domain.SLARepository,
+	statsRepo domain.StatsRepository,
+	teamRepo domain.TeamRepository,
+) *StatsService {
+	return &StatsService{
+		slaRepo:   slaRepo,
+		statsRepo: statsRepo,
+		teamRepo:  teamRepo,
+	}
+}
+
+func (s *StatsService) SLABreaches(ctx context.Context, filter domain.SLABreachFilter) ([]domain.SLABreach, error) {
+	if err := validatePeriod(filter.From, filter.To); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case filter.Limit < 0 || filter.Limit > maxStatsLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxStatsLimit)
+	case filter.Limit == 0:
+		filter.Limit = defaultStatsLimit
+	}
+
+	return s.slaRepo.ListBreaches(ctx, filter)
+}
+
+func (s *StatsService) Reviewers(ctx context.Context, filter domain.ReviewerStatsFilter) ([]domain.ReviewerStats, error) {
+	if err := validatePeriod(filter.From, filter.To); err != nil {
+		return nil, err
+	}
+
+	return s.statsRepo.ReviewerStats(ctx, filter)
+}
+
+// TeamFairness считает, насколько равномерно назначения распределились по команде за период.
+// Без to период заканчивается сейчас, без from — длится DefaultFairnessWindow.
+func (s *StatsService) TeamFairness(ctx context.Context, teamName string, from, to time.Time) (domain.TeamFairness, error) {
+	if teamName == "" {
+		return domain.TeamFairness{}, fmt.Errorf("%w: team_name is required", domain.ErrInvalidInput)
+	}
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+	if from.IsZero() {
+		from = to.Add(-domain.DefaultFairnessWindow)
+	}
+	if err := validatePeriod(from, to); err != nil {
+		return domain.TeamFairness{}, err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return domain.TeamFairness{}, err
+	}
+	active := make(map[string]bool, len(team.Members))
+	for _, m := range team.Members {
+		active[m.ID] = m.IsActive
+	}
+
+	stats, err := s.statsRepo.ReviewerStats(ctx, domain.ReviewerStatsFilter{TeamName: teamName, From: from, To: to})
+	if err != nil {
+		return domain.TeamFairness{}, err
+	}
+
+	report := domain.TeamFairness{
+		TeamName: teamName,
+		From:     from,
+		To:       to,
+		Members:  make([]domain.MemberLoad, 0, len(stats)),
+	}
+	for _, st := range stats {
+		if !active[st.UserID] && st.Assignments == 0 {
+			continue
+		}
+		report.Members = append(report.Members, domain.MemberLoad{
+			UserID:      st.UserID,
+			Username:    st.Username,
+			IsActive:    active[st.UserID],
+			Assignments: st.Assignments,
+		})
+		report.TotalAssignments += st.Assignments
+	}
+
+	loads := make([]int, 0, len(report.Members))
+	for i := range report.Members {
+		m := &report.Members[i]
+		if report.TotalAssignments > 0 {
+			m.Share = float64(m.Assignments) / float64(report.TotalAssignments)
+		}
+		loads = append(loads, m.Assignments)
+	}
+	report.Gini = gini(loads)
+	report.MaxMinRatio = maxMinRatio(loads)
+
+	return report, nil
+}
+
+// gini — коэффициент Джини по отсортированным значениям: sum((2i-n-1)*x_i) / (n*sum(x)).
+func gini(values []int) float64 {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+
+	n := len(sorted)
+	var sum, weighted float64
+	for i, v := range sorted {
+		sum += float64(v)
+		weighted += float64(2*(i+1)-n-1) * float64(v)
+	}
+	if sum == 0 {
+		return 0
+	}
+	return weighted / (float64(n) * sum)
+}
+
+func maxMinRatio(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	lo, hi := math.MaxInt, 0
+	for _, v := range values {
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+	if lo == 0 {
+		return 0
+	}
+	return float64(hi) / float64(lo)
+}
+
+func validatePeriod(from, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return fmt.Errorf("%w: from must be before to", domain.ErrInvalidInput)
+	}
+	return nil
+}
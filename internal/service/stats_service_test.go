@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeStatsRepo struct {
+	stats  []domain.ReviewerStats
+	filter domain.ReviewerStatsFilter
+}
+
+func (r *fakeStatsRepo) ReviewerStats(ctx context.Context, filter domain.ReviewerStatsFilter) ([]domain.ReviewerStats, error) {
+	r.filter = filter
+	return r.stats, nil
+}
+
+func TestGini(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []int
+		want   float64
+	}{
+		{"empty", nil, 0},
+		{"no assignments", []int{0, 0, 0}, 0},
+		{"equal", []int{5, 5, 5, 5}, 0},
+		{"all to one", []int{0, 0, 0, 8}, 0.75},
+		{"skewed", []int{1, 2, 3, 4}, 0.25},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := gini(tc.values); math.Abs(got-tc.want) > 1e-9 {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestStatsService_TeamFairness(t *testing.T) {
+	ctx := context.Background()
+	teamRepo := &fakeTeamRepo{
+		getByNameFn: func(ctx context.Context, name string) (domain.Team, error) {
+			if name != "core" {
+				return domain.Team{}, domain.ErrNotFound
+			}
+			return domain.Team{Name: "core", Members: []domain.TeamMember{
+				{ID: "u1", IsActive: true},
+				{ID: "u2", IsActive: true},
+				{ID: "u3", IsActive: false},
+				{ID: "u4", IsActive: false},
+			}}, nil
+		},
+	}
+	statsRepo := &fakeStatsRepo{stats: []domain.ReviewerStats{
+		{UserID: "u1", Assignments: 6},
+		{UserID: "u2", Assignments: 2},
+		{UserID: "u3", Assignments: 2},
+		// Неактивный без назначений не влияет на отчёт.
+		{UserID: "u4"},
+	}}
+	svc := NewStatsService(&fakeSLARepo{}, statsRepo, teamRepo)
+
+	to := time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC)
+	report, err := svc.TeamFairness(ctx, "core", time.Time{}, to)
+	if err != nil {
+		t.Fatalf("TeamFairness error: %v", err)
+	}
+
+	if !statsRepo.filter.From.Equal(to.Add(-domain.DefaultFairnessWindow)) || !statsRepo.filter.To.Equal(to) {
+		t.Fatalf("expected default window, got %+v", statsRepo.filter)
+	}
+	if report.TotalAssignments != 10 || len(report.Members) != 3 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	if report.Members[0].Share != 0.6 || report.MaxMinRatio != 3 {
+		t.Fatalf("unexpected share or ratio: %+v", report)
+	}
+	if math.Abs(report.Gini-4.0/15) > 1e-9 {
+		t.Fatalf("expected gini 4/15, got %v", report.Gini)
+	}
+
+	if _, err := svc.TeamFairness(ctx, "", time.Time{}, time.Time{}); !errors.Is(err, domain.ErrInvalidInput) {
+		t.Fatalf("expected ErrInvalidInput without team, got %v", err)
+	}
+	if _, err := svc.TeamFairness(ctx, "ghost", time.Time{}, time.Time{}); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown team, got %v", err)
+	}
+}
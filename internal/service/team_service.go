@@ -2,39 +2,99 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"time"
 
 	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
 )
 
+// bulkDeactivateTimeout ограничивает массовую деактивацию: если перенос ревью
+// не уложился, транзакция откатывается целиком.
+const bulkDeactivateTimeout = 30 * time.Second
+
 type TeamService struct {
-	teamRepo domain.TeamRepository
-	userRepo domain.UserRepository
+	teamRepo  domain.TeamRepository
+	userRepo  domain.UserRepository
+	publisher domain.EventPublisher
+	txManager domain.TxManager
+	prService *PRService
 }
 
-func NewTeamService(teamRepo domain.TeamRepository, userRepo domain.UserRepository) *TeamService {
+func NewTeamService(
+	teamRepo domain.TeamRepository,
+	userRepo domain.UserRepository,
+	publisher domain.EventPublisher,
+	txManager domain.TxManager,
+	prService *PRService,
+) *TeamService {
 	return &TeamService{
-		teamRepo: teamRepo,
-		userRepo: userRepo,
+		teamRepo:  teamRepo,
+		userRepo:  userRepo,
+		publisher: publisher,
+		txManager: txManager,
+		prService: prService,
 	}
 }
 
 func (s *TeamService) CreateOrUpdateTeam(ctx context.Context, team domain.Team) error {
-	if err := s.teamRepo.Create(ctx, team.Name); err != nil {
+	if team.Settings == (domain.TeamSettings{}) {
+		team.Settings = domain.DefaultTeamSettings()
+	}
+	if err := s.validateSettings(ctx, team.Name, team.Settings); err != nil {
 		return err
 	}
-
-	for _, m := range team.Members {
-		user := domain.User{
-			ID:       m.ID,
-			Username: m.Username,
-			TeamName: team.Name,
-			IsActive: m.IsActive,
+	if lead := team.Settings.TeamLead; lead != "" {
+		isMember := false
+		for _, m := range team.Members {
+			if m.ID == lead {
+				isMember = true
+				break
+			}
 		}
-		if err := s.userRepo.Upsert(ctx, user); err != nil {
-			return err
+		if !isMember {
+			return fmt.Errorf("%w: team_lead %s is not a member of the team", domain.ErrInvalidInput, lead)
 		}
 	}
-	return nil
+	for i, m := range team.Members {
+		if m.ReviewWeight < 0 {
+			return fmt.Errorf("%w: review_weight of %s must be positive", domain.ErrInvalidInput, m.ID)
+		}
+		if m.MaxOpenReviews < 0 {
+			return fmt.Errorf("%w: max_open_reviews of %s must not be negative", domain.ErrInvalidInput, m.ID)
+		}
+		if m.ReviewWeight == 0 {
+			team.Members[i].ReviewWeight = domain.DefaultReviewWeight
+		}
+		if m.Email != "" {
+			if _, err := mail.ParseAddress(m.Email); err != nil {
+				return fmt.Errorf("%w: email of %s is not a valid address", domain.ErrInvalidInput, m.ID)
+			}
+		}
+	}
+
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.teamRepo.Create(ctx, team.Name, team.Settings); err != nil {
+			return err
+		}
+
+		for _, m := range team.Members {
+			user := domain.User{
+				ID:             m.ID,
+				Username:       m.Username,
+				Email:          m.Email,
+				TeamName:       team.Name,
+				IsActive:       m.IsActive,
+				ReviewWeight:   m.ReviewWeight,
+				MaxOpenReviews: m.MaxOpenReviews,
+			}
+			if err := s.userRepo.Upsert(ctx, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (s *TeamService) GetTeamInfo(ctx context.Context, name string) (domain.Team, error) {
@@ -44,3 +104,121 @@ func (s *TeamService) GetTeamInfo(ctx context.Context, name string) (domain.Team
 func (s *TeamService) ListTeams(ctx context.Context) ([]domain.Team, error) {
 	return s.teamRepo.List(ctx)
 }
+
+func (s *TeamService) GetSettings(ctx context.Context, name string) (domain.TeamSettings, error) {
+	return s.teamRepo.GetSettings(ctx, name)
+}
+
+func (s *TeamService) UpdateSettings(ctx context.Context, name string, update domain.TeamSettingsUpdate) (domain.TeamSettings, error) {
+	current, err := s.teamRepo.GetSettings(ctx, name)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	next := update.Apply(current)
+	if err := s.validateSettings(ctx, name, next); err != nil {
+		return domain.TeamSettings{}, err
+	}
+	if update.TeamLead != nil && *update.TeamLead != "" {
+		lead, err := s.userRepo.GetByID(ctx, *update.TeamLead)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return domain.TeamSettings{}, err
+		}
+		if err != nil || lead.TeamName != name {
+			return domain.TeamSettings{}, fmt.Errorf("%w: team_lead %s is not a member of the team", domain.ErrInvalidInput, *update.TeamLead)
+		}
+	}
+
+	return s.teamRepo.UpdateSettings(ctx, name, next)
+}
+
+func (s *TeamService) validateSettings(ctx context.Context, name string, settings domain.TeamSettings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	if settings.FallbackTeam == "" {
+		return nil
+	}
+	if settings.FallbackTeam == name {
+		return fmt.Errorf("%w: team cannot be its own fallback", domain.ErrInvalidInput)
+	}
+	if _, err := s.teamRepo.GetSettings(ctx, settings.FallbackTeam); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: fallback team %s does not exist", domain.ErrInvalidInput, settings.FallbackTeam)
+		}
+		return err
+	}
+	return nil
+}
+
+// DeactivateUsers деактивирует участников команды и раскидывает их OPEN ревью
+// по оставшимся активным (включая fallback-команду) в одной транзакции.
+// Повторный вызов с теми же пользователями безопасен: у них уже нет открытых ревью.
+func (s *TeamService) DeactivateUsers(
+	ctx context.Context,
+	teamName string,
+	userIDs []string,
+) ([]domain.User, []domain.ReviewReassignment, error) {
+	ids := make([]string, 0, len(userIDs))
+	seen := make(map[string]struct{}, len(userIDs))
+	for _, id := range userIDs {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("%w: user_ids must not be empty", domain.ErrInvalidInput)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, bulkDeactivateTimeout)
+	defer cancel()
+
+	var (
+		users  []domain.User
+		report []domain.ReviewReassignment
+	)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if _, err := s.teamRepo.GetSettings(ctx, teamName); err != nil {
+			return err
+		}
+
+		// Сначала деактивируем всех, чтобы они не стали заменой друг для друга.
+		var (
+			wasActive map[string]bool
+			err       error
+		)
+		users, wasActive, err = s.userRepo.DeactivateMany(ctx, teamName, ids)
+		if err != nil {
+			return err
+		}
+		if len(users) != len(ids) {
+			return fmt.Errorf("%w: some users are not members of team %s", domain.ErrNotFound, teamName)
+		}
+
+		// Повторный вызов не должен публиковать событие для тех, кто уже был выключен.
+		for _, u := range users {
+			if !wasActive[u.ID] {
+				continue
+			}
+			event := newEvent(domain.EventUserDeactivated, teamName, UserDeactivatedData{
+				UserID:   u.ID,
+				TeamName: teamName,
+			})
+			if err := publishEvents(ctx, s.publisher, event); err != nil {
+				return err
+			}
+		}
+
+		report, err = s.prService.ReassignReviewsInBulk(ctx, teamName, ids, domain.ReviewerEventReasonUserDeactivated)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return users, report, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
@@ -10,9 +11,11 @@ import (
 
 type fakeTeamRepo struct {
 	createdNames []string
+	settings     map[string]domain.TeamSettings
 
 	createErr   error
 	getByNameFn func(ctx context.Context, name string) (domain.Team, error)
+	cursors     map[string]string
 }
 
 type fakeUserRepo struct {
@@ -33,17 +36,56 @@ func (r *fakeUserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([
 	return nil, nil
 }
 
-func (r *fakeUserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
-	return domain.User{}, domain.ErrNotFound
+func (r *fakeUserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, bool, error) {
+	return domain.User{}, false, domain.ErrNotFound
+}
+
+func (r *fakeUserRepo) DeactivateMany(ctx context.Context, teamName string, userIDs []string) ([]domain.User, map[string]bool, error) {
+	return nil, nil, nil
 }
 
 func (r *fakeTeamRepo) List(ctx context.Context) ([]domain.Team, error) {
 	return nil, nil
 }
 
-func (r *fakeTeamRepo) Create(ctx context.Context, name string) error {
+func (r *fakeTeamRepo) Create(ctx context.Context, name string, settings domain.TeamSettings) error {
 	r.createdNames = append(r.createdNames, name)
-	return r.createErr
+	if r.createErr != nil {
+		return r.createErr
+	}
+	if r.settings == nil {
+		r.settings = make(map[string]domain.TeamSettings)
+	}
+	r.settings[name] = settings
+	return nil
+}
+
+func (r *fakeTeamRepo) GetSettings(ctx context.Context, name string) (domain.TeamSettings, error) {
+	s, ok := r.settings[name]
+	if !ok {
+		return domain.TeamSettings{}, domain.ErrNotFound
+	}
+	return s, nil
+}
+
+func (r *fakeTeamRepo) UpdateSettings(ctx context.Context, name string, settings domain.TeamSettings) (domain.TeamSettings, error) {
+	if _, ok := r.settings[name]; !ok {
+		return domain.TeamSettings{}, domain.ErrNotFound
+	}
+	r.settings[name] = settings
+	return settings, nil
+}
+
+func (r *fakeTeamRepo) RoundRobinCursor(ctx context.Context, name string) (string, error) {
+	return r.cursors[name], nil
+}
+
+func (r *fakeTeamRepo) SetRoundRobinCursor(ctx context.Context, name, userID string) error {
+	if r.cursors == nil {
+		r.cursors = make(map[string]string)
+	}
+	r.cursors[name] = userID
+	return nil
 }
 
 func (r *fakeTeamRepo) GetByName(ctx context.Context, name string) (domain.Team, error) {
@@ -59,7 +101,7 @@ func TestTeamService_CreateOrUpdateTeam_Success(t *testing.T) {
 	teamRepo := &fakeTeamRepo{}
 	userRepo := &fakeUserRepo{}
 
-	svc := NewTeamService(teamRepo, userRepo)
+	svc := NewTeamService(teamRepo, userRepo, nil, &fakeTxManager{}, nil)
 
 	team := domain.Team{
 		Name: "backend",
@@ -110,7 +152,7 @@ func TestTeamService_CreateOrUpdateTeam_TeamExists(t *testing.T) {
 	}
 	userRepo := &fakeUserRepo{}
 
-	svc := NewTeamService(teamRepo, userRepo)
+	svc := NewTeamService(teamRepo, userRepo, nil, &fakeTxManager{}, nil)
 
 	team := domain.Team{
 		Name: "backend",
@@ -135,7 +177,7 @@ func TestTeamService_CreateOrUpdateTeam_UpsertError(t *testing.T) {
 		upsertErr: upsertErr,
 	}
 
-	svc := NewTeamService(teamRepo, userRepo)
+	svc := NewTeamService(teamRepo, userRepo, nil, &fakeTxManager{}, nil)
 
 	team := domain.Team{
 		Name: "backend",
@@ -153,3 +195,142 @@ func TestTeamService_CreateOrUpdateTeam_UpsertError(t *testing.T) {
 		t.Fatalf("expected 1 call to Create, got %d", len(teamRepo.createdNames))
 	}
 }
+
+// countingPRRepo считает обращения к репозиторию при массовом переносе ревью.
+type countingPRRepo struct {
+	*prRepoFake
+	lockedOne, counts, applied int
+}
+
+func (r *countingPRRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	r.lockedOne++
+	return r.prRepoFake.GetByIDForUpdate(ctx, id)
+}
+
+func (r *countingPRRepo) OpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	r.counts++
+	return r.prRepoFake.OpenReviewCounts(ctx, reviewerIDs)
+}
+
+func (r *countingPRRepo) ApplyReviewerChanges(ctx context.Context, changes []domain.ReviewerChange) error {
+	r.applied++
+	return r.prRepoFake.ApplyReviewerChanges(ctx, changes)
+}
+
+func TestTeamService_DeactivateUsers_ManyPRs(t *testing.T) {
+	ctx := context.Background()
+
+	const (
+		prCount  = 200
+		capacity = 90
+	)
+
+	users := map[string]domain.User{
+		"l1": {ID: "l1", TeamName: "backend", IsActive: true},
+		"l2": {ID: "l2", TeamName: "backend", IsActive: true},
+	}
+	var active []domain.User
+	for i := 1; i <= 5; i++ {
+		u := domain.User{ID: fmt.Sprintf("c%d", i), TeamName: "backend", IsActive: true, MaxOpenReviews: capacity}
+		users[u.ID] = u
+		active = append(active, u)
+	}
+	active = append(active, users["l1"], users["l2"])
+	userRepo := &userRepoFake{usersByID: users, activeByTeam: map[string][]domain.User{"backend": active}}
+
+	prs := &prRepoFake{
+		prs:         make(map[string]domain.PullRequest),
+		reviewers:   make(map[string][]string),
+		authorTeams: map[string]string{"author": "backend"},
+	}
+	for i := 0; i < prCount; i++ {
+		id := fmt.Sprintf("pr-%03d", i)
+		prs.prs[id] = domain.PullRequest{ID: id, AuthorID: "author", Status: domain.PullRequestStatusOpen}
+		prs.reviewers[id] = []string{"l1", "l2"}
+	}
+	prRepo := &countingPRRepo{prRepoFake: prs}
+
+	teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"backend": {
+		MaxReviewers:       2,
+		AssignmentStrategy: domain.AssignmentStrategyLeastLoaded,
+	}}}
+	events := &fakeEventRepo{}
+	prSvc := NewPRService(prRepo, userRepo, teamRepo, events, nil, nil, &fakeTxManager{})
+	svc := NewTeamService(teamRepo, userRepo, nil, &fakeTxManager{}, prSvc)
+
+	_, report, err := svc.DeactivateUsers(ctx, "backend", []string{"l1", "l2"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report) != 2*prCount {
+		t.Fatalf("expected %d reassignments, got %d", 2*prCount, len(report))
+	}
+	for _, item := range report {
+		if item.NewReviewerID == "" {
+			t.Fatalf("pr %s left without replacement for %s", item.PullRequestID, item.OldReviewerID)
+		}
+	}
+
+	load := make(map[string]int)
+	for id, list := range prs.reviewers {
+		if len(list) != 2 || list[0] == list[1] {
+			t.Fatalf("pr %s: expected two distinct reviewers, got %v", id, list)
+		}
+		for _, r := range list {
+			if r == "l1" || r == "l2" {
+				t.Fatalf("pr %s still has deactivated reviewer %s", id, r)
+			}
+			load[r]++
+		}
+	}
+	for id, n := range load {
+		if n > capacity {
+			t.Fatalf("reviewer %s got %d open reviews, limit is %d", id, n, capacity)
+		}
+	}
+
+	if prRepo.lockedOne != 0 || prRepo.counts != 1 || prRepo.applied != 1 {
+		t.Fatalf("expected one load query and one bulk write without per-PR locks, got locks=%d counts=%d writes=%d",
+			prRepo.lockedOne, prRepo.counts, prRepo.applied)
+	}
+	if len(events.events) != 2*prCount {
+		t.Fatalf("expected %d reviewer events, got %d", 2*prCount, len(events.events))
+	}
+}
+
+func TestTeamService_DeactivateUsers_RepeatPublishesOnce(t *testing.T) {
+	ctx := context.Background()
+
+	members := []domain.User{
+		{ID: "u1", TeamName: "backend", IsActive: true},
+		{ID: "u2", TeamName: "backend", IsActive: true},
+		{ID: "u3", TeamName: "backend", IsActive: true},
+	}
+	users := make(map[string]domain.User)
+	for _, u := range members {
+		users[u.ID] = u
+	}
+	userRepo := &userRepoFake{usersByID: users, activeByTeam: map[string][]domain.User{"backend": members}}
+	teamRepo := &fakeTeamRepo{settings: map[string]domain.TeamSettings{"backend": domain.DefaultTeamSettings()}}
+	publisher := &recordingPublisher{}
+	prSvc := NewPRService(&prRepoFake{}, userRepo, teamRepo, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+	svc := NewTeamService(teamRepo, userRepo, publisher, &fakeTxManager{}, prSvc)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.DeactivateUsers(ctx, "backend", []string{"u1", "u2"}); err != nil {
+			t.Fatalf("call %d: unexpected error: %v", i+1, err)
+		}
+	}
+
+	published := make(map[string]int)
+	for _, e := range publisher.events {
+		if e.Type != domain.EventUserDeactivated {
+			continue
+		}
+		published[e.Data.(UserDeactivatedData).UserID]++
+	}
+	if len(published) != 2 || published["u1"] != 1 || published["u2"] != 1 {
+		t.Fatalf("expected exactly one user.deactivated per user, got %v", published)
+	}
+}
@@ -7,14 +7,29 @@ import (
 )
 
 type UserService struct {
-	userRepo domain.UserRepository
-	prRepo   domain.PullRequestRepository
+	userRepo    domain.UserRepository
+	prRepo      domain.PullRequestRepository
+	absenceRepo domain.AbsenceRepository
+	publisher   domain.EventPublisher
+	txManager   domain.TxManager
+	prService   *PRService
 }
 
-func NewUserService(userRepo domain.UserRepository, prRepo domain.PullRequestRepository) *UserService {
+func NewUserService(
+	userRepo domain.UserRepository,
+	prRepo domain.PullRequestRepository,
+	absenceRepo domain.AbsenceRepository,
+	publisher domain.EventPublisher,
+	txManager domain.TxManager,
+	prService *PRService,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
-		prRepo:   prRepo,
+		userRepo:    userRepo,
+		prRepo:      prRepo,
+		absenceRepo: absenceRepo,
+		publisher:   publisher,
+		txManager:   txManager,
+		prService:   prService,
 	}
 }
 
@@ -22,8 +37,56 @@ func (s *UserService) GetUser(ctx context.Context, id string) (domain.User, erro
 	return s.userRepo.GetByID(ctx, id)
 }
 
-func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
-	return s.userRepo.SetIsActive(ctx, userID, isActive)
+// SetIsActive меняет флаг активности. При деактивации с reassignReviews=true
+// все OPEN ревью пользователя переносятся на других в той же транзакции.
+// user.deactivated публикуется только при реальном переходе из активного состояния.
+func (s *UserService) SetIsActive(
+	ctx context.Context,
+	userID string,
+	isActive bool,
+	reassignReviews bool,
+) (domain.User, []domain.ReviewReassignment, error) {
+	var (
+		user   domain.User
+		report []domain.ReviewReassignment
+	)
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		var (
+			wasActive bool
+			err       error
+		)
+		user, wasActive, err = s.userRepo.SetIsActive(ctx, userID, isActive)
+		if err != nil {
+			return err
+		}
+
+		if isActive {
+			return nil
+		}
+
+		if wasActive {
+			event := newEvent(domain.EventUserDeactivated, user.TeamName, UserDeactivatedData{
+				UserID:   user.ID,
+				TeamName: user.TeamName,
+			})
+			if err := publishEvents(ctx, s.publisher, event); err != nil {
+				return err
+			}
+		}
+
+		if !reassignReviews {
+			return nil
+		}
+
+		report, err = s.prService.ReassignOpenReviews(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return domain.User{}, nil, err
+	}
+
+	return user, report, nil
 }
 
 func (s *UserService) ListReviewerPRs(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
@@ -33,3 +96,39 @@ func (s *UserService) ListReviewerPRs(ctx context.Context, reviewerID string) ([
 
 	return s.prRepo.ListByReviewer(ctx, reviewerID)
 }
+
+func (s *UserService) AddAbsence(ctx context.Context, a domain.Absence) (domain.Absence, error) {
+	if err := a.Validate(); err != nil {
+		return domain.Absence{}, err
+	}
+
+	if _, err := s.userRepo.GetByID(ctx, a.UserID); err != nil {
+		return domain.Absence{}, err
+	}
+
+	if err := s.absenceRepo.Create(ctx, &a); err != nil {
+		return domain.Absence{}, err
+	}
+
+	return a, nil
+}
+
+func (s *UserService) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.absenceRepo.ListByUser(ctx, userID)
+}
+
+func (s *UserService) UpdateAbsence(ctx context.Context, a domain.Absence) (domain.Absence, error) {
+	if err := a.Validate(); err != nil {
+		return domain.Absence{}, err
+	}
+
+	return s.absenceRepo.Update(ctx, a)
+}
+
+func (s *UserService) DeleteAbsence(ctx context.Context, id int64) error {
+	return s.absenceRepo.Delete(ctx, id)
+}
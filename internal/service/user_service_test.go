@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeTxManager struct {
+	calls int
+}
+
+func (m *fakeTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls++
+	return fn(ctx)
+}
+
+func TestUserService_SetIsActive_ReassignsOpenReviews(t *testing.T) {
+	ctx := context.Background()
+
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-open":   {ID: "pr-open", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+			"pr-stuck":  {ID: "pr-stuck", AuthorID: "u3", Status: domain.PullRequestStatusOpen},
+			"pr-merged": {ID: "pr-merged", AuthorID: "author", Status: domain.PullRequestStatusMerged},
+		},
+		reviewers: map[string][]string{
+			"pr-open":   {"leaver"},
+			"pr-stuck":  {"leaver"},
+			"pr-merged": {"leaver"},
+		},
+	}
+
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"author": {ID: "author", TeamName: "frontend", IsActive: true},
+			"leaver": {ID: "leaver", TeamName: "backend", IsActive: true},
+			"u3":     {ID: "u3", TeamName: "backend", IsActive: true},
+		},
+		activeByTeam: map[string][]domain.User{
+			"backend": {
+				{ID: "u3", TeamName: "backend", IsActive: true},
+			},
+		},
+	}
+
+	tx := &fakeTxManager{}
+	prSvc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+	svc := NewUserService(userRepo, prRepo, nil, nil, tx, prSvc)
+
+	user, report, err := svc.SetIsActive(ctx, "leaver", false, true)
+	if err != nil {
+		t.Fatalf("SetIsActive error: %v", err)
+	}
+	if user.IsActive {
+		t.Fatalf("expected user to be deactivated")
+	}
+	if tx.calls != 1 {
+		t.Fatalf("expected a single transaction, got %d", tx.calls)
+	}
+
+	got := make(map[string]string, len(report))
+	for _, it := range report {
+		got[it.PullRequestID] = it.NewReviewerID
+	}
+
+	if len(report) != 2 {
+		t.Fatalf("expected 2 open PRs in report, got %v", report)
+	}
+	if got["pr-open"] != "u3" {
+		t.Fatalf("expected pr-open to move to u3, got %q", got["pr-open"])
+	}
+	if newID, ok := got["pr-stuck"]; !ok || newID != "" {
+		t.Fatalf("expected pr-stuck to be reported without replacement, got %q", newID)
+	}
+	if prRepo.reviewers["pr-merged"][0] != "leaver" {
+		t.Fatalf("merged PR must not be touched, got %v", prRepo.reviewers["pr-merged"])
+	}
+}
+
+func TestUserService_SetIsActive_WithoutReassign(t *testing.T) {
+	ctx := context.Background()
+
+	prRepo := &prRepoFake{
+		prs: map[string]domain.PullRequest{
+			"pr-open": {ID: "pr-open", AuthorID: "author", Status: domain.PullRequestStatusOpen},
+		},
+		reviewers: map[string][]string{
+			"pr-open": {"leaver"},
+		},
+	}
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"leaver": {ID: "leaver", TeamName: "backend", IsActive: true},
+		},
+	}
+
+	prSvc := NewPRService(prRepo, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+	svc := NewUserService(userRepo, prRepo, nil, nil, &fakeTxManager{}, prSvc)
+
+	_, report, err := svc.SetIsActive(ctx, "leaver", false, false)
+	if err != nil {
+		t.Fatalf("SetIsActive error: %v", err)
+	}
+	if len(report) != 0 {
+		t.Fatalf("expected no reassignments, got %v", report)
+	}
+	if prRepo.reviewers["pr-open"][0] != "leaver" {
+		t.Fatalf("expected reviewer to stay, got %v", prRepo.reviewers["pr-open"])
+	}
+
+	if _, _, err := svc.SetIsActive(ctx, "ghost", false, true); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown user, got %v", err)
+	}
+}
+
+func TestUserService_SetIsActive_PublishesDeactivationOnce(t *testing.T) {
+	ctx := context.Background()
+
+	userRepo := &userRepoFake{
+		usersByID: map[string]domain.User{
+			"leaver": {ID: "leaver", TeamName: "backend", IsActive: true},
+		},
+	}
+	publisher := &recordingPublisher{}
+	prSvc := NewPRService(&prRepoFake{}, userRepo, &fakeTeamRepo{}, &fakeEventRepo{}, nil, nil, &fakeTxManager{})
+	svc := NewUserService(userRepo, &prRepoFake{}, nil, publisher, &fakeTxManager{}, prSvc)
+
+	for i := 0; i < 2; i++ {
+		if _, _, err := svc.SetIsActive(ctx, "leaver", false, false); err != nil {
+			t.Fatalf("SetIsActive error: %v", err)
+		}
+	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != domain.EventUserDeactivated {
+		t.Fatalf("expected a single user.deactivated event, got %v", publisher.events)
+	}
+
+	if _, _, err := svc.SetIsActive(ctx, "leaver", true, false); err != nil {
+		t.Fatalf("SetIsActive error: %v", err)
+	}
+	if _, _, err := svc.SetIsActive(ctx, "leaver", false, false); err != nil {
+		t.Fatalf("SetIsActive error: %v", err)
+	}
+	if len(publisher.events) != 2 {
+		t.Fatalf("expected a new event after reactivation, got %d", len(publisher.events))
+	}
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+const (
+	WebhookSignatureHeader = "X-Webhook-Signature-256"
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+)
+
+type WebhookDispatcherConfig struct {
+	PollInterval   time.Duration
+	BatchSize      int
+	MaxAttempts    int
+	BackoffBase    time.Duration
+	BackoffMax     time.Duration
+	RequestTimeout time.Duration
+}
+
+// WebhookDispatcher разбирает очередь webhook_deliveries в фоне:
+// неудачные отправки повторяются с экспоненциальной задержкой, после MaxAttempts доставка помечается failed.
+type WebhookDispatcher struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	cfg    WebhookDispatcherConfig
+}
+
+func NewWebhookDispatcher(repo domain.WebhookRepository, client *http.Client, cfg WebhookDispatcherConfig) *WebhookDispatcher {
+	if client == nil {
+		client = &http.Client{Timeout: cfg.RequestTimeout}
+	}
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: client,
+		cfg:    cfg,
+	}
+}
+
+// SignWebhookPayload возвращает значение заголовка подписи: "sha256=" + hex(HMAC-SHA256(secret, body)).
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// retryBackoff — задержка перед попыткой номер attempt+1: base, 2*base, 4*base, ... но не больше max.
+func retryBackoff(attempt int, base, max time.Duration) time.Duration {
+	d := base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}
+
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("webhooks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue отправляет одну пачку готовых доставок и возвращает, сколько их было.
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	// Аренда с запасом больше таймаута запроса: пока пачка отправляется, её никто не заберёт.
+	lease := d.cfg.RequestTimeout + 30*time.Second
+	deliveries, err := d.repo.ClaimDue(ctx, d.cfg.BatchSize, lease)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery domain.WebhookDelivery) {
+			defer wg.Done()
+			if err := d.finish(ctx, delivery, d.send(ctx, delivery)); err != nil {
+				log.Printf("webhooks: update delivery %d: %v", delivery.ID, err)
+			}
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *WebhookDispatcher) send(ctx context.Context, delivery domain.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.RequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(delivery.Secret, delivery.Payload))
+	req.Header.Set(WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("receiver responded with %s", resp.Status)
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) finish(ctx context.Context, delivery domain.WebhookDelivery, sendErr error) error {
+	if sendErr == nil {
+		return d.repo.MarkDelivered(ctx, delivery.ID)
+	}
+
+	attempts := delivery.Attempts + 1
+	if attempts >= d.cfg.MaxAttempts {
+		return d.repo.MarkFailed(ctx, delivery.ID, attempts, sendErr.Error())
+	}
+
+	next := time.Now().UTC().Add(retryBackoff(attempts, d.cfg.BackoffBase, d.cfg.BackoffMax))
+	return d.repo.MarkRetry(ctx, delivery.ID, attempts, next, sendErr.Error())
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type WebhookService struct {
+	repo     domain.WebhookRepository
+	teamRepo domain.TeamRepository
+}
+
+func NewWebhookService(repo domain.WebhookRepository, teamRepo domain.TeamRepository) *WebhookService {
+	return &WebhookService{
+		repo:     repo,
+		teamRepo: teamRepo,
+	}
+}
+
+func (s *WebhookService) AddSubscription(ctx context.Context, sub domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	if err := sub.Validate(); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	if _, err := s.teamRepo.GetSettings(ctx, sub.TeamName); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	if err := s.repo.CreateSubscription(ctx, &sub); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+	return sub, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context, teamName string) ([]domain.WebhookSubscription, error) {
+	if _, err := s.teamRepo.GetSettings(ctx, teamName); err != nil {
+		return nil, err
+	}
+	return s.repo.ListSubscriptions(ctx, teamName)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id int64) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+// Publish ставит событие в очередь доставки для каждой подходящей подписки команды.
+// Сама отправка — в WebhookDispatcher, поэтому медленный получатель не тормозит запрос.
+func (s *WebhookService) Publish(ctx context.Context, event domain.Event) error {
+	if event.TeamName == "" {
+		return nil
+	}
+
+	subs, err := s.repo.ListSubscriptions(ctx, event.TeamName)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []domain.WebhookDelivery
+	var payload []byte
+	for _, sub := range subs {
+		if !sub.Accepts(event.Type) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = marshalEvent(event)
+			if err != nil {
+				return err
+			}
+		}
+
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return s.repo.Enqueue(ctx, deliveries...)
+}
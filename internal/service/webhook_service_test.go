@@ -0,0 +1,249 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type fakeWebhookRepo struct {
+	mu         sync.Mutex
+	subs       []domain.WebhookSubscription
+	deliveries map[int64]*domain.WebhookDelivery
+	nextID     int64
+}
+
+func (r *fakeWebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub.ID = int64(len(r.subs) + 1)
+	r.subs = append(r.subs, *sub)
+	return nil
+}
+
+func (r *fakeWebhookRepo) ListSubscriptions(ctx context.Context, teamName string) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookSubscription
+	for _, s := range r.subs {
+		if s.TeamName == teamName {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeWebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (r *fakeWebhookRepo) Enqueue(ctx context.Context, deliveries ...domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.deliveries == nil {
+		r.deliveries = make(map[int64]*domain.WebhookDelivery)
+	}
+	for _, d := range deliveries {
+		r.nextID++
+		d.ID = r.nextID
+		d.Status = domain.WebhookDeliveryPending
+		d.NextAttemptAt = time.Now().Add(-time.Second)
+		for _, s := range r.subs {
+			if s.ID == d.SubscriptionID {
+				d.URL, d.Secret = s.URL, s.Secret
+			}
+		}
+		r.deliveries[d.ID] = &d
+	}
+	return nil
+}
+
+func (r *fakeWebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var res []domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && !d.NextAttemptAt.After(time.Now()) && len(res) < limit {
+			d.NextAttemptAt = time.Now().Add(lease)
+			res = append(res, *d)
+		}
+	}
+	return res, nil
+}
+
+func (r *fakeWebhookRepo) MarkDelivered(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	d.Status = domain.WebhookDeliveryDelivered
+	d.Attempts++
+	return nil
+}
+
+func (r *fakeWebhookRepo) MarkRetry(ctx context.Context, id int64, attempts int, next time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	d.Attempts, d.NextAttemptAt, d.LastError = attempts, next, lastError
+	return nil
+}
+
+func (r *fakeWebhookRepo) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	d.Status, d.Attempts, d.LastError = domain.WebhookDeliveryFailed, attempts, lastError
+	return nil
+}
+
+func TestWebhookService_PublishFiltersSubscriptions(t *testing.T) {
+	ctx := context.Background()
+
+	repo := &fakeWebhookRepo{}
+	repo.subs = []domain.WebhookSubscription{
+		{ID: 1, TeamName: "core", URL: "http://a", Secret: "a", EventTypes: []domain.EventType{domain.EventPRMerged}},
+		{ID: 2, TeamName: "core", URL: "http://b", Secret: "b", EventTypes: []domain.EventType{domain.EventPRCreated, domain.EventPRMerged}},
+		{ID: 3, TeamName: "other", URL: "http://c", Secret: "c", EventTypes: []domain.EventType{domain.EventPRMerged}},
+	}
+	svc := NewWebhookService(repo, &fakeTeamRepo{})
+
+	event := newEvent(domain.EventPRMerged, "core", PREventData{PullRequestID: "pr-1"})
+	if err := svc.Publish(ctx, event); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if err := svc.Publish(ctx, newEvent(domain.EventUserDeactivated, "core", UserDeactivatedData{UserID: "u1"})); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("expected deliveries for subscriptions 1 and 2, got %d", len(repo.deliveries))
+	}
+	for _, d := range repo.deliveries {
+		if d.SubscriptionID == 3 || d.EventID != event.ID {
+			t.Fatalf("unexpected delivery %+v", d)
+		}
+	}
+}
+
+func TestWebhookDispatcher_SignsAndRetries(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		calls    int
+		gotSig   string
+		gotBody  []byte
+		failNext = true
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if failNext {
+			failNext = false
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		gotSig = r.Header.Get(WebhookSignatureHeader)
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	repo := &fakeWebhookRepo{}
+	repo.subs = []domain.WebhookSubscription{
+		{ID: 1, TeamName: "core", URL: srv.URL, Secret: "s3cret", EventTypes: []domain.EventType{domain.EventPRCreated}},
+	}
+	svc := NewWebhookService(repo, &fakeTeamRepo{})
+	if err := svc.Publish(ctx, newEvent(domain.EventPRCreated, "core", PREventData{PullRequestID: "pr-1"})); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	dispatcher := NewWebhookDispatcher(repo, srv.Client(), WebhookDispatcherConfig{
+		BatchSize:      10,
+		MaxAttempts:    3,
+		BackoffBase:    time.Minute,
+		BackoffMax:     time.Hour,
+		RequestTimeout: time.Second,
+	})
+
+	if n, err := dispatcher.DeliverDue(ctx); err != nil || n != 1 {
+		t.Fatalf("first DeliverDue: n=%d err=%v", n, err)
+	}
+	d := repo.deliveries[1]
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 1 || d.LastError == "" {
+		t.Fatalf("expected pending delivery after failure, got %+v", d)
+	}
+	if wait := time.Until(d.NextAttemptAt); wait < 50*time.Second || wait > time.Minute {
+		t.Fatalf("expected retry in about a minute, got %s", wait)
+	}
+
+	// До истечения задержки повтор не отправляется.
+	if n, _ := dispatcher.DeliverDue(ctx); n != 0 {
+		t.Fatalf("expected no due deliveries during backoff, got %d", n)
+	}
+
+	d.NextAttemptAt = time.Now().Add(-time.Second)
+	if n, err := dispatcher.DeliverDue(ctx); err != nil || n != 1 {
+		t.Fatalf("second DeliverDue: n=%d err=%v", n, err)
+	}
+	if d.Status != domain.WebhookDeliveryDelivered {
+		t.Fatalf("expected delivered, got %+v", d)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls to receiver, got %d", calls)
+	}
+	if gotSig != SignWebhookPayload("s3cret", gotBody) {
+		t.Fatalf("signature %q does not match body", gotSig)
+	}
+}
+
+func TestWebhookDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	repo := &fakeWebhookRepo{}
+	repo.subs = []domain.WebhookSubscription{
+		{ID: 1, TeamName: "core", URL: srv.URL, Secret: "s", EventTypes: []domain.EventType{domain.EventPRMerged}},
+	}
+	_ = repo.Enqueue(ctx, domain.WebhookDelivery{SubscriptionID: 1, EventType: domain.EventPRMerged, Payload: []byte(`{}`)})
+
+	dispatcher := NewWebhookDispatcher(repo, srv.Client(), WebhookDispatcherConfig{
+		BatchSize:      10,
+		MaxAttempts:    2,
+		BackoffBase:    time.Second,
+		BackoffMax:     time.Second,
+		RequestTimeout: time.Second,
+	})
+
+	for i := 0; i < 2; i++ {
+		repo.deliveries[1].NextAttemptAt = time.Now().Add(-time.Second)
+		if _, err := dispatcher.DeliverDue(ctx); err != nil {
+			t.Fatalf("DeliverDue error: %v", err)
+		}
+	}
+
+	if d := repo.deliveries[1]; d.Status != domain.WebhookDeliveryFailed || d.Attempts != 2 {
+		t.Fatalf("expected failed after 2 attempts, got %+v", d)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	base, max := 5*time.Second, time.Minute
+	want := []time.Duration{5 * time.Second, 10 * time.Second, 20 * time.Second, 40 * time.Second, time.Minute, time.Minute}
+	for i, w := range want {
+		if got := retryBackoff(i+1, base, max); got != w {
+			t.Fatalf("attempt %d: expected %s, got %s", i+1, w, got)
+		}
+	}
+}
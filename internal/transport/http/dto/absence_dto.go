@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type AbsenceCreateRequest struct {
+	UserID   string    `json:"user_id"   binding:"required"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at"   binding:"required"`
+	Reason   string    `json:"reason"`
+}
+
+type AbsenceUpdateRequest struct {
+	AbsenceID int64     `json:"absence_id" binding:"required"`
+	StartsAt  time.Time `json:"starts_at"  binding:"required"`
+	EndsAt    time.Time `json:"ends_at"    binding:"required"`
+	Reason    string    `json:"reason"`
+}
+
+type AbsenceDeleteRequest struct {
+	AbsenceID int64 `json:"absence_id" binding:"required"`
+}
+
+type AbsenceDTO struct {
+	AbsenceID int64     `json:"absence_id"`
+	UserID    string    `json:"user_id"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Reason    string    `json:"reason"`
+}
+
+type AbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+type AbsenceListResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
+func (r AbsenceCreateRequest) ToDomain() domain.Absence {
+	return domain.Absence{
+		UserID:   r.UserID,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+		Reason:   r.Reason,
+	}
+}
+
+func (r AbsenceUpdateRequest) ToDomain() domain.Absence {
+	return domain.Absence{
+		ID:       r.AbsenceID,
+		StartsAt: r.StartsAt,
+		EndsAt:   r.EndsAt,
+		Reason:   r.Reason,
+	}
+}
+
+func AbsenceDTOFromDomain(a domain.Absence) AbsenceDTO {
+	return AbsenceDTO{
+		AbsenceID: a.ID,
+		UserID:    a.UserID,
+		StartsAt:  a.StartsAt,
+		EndsAt:    a.EndsAt,
+		Reason:    a.Reason,
+	}
+}
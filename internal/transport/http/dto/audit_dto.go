@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type AuditEntryDTO struct {
+	ID          int64     `json:"audit_id"`
+	ActorID     string    `json:"actor_id,omitempty"`
+	Method      string    `json:"method"`
+	Endpoint    string    `json:"endpoint"`
+	EntityType  string    `json:"entity_type,omitempty"`
+	EntityID    string    `json:"entity_id,omitempty"`
+	PayloadHash string    `json:"payload_hash"`
+	StatusCode  int       `json:"status_code"`
+	OutcomeCode string    `json:"outcome_code"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+type AuditListResponse struct {
+	Entries []AuditEntryDTO `json:"entries"`
+}
+
+func AuditEntryDTOFromDomain(e domain.AuditEntry) AuditEntryDTO {
+	return AuditEntryDTO{
+		ID:          e.ID,
+		ActorID:     e.ActorID,
+		Method:      e.Method,
+		Endpoint:    e.Endpoint,
+		EntityType:  e.EntityType,
+		EntityID:    e.EntityID,
+		PayloadHash: e.PayloadHash,
+		StatusCode:  e.StatusCode,
+		OutcomeCode: e.OutcomeCode,
+		CreatedAt:   e.CreatedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type AccountLinkRequest struct {
+	Provider string `json:"provider" binding:"required"`
+	Login    string `json:"login"    binding:"required"`
+	UserID   string `json:"user_id"  binding:"required"`
+}
+
+type AccountDTO struct {
+	Provider  string    `json:"provider"`
+	Login     string    `json:"login"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AccountResponse struct {
+	Account AccountDTO `json:"account"`
+}
+
+type AccountListResponse struct {
+	Provider string       `json:"provider"`
+	Accounts []AccountDTO `json:"accounts"`
+}
+
+type IntegrationEventResponse struct {
+	Status        string `json:"status"`
+	PullRequestID string `json:"pull_request_id,omitempty"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+func (r AccountLinkRequest) ToDomain() domain.ExternalAccount {
+	return domain.ExternalAccount{
+		Provider: domain.CodeHost(r.Provider),
+		Login:    r.Login,
+		UserID:   r.UserID,
+	}
+}
+
+func AccountDTOFromDomain(a domain.ExternalAccount) AccountDTO {
+	return AccountDTO{
+		Provider:  string(a.Provider),
+		Login:     a.Login,
+		UserID:    a.UserID,
+		CreatedAt: a.CreatedAt,
+	}
+}
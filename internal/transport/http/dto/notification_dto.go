@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type NotificationPreferencesRequest struct {
+	UserID string `json:"user_id" binding:"required"`
+	// Enabled по умолчанию true; Events без значения — все события.
+	Enabled         *bool    `json:"enabled"`
+	Events          []string `json:"events"`
+	SlackUserID     string   `json:"slack_user_id"`
+	SlackWebhookURL string   `json:"slack_webhook_url"`
+}
+
+// NotificationPreferencesDTO не показывает slack_webhook_url: адрес incoming webhook — секрет.
+type NotificationPreferencesDTO struct {
+	UserID                 string     `json:"user_id"`
+	Enabled                bool       `json:"enabled"`
+	Events                 []string   `json:"events"`
+	SlackUserID            string     `json:"slack_user_id,omitempty"`
+	SlackWebhookConfigured bool       `json:"slack_webhook_configured"`
+	UpdatedAt              *time.Time `json:"updatedAt,omitempty"`
+}
+
+type NotificationPreferencesResponse struct {
+	Preferences NotificationPreferencesDTO `json:"preferences"`
+}
+
+type NotificationTemplateRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	Event    string `json:"event"     binding:"required"`
+	// Template пустой — вернуть встроенный шаблон.
+	Template string `json:"template"`
+}
+
+type NotificationTemplateDTO struct {
+	Event     string     `json:"event"`
+	Template  string     `json:"template"`
+	Custom    bool       `json:"custom"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+type NotificationTemplateResponse struct {
+	TeamName string                  `json:"team_name"`
+	Template NotificationTemplateDTO `json:"template"`
+}
+
+type NotificationTemplateListResponse struct {
+	TeamName  string                    `json:"team_name"`
+	Templates []NotificationTemplateDTO `json:"templates"`
+}
+
+func (r NotificationPreferencesRequest) ToDomain() domain.NotificationPreferences {
+	prefs := domain.DefaultNotificationPreferences(r.UserID)
+	if r.Enabled != nil {
+		prefs.Enabled = *r.Enabled
+	}
+	if r.Events != nil {
+		prefs.Kinds = make([]domain.NotificationKind, 0, len(r.Events))
+		for _, e := range r.Events {
+			prefs.Kinds = append(prefs.Kinds, domain.NotificationKind(e))
+		}
+	}
+	prefs.SlackUserID = r.SlackUserID
+	prefs.SlackWebhookURL = r.SlackWebhookURL
+	return prefs
+}
+
+func NotificationPreferencesDTOFromDomain(p domain.NotificationPreferences) NotificationPreferencesDTO {
+	events := make([]string, 0, len(p.Kinds))
+	for _, k := range p.Kinds {
+		events = append(events, string(k))
+	}
+
+	dto := NotificationPreferencesDTO{
+		UserID:                 p.UserID,
+		Enabled:                p.Enabled,
+		Events:                 events,
+		SlackUserID:            p.SlackUserID,
+		SlackWebhookConfigured: p.SlackWebhookURL != "",
+	}
+	if !p.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &p.UpdatedAt
+	}
+	return dto
+}
+
+func (r NotificationTemplateRequest) ToDomain() domain.NotificationTemplate {
+	return domain.NotificationTemplate{
+		TeamName: r.TeamName,
+		Kind:     domain.NotificationKind(r.Event),
+		Template: r.Template,
+	}
+}
+
+func NotificationTemplateDTOFromDomain(t domain.NotificationTemplate, custom bool) NotificationTemplateDTO {
+	dto := NotificationTemplateDTO{
+		Event:    string(t.Kind),
+		Template: t.Template,
+		Custom:   custom,
+	}
+	if custom && !t.UpdatedAt.IsZero() {
+		dto.UpdatedAt = &t.UpdatedAt
+	}
+	return dto
+}
@@ -7,19 +7,39 @@ import (
 )
 
 type PRCreateRequest struct {
-	ID       string `json:"pull_request_id"   binding:"required"`
-	Name     string `json:"pull_request_name" binding:"required"`
-	AuthorID string `json:"author_id"         binding:"required"`
+	ID             string `json:"pull_request_id"   binding:"required"`
+	Name           string `json:"pull_request_name" binding:"required"`
+	AuthorID       string `json:"author_id"         binding:"required"`
+	ReviewersCount *int   `json:"reviewers_count"`
+	Draft          bool   `json:"draft"`
 }
 
 type PRDTO struct {
-	ID                string     `json:"pull_request_id"`
-	Name              string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"createdAt"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ID                string           `json:"pull_request_id"`
+	Name              string           `json:"pull_request_name"`
+	AuthorID          string           `json:"author_id"`
+	Status            string           `json:"status"`
+	AssignedReviewers []string         `json:"assigned_reviewers"`
+	CreatedAt         time.Time        `json:"createdAt"`
+	MergedAt          *time.Time       `json:"mergedAt,omitempty"`
+	ClosedAt          *time.Time       `json:"closedAt,omitempty"`
+	StaleSince        *time.Time       `json:"staleSince,omitempty"`
+	Reviews           []ReviewDTO      `json:"reviews,omitempty"`
+	ReviewerSync      *ReviewerSyncDTO `json:"reviewer_sync,omitempty"`
+}
+
+// ReviewerSyncDTO — состояние выгрузки ревьюверов в GitHub/GitLab; только для внешних PR.
+type ReviewerSyncDTO struct {
+	Status    string    `json:"status"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ReviewDTO struct {
+	ReviewerID string    `json:"reviewer_id"`
+	Decision   string    `json:"decision"`
+	DecidedAt  time.Time `json:"decidedAt"`
 }
 
 type PRCreateResponse struct {
@@ -36,6 +56,25 @@ func (r PRCreateRequest) ToDomain() *domain.PullRequest {
 }
 
 func PRDTOFromDomain(pr domain.PullRequest) PRDTO {
+	var reviews []ReviewDTO
+	for _, r := range pr.Reviews {
+		reviews = append(reviews, ReviewDTO{
+			ReviewerID: r.ReviewerID,
+			Decision:   string(r.Decision),
+			DecidedAt:  r.DecidedAt,
+		})
+	}
+
+	var reviewerSync *ReviewerSyncDTO
+	if pr.ReviewerSync != nil {
+		reviewerSync = &ReviewerSyncDTO{
+			Status:    string(pr.ReviewerSync.Status),
+			Attempts:  pr.ReviewerSync.Attempts,
+			LastError: pr.ReviewerSync.LastError,
+			UpdatedAt: pr.ReviewerSync.UpdatedAt,
+		}
+	}
+
 	return PRDTO{
 		ID:                pr.ID,
 		Name:              pr.Name,
@@ -44,6 +83,10 @@ func PRDTOFromDomain(pr domain.PullRequest) PRDTO {
 		AssignedReviewers: append([]string(nil), pr.AssignedReviewers...),
 		CreatedAt:         pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
+		ClosedAt:          pr.ClosedAt,
+		StaleSince:        pr.StaleSince,
+		Reviews:           reviews,
+		ReviewerSync:      reviewerSync,
 	}
 }
 
@@ -65,19 +108,42 @@ type PRMergeResponse struct {
 	PR PRDTO `json:"pr"`
 }
 
+// PRStatusRequest — тело /pullRequest/close и /pullRequest/reopen.
+type PRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+}
+
+type PRReadyRequest struct {
+	PullRequestID  string `json:"pull_request_id" binding:"required"`
+	ReviewersCount *int   `json:"reviewers_count"`
+}
+
+type PRReviewRequest struct {
+	PullRequestID string `json:"pull_request_id" binding:"required"`
+	ReviewerID    string `json:"reviewer_id"     binding:"required"`
+	Decision      string `json:"decision"        binding:"required"`
+}
+
+type PRStatusResponse struct {
+	PR PRDTO `json:"pr"`
+}
+
 type PRShortDTO struct {
 	ID       string `json:"pull_request_id"`
 	Name     string `json:"pull_request_name"`
 	AuthorID string `json:"author_id"`
 	Status   string `json:"status"`
+	// StaleSince — с какого момента PR считается устаревшим; такие PR можно не брать в работу.
+	StaleSince *time.Time `json:"staleSince,omitempty"`
 }
 
 func PRShortDTOFromDomain(pr domain.PullRequest) PRShortDTO {
 	return PRShortDTO{
-		ID:       pr.ID,
-		Name:     pr.Name,
-		AuthorID: pr.AuthorID,
-		Status:   string(pr.Status),
+		ID:         pr.ID,
+		Name:       pr.Name,
+		AuthorID:   pr.AuthorID,
+		Status:     string(pr.Status),
+		StaleSince: pr.StaleSince,
 	}
 }
 
@@ -85,3 +151,28 @@ type PRListByUserResponse struct {
 	UserID       string       `json:"user_id"`
 	PullRequests []PRShortDTO `json:"pull_requests"`
 }
+
+type ReviewerEventDTO struct {
+	Type       string    `json:"type"`
+	ReviewerID string    `json:"reviewer_id"`
+	ReplacedBy string    `json:"replaced_by,omitempty"`
+	ActorID    string    `json:"actor_id,omitempty"`
+	Reason     string    `json:"reason"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type PRHistoryResponse struct {
+	PullRequestID string             `json:"pull_request_id"`
+	Events        []ReviewerEventDTO `json:"events"`
+}
+
+func ReviewerEventDTOFromDomain(e domain.ReviewerEvent) ReviewerEventDTO {
+	return ReviewerEventDTO{
+		Type:       string(e.Type),
+		ReviewerID: e.ReviewerID,
+		ReplacedBy: e.ReplacedBy,
+		ActorID:    e.ActorID,
+		Reason:     e.Reason,
+		CreatedAt:  e.CreatedAt,
+	}
+}
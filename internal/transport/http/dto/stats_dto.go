@@ -0,0 +1,116 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type SLABreachDTO struct {
+	ID              int64     `json:"breach_id"`
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	TeamName        string    `json:"team_name"`
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignedAt      time.Time `json:"assignedAt"`
+	Deadline        time.Time `json:"deadline"`
+	DetectedAt      time.Time `json:"detectedAt"`
+	NewReviewerID   string    `json:"new_reviewer_id,omitempty"`
+	Open            bool      `json:"open"`
+}
+
+type SLABreachListResponse struct {
+	Breaches []SLABreachDTO `json:"breaches"`
+}
+
+func SLABreachDTOFromDomain(b domain.SLABreach) SLABreachDTO {
+	return SLABreachDTO{
+		ID:              b.ID,
+		PullRequestID:   b.PullRequestID,
+		PullRequestName: b.PullRequestName,
+		TeamName:        b.TeamName,
+		ReviewerID:      b.ReviewerID,
+		AssignedAt:      b.AssignedAt,
+		Deadline:        b.Deadline,
+		DetectedAt:      b.DetectedAt,
+		NewReviewerID:   b.NewReviewerID,
+		Open:            b.Open,
+	}
+}
+
+type ReviewerStatsDTO struct {
+	UserID         string `json:"user_id"`
+	Username       string `json:"username"`
+	TeamName       string `json:"team_name"`
+	Assignments    int    `json:"assignments"`
+	OpenReviews    int    `json:"open_reviews"`
+	MergedReviews  int    `json:"merged_reviews"`
+	ReassignedAway int    `json:"reassigned_away"`
+	// MedianTimeToMergeSeconds отсутствует, если смёрженных PR за период нет.
+	MedianTimeToMergeSeconds *int64 `json:"median_time_to_merge_seconds,omitempty"`
+}
+
+type ReviewerStatsResponse struct {
+	Reviewers []ReviewerStatsDTO `json:"reviewers"`
+}
+
+func ReviewerStatsDTOFromDomain(s domain.ReviewerStats) ReviewerStatsDTO {
+	res := ReviewerStatsDTO{
+		UserID:         s.UserID,
+		Username:       s.Username,
+		TeamName:       s.TeamName,
+		Assignments:    s.Assignments,
+		OpenReviews:    s.OpenReviews,
+		MergedReviews:  s.MergedReviews,
+		ReassignedAway: s.ReassignedAway,
+	}
+	if s.MergedReviews > 0 {
+		seconds := int64(s.MedianTimeToMerge.Round(time.Second) / time.Second)
+		res.MedianTimeToMergeSeconds = &seconds
+	}
+	return res
+}
+
+type MemberLoadDTO struct {
+	UserID      string  `json:"user_id"`
+	Username    string  `json:"username"`
+	IsActive    bool    `json:"is_active"`
+	Assignments int     `json:"assignments"`
+	Share       float64 `json:"share"`
+}
+
+type TeamFairnessResponse struct {
+	TeamName         string    `json:"team_name"`
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	TotalAssignments int       `json:"total_assignments"`
+	Gini             float64   `json:"gini"`
+	// MaxMinRatio отсутствует, если у кого-то из участников нет назначений.
+	MaxMinRatio *float64        `json:"max_min_ratio,omitempty"`
+	Members     []MemberLoadDTO `json:"members"`
+}
+
+func TeamFairnessResponseFromDomain(f domain.TeamFairness) TeamFairnessResponse {
+	resp := TeamFairnessResponse{
+		TeamName:         f.TeamName,
+		From:             f.From,
+		To:               f.To,
+		TotalAssignments: f.TotalAssignments,
+		Gini:             f.Gini,
+		Members:          make([]MemberLoadDTO, 0, len(f.Members)),
+	}
+	if f.MaxMinRatio > 0 {
+		ratio := f.MaxMinRatio
+		resp.MaxMinRatio = &ratio
+	}
+	for _, m := range f.Members {
+		resp.Members = append(resp.Members, MemberLoadDTO{
+			UserID:      m.UserID,
+			Username:    m.Username,
+			IsActive:    m.IsActive,
+			Assignments: m.Assignments,
+			Share:       m.Share,
+		})
+	}
+	return resp
+}
@@ -3,19 +3,53 @@ package dto
 import "github.com/Mutter0815/pr-reviewer-service/internal/domain"
 
 type TeamMemberDTO struct {
-	UserID   string `json:"user_id" binding:"required"`
-	Username string `json:"username" binding:"required"`
-	IsActive bool   `json:"is_active"`
+	UserID         string `json:"user_id" binding:"required"`
+	Username       string `json:"username" binding:"required"`
+	Email          string `json:"email,omitempty"`
+	IsActive       bool   `json:"is_active"`
+	ReviewWeight   int    `json:"review_weight,omitempty"`
+	MaxOpenReviews int    `json:"max_open_reviews,omitempty"`
+}
+
+type TeamSettingsDTO struct {
+	AssignmentStrategy  string `json:"assignment_strategy"`
+	MinReviewers        int    `json:"min_reviewers"`
+	MaxReviewers        int    `json:"max_reviewers"`
+	FallbackTeam        string `json:"fallback_team,omitempty"`
+	RequiredApprovals   int    `json:"required_approvals"`
+	SLAHours            int    `json:"sla_hours"`
+	SLAWorkingHours     bool   `json:"sla_working_hours"`
+	SLAAutoReassign     bool   `json:"sla_auto_reassign"`
+	TeamLead            string `json:"team_lead,omitempty"`
+	StaleAfterDays      int    `json:"stale_after_days"`
+	StaleCloseAfterDays int    `json:"stale_close_after_days"`
+}
+
+// TeamSettingsInput — настройки команды во входящих запросах, все поля опциональны.
+type TeamSettingsInput struct {
+	AssignmentStrategy  *string `json:"assignment_strategy"`
+	MinReviewers        *int    `json:"min_reviewers"`
+	MaxReviewers        *int    `json:"max_reviewers"`
+	FallbackTeam        *string `json:"fallback_team"`
+	RequiredApprovals   *int    `json:"required_approvals"`
+	SLAHours            *int    `json:"sla_hours"`
+	SLAWorkingHours     *bool   `json:"sla_working_hours"`
+	SLAAutoReassign     *bool   `json:"sla_auto_reassign"`
+	TeamLead            *string `json:"team_lead"`
+	StaleAfterDays      *int    `json:"stale_after_days"`
+	StaleCloseAfterDays *int    `json:"stale_close_after_days"`
 }
 
 type TeamRequest struct {
-	TeamName string          `json:"team_name" binding:"required"`
-	Members  []TeamMemberDTO `json:"members" binding:"required"`
+	TeamName string             `json:"team_name" binding:"required"`
+	Members  []TeamMemberDTO    `json:"members" binding:"required"`
+	Settings *TeamSettingsInput `json:"settings"`
 }
 
 type TeamDTO struct {
 	TeamName string          `json:"team_name"`
 	Members  []TeamMemberDTO `json:"members"`
+	Settings TeamSettingsDTO `json:"settings"`
 }
 
 type TeamResponse struct {
@@ -26,19 +60,73 @@ type TeamListResponse struct {
 	Teams []TeamDTO `json:"teams"`
 }
 
+type TeamSettingsRequest struct {
+	TeamName string `json:"team_name" binding:"required"`
+	TeamSettingsInput
+}
+
+type TeamSettingsResponse struct {
+	TeamName string          `json:"team_name"`
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+func (in *TeamSettingsInput) ToDomain() domain.TeamSettingsUpdate {
+	var upd domain.TeamSettingsUpdate
+	if in == nil {
+		return upd
+	}
+
+	if in.AssignmentStrategy != nil {
+		strategy := domain.AssignmentStrategy(*in.AssignmentStrategy)
+		upd.AssignmentStrategy = &strategy
+	}
+	upd.MinReviewers = in.MinReviewers
+	upd.MaxReviewers = in.MaxReviewers
+	upd.FallbackTeam = in.FallbackTeam
+	upd.RequiredApprovals = in.RequiredApprovals
+	upd.SLAHours = in.SLAHours
+	upd.SLAWorkingHours = in.SLAWorkingHours
+	upd.SLAAutoReassign = in.SLAAutoReassign
+	upd.TeamLead = in.TeamLead
+	upd.StaleAfterDays = in.StaleAfterDays
+	upd.StaleCloseAfterDays = in.StaleCloseAfterDays
+
+	return upd
+}
+
+func TeamSettingsDTOFromDomain(s domain.TeamSettings) TeamSettingsDTO {
+	return TeamSettingsDTO{
+		AssignmentStrategy:  string(s.AssignmentStrategy),
+		MinReviewers:        s.MinReviewers,
+		MaxReviewers:        s.MaxReviewers,
+		FallbackTeam:        s.FallbackTeam,
+		RequiredApprovals:   s.RequiredApprovals,
+		SLAHours:            s.SLAHours,
+		SLAWorkingHours:     s.SLAWorkingHours,
+		SLAAutoReassign:     s.SLAAutoReassign,
+		TeamLead:            s.TeamLead,
+		StaleAfterDays:      s.StaleAfterDays,
+		StaleCloseAfterDays: s.StaleCloseAfterDays,
+	}
+}
+
 func (r *TeamRequest) ToDomain() domain.Team {
 	members := make([]domain.TeamMember, 0, len(r.Members))
 	for _, m := range r.Members {
 		members = append(members, domain.TeamMember{
-			ID:       m.UserID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			ID:             m.UserID,
+			Username:       m.Username,
+			Email:          m.Email,
+			IsActive:       m.IsActive,
+			ReviewWeight:   m.ReviewWeight,
+			MaxOpenReviews: m.MaxOpenReviews,
 		})
 	}
 
 	return domain.Team{
-		Name:    r.TeamName,
-		Members: members,
+		Name:     r.TeamName,
+		Members:  members,
+		Settings: r.Settings.ToDomain().Apply(domain.DefaultTeamSettings()),
 	}
 }
 
@@ -46,14 +134,29 @@ func TeamDTOFromDomain(t domain.Team) TeamDTO {
 	members := make([]TeamMemberDTO, 0, len(t.Members))
 	for _, m := range t.Members {
 		members = append(members, TeamMemberDTO{
-			UserID:   m.ID,
-			Username: m.Username,
-			IsActive: m.IsActive,
+			UserID:         m.ID,
+			Username:       m.Username,
+			Email:          m.Email,
+			IsActive:       m.IsActive,
+			ReviewWeight:   m.ReviewWeight,
+			MaxOpenReviews: m.MaxOpenReviews,
 		})
 	}
 
 	return TeamDTO{
 		TeamName: t.Name,
 		Members:  members,
+		Settings: TeamSettingsDTOFromDomain(t.Settings),
 	}
 }
+
+type TeamDeactivateUsersRequest struct {
+	TeamName string   `json:"team_name" binding:"required"`
+	UserIDs  []string `json:"user_ids"  binding:"required"`
+}
+
+type TeamDeactivateUsersResponse struct {
+	TeamName      string                  `json:"team_name"`
+	Deactivated   []UserDTO               `json:"deactivated"`
+	Reassignments []ReviewReassignmentDTO `json:"reassignments"`
+}
@@ -5,6 +5,8 @@ import "github.com/Mutter0815/pr-reviewer-service/internal/domain"
 type SetUserIsActiveRequest struct {
 	UserID   string `json:"user_id" binding:"required"`
 	IsActive bool   `json:"is_active"`
+	// ReassignReviews — при деактивации перенести открытые ревью пользователя на других.
+	ReassignReviews bool `json:"reassign_reviews"`
 }
 
 type UserDTO struct {
@@ -18,6 +20,29 @@ type UserResponse struct {
 	User UserDTO `json:"user"`
 }
 
+type ReviewReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+type SetUserIsActiveResponse struct {
+	User          UserDTO                 `json:"user"`
+	Reassignments []ReviewReassignmentDTO `json:"reassignments,omitempty"`
+}
+
+func ReviewReassignmentDTOsFromDomain(items []domain.ReviewReassignment) []ReviewReassignmentDTO {
+	res := make([]ReviewReassignmentDTO, 0, len(items))
+	for _, it := range items {
+		res = append(res, ReviewReassignmentDTO{
+			PullRequestID: it.PullRequestID,
+			OldReviewerID: it.OldReviewerID,
+			NewReviewerID: it.NewReviewerID,
+		})
+	}
+	return res
+}
+
 func UserDTOFromDomain(u domain.User) UserDTO {
 	return UserDTO{
 		UserID:   u.ID,
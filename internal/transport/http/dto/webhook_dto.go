@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+)
+
+type WebhookCreateRequest struct {
+	TeamName   string   `json:"team_name"   binding:"required"`
+	URL        string   `json:"url"         binding:"required"`
+	Secret     string   `json:"secret"      binding:"required"`
+	EventTypes []string `json:"event_types" binding:"required"`
+}
+
+type WebhookDeleteRequest struct {
+	SubscriptionID int64 `json:"subscription_id" binding:"required"`
+}
+
+// WebhookDTO не содержит secret: он нужен только получателю для проверки подписи.
+type WebhookDTO struct {
+	SubscriptionID int64     `json:"subscription_id"`
+	TeamName       string    `json:"team_name"`
+	URL            string    `json:"url"`
+	EventTypes     []string  `json:"event_types"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type WebhookResponse struct {
+	Webhook WebhookDTO `json:"webhook"`
+}
+
+type WebhookListResponse struct {
+	TeamName string       `json:"team_name"`
+	Webhooks []WebhookDTO `json:"webhooks"`
+}
+
+func (r WebhookCreateRequest) ToDomain() domain.WebhookSubscription {
+	types := make([]domain.EventType, 0, len(r.EventTypes))
+	for _, t := range r.EventTypes {
+		types = append(types, domain.EventType(t))
+	}
+
+	return domain.WebhookSubscription{
+		TeamName:   r.TeamName,
+		URL:        r.URL,
+		Secret:     r.Secret,
+		EventTypes: types,
+	}
+}
+
+func WebhookDTOFromDomain(s domain.WebhookSubscription) WebhookDTO {
+	types := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		types = append(types, string(t))
+	}
+
+	return WebhookDTO{
+		SubscriptionID: s.ID,
+		TeamName:       s.TeamName,
+		URL:            s.URL,
+		EventTypes:     types,
+		CreatedAt:      s.CreatedAt,
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+func (h *AccountHandler) Link(c *gin.Context) {
+	var req dto.AccountLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	account, err := h.accountService.Link(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AccountResponse{Account: dto.AccountDTOFromDomain(account)})
+}
+
+func (h *AccountHandler) List(c *gin.Context) {
+	provider := c.Query("provider")
+	if provider == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "provider query param is required",
+			},
+		})
+		return
+	}
+
+	accounts, err := h.accountService.List(c.Request.Context(), domain.CodeHost(provider))
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.AccountListResponse{
+		Provider: provider,
+		Accounts: make([]dto.AccountDTO, 0, len(accounts)),
+	}
+	for _, a := range accounts {
+		resp.Accounts = append(resp.Accounts, dto.AccountDTOFromDomain(a))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
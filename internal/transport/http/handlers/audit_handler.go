@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService *service.AuditService
+}
+
+func NewAuditHandler(auditService *service.AuditService) *AuditHandler {
+	return &AuditHandler{
+		auditService: auditService,
+	}
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := domain.AuditFilter{
+		ActorID:    c.Query("actor_id"),
+		EntityType: c.Query("entity_type"),
+		EntityID:   c.Query("entity_id"),
+	}
+
+	var err error
+	if v := c.Query("from"); v != "" {
+		if filter.From, err = time.Parse(time.RFC3339, v); err != nil {
+			badAuditQuery(c, "from must be RFC3339")
+			return
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if filter.To, err = time.Parse(time.RFC3339, v); err != nil {
+			badAuditQuery(c, "to must be RFC3339")
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			badAuditQuery(c, "limit must be an integer")
+			return
+		}
+	}
+
+	entries, err := h.auditService.List(c.Request.Context(), filter)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.AuditListResponse{
+		Entries: make([]dto.AuditEntryDTO, 0, len(entries)),
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, dto.AuditEntryDTOFromDomain(e))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func badAuditQuery(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "BAD_REQUEST",
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+// maxIntegrationPayload — предел тела входящего вебхука (у GitHub это 25 МБ, у GitLab меньше).
+const maxIntegrationPayload = 25 << 20
+
+type GitHubHandler struct {
+	githubService *service.GitHubService
+}
+
+func NewGitHubHandler(githubService *service.GitHubService) *GitHubHandler {
+	return &GitHubHandler{
+		githubService: githubService,
+	}
+}
+
+func (h *GitHubHandler) Webhook(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIntegrationPayload+1))
+	if err != nil || len(body) > maxIntegrationPayload {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	if !h.githubService.VerifySignature(body, c.GetHeader(service.GitHubSignatureHeader)) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_SIGNATURE",
+				"message": "signature does not match payload",
+			},
+		})
+		return
+	}
+
+	res, err := h.githubService.HandleEvent(c.Request.Context(), c.GetHeader(service.GitHubEventHeader), body)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.IntegrationEventResponse{
+		Status:        res.Status,
+		PullRequestID: res.PullRequestID,
+		Reason:        res.Reason,
+	})
+}
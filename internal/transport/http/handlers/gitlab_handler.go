@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type GitLabHandler struct {
+	gitlabService *service.GitLabService
+}
+
+func NewGitLabHandler(gitlabService *service.GitLabService) *GitLabHandler {
+	return &GitLabHandler{
+		gitlabService: gitlabService,
+	}
+}
+
+func (h *GitLabHandler) Webhook(c *gin.Context) {
+	if !h.gitlabService.VerifyToken(c.GetHeader(service.GitLabTokenHeader)) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"error": gin.H{
+				"code":    "INVALID_TOKEN",
+				"message": "webhook token does not match",
+			},
+		})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIntegrationPayload+1))
+	if err != nil || len(body) > maxIntegrationPayload {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	res, err := h.gitlabService.HandleEvent(c.Request.Context(), c.GetHeader(service.GitLabEventHeader), body)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.IntegrationEventResponse{
+		Status:        res.Status,
+		PullRequestID: res.PullRequestID,
+		Reason:        res.Reason,
+	})
+}
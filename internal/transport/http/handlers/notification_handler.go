@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *service.NotificationService
+}
+
+func NewNotificationHandler(notificationService *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "user_id query param is required",
+			},
+		})
+		return
+	}
+
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NotificationPreferencesResponse{
+		Preferences: dto.NotificationPreferencesDTOFromDomain(prefs),
+	})
+}
+
+func (h *NotificationHandler) SetPreferences(c *gin.Context) {
+	var req dto.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	prefs, err := h.notificationService.SetPreferences(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NotificationPreferencesResponse{
+		Preferences: dto.NotificationPreferencesDTOFromDomain(prefs),
+	})
+}
+
+func (h *NotificationHandler) ListTemplates(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "team_name query param is required",
+			},
+		})
+		return
+	}
+
+	templates, err := h.notificationService.ListTemplates(c.Request.Context(), teamName)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.NotificationTemplateListResponse{
+		TeamName:  teamName,
+		Templates: make([]dto.NotificationTemplateDTO, 0, len(templates)),
+	}
+	for _, t := range templates {
+		resp.Templates = append(resp.Templates, dto.NotificationTemplateDTOFromDomain(t.NotificationTemplate, t.Custom))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *NotificationHandler) SetTemplate(c *gin.Context) {
+	var req dto.NotificationTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	tpl, err := h.notificationService.SetTemplate(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NotificationTemplateResponse{
+		TeamName: req.TeamName,
+		Template: dto.NotificationTemplateDTOFromDomain(tpl.NotificationTemplate, tpl.Custom),
+	})
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
 
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
 	"github.com/Mutter0815/pr-reviewer-service/internal/service"
 	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
 	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
@@ -34,7 +36,12 @@ func (h *PRHandler) Create(c *gin.Context) {
 
 	pr := req.ToDomain()
 
-	created, err := h.prService.CreatePR(c.Request.Context(), pr)
+	opts := service.CreatePROptions{
+		ReviewersCount: req.ReviewersCount,
+		Draft:          req.Draft,
+	}
+
+	created, err := h.prService.CreatePR(c.Request.Context(), pr, opts)
 	if err != nil {
 		httperror.Write(c, err)
 		return
@@ -103,3 +110,111 @@ func (h *PRHandler) Merge(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *PRHandler) Ready(c *gin.Context) {
+	var req dto.PRReadyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.MarkReady(c.Request.Context(), req.PullRequestID, req.ReviewersCount)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PRStatusResponse{PR: dto.PRDTOFromDomain(pr)})
+}
+
+func (h *PRHandler) Review(c *gin.Context) {
+	var req dto.PRReviewRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(
+		c.Request.Context(),
+		req.PullRequestID,
+		req.ReviewerID,
+		domain.ReviewDecisionType(req.Decision),
+	)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PRStatusResponse{PR: dto.PRDTOFromDomain(pr)})
+}
+
+func (h *PRHandler) History(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "pull_request_id query param is required",
+			},
+		})
+		return
+	}
+
+	events, err := h.prService.History(c.Request.Context(), prID)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.PRHistoryResponse{
+		PullRequestID: prID,
+		Events:        make([]dto.ReviewerEventDTO, 0, len(events)),
+	}
+	for _, e := range events {
+		resp.Events = append(resp.Events, dto.ReviewerEventDTOFromDomain(e))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *PRHandler) Close(c *gin.Context) {
+	h.changeStatus(c, h.prService.ClosePR)
+}
+
+func (h *PRHandler) Reopen(c *gin.Context) {
+	h.changeStatus(c, h.prService.ReopenPR)
+}
+
+func (h *PRHandler) changeStatus(c *gin.Context, apply func(ctx context.Context, prID string) (domain.PullRequest, error)) {
+	var req dto.PRStatusRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	pr, err := apply(c.Request.Context(), req.PullRequestID)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.PRStatusResponse{PR: dto.PRDTOFromDomain(pr)})
+}
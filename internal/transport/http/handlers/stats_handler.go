@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type StatsHandler struct {
+	statsService *service.StatsService
+}
+
+func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+	return &StatsHandler{
+		statsService: statsService,
+	}
+}
+
+func (h *StatsHandler) SLABreaches(c *gin.Context) {
+	filter := domain.SLABreachFilter{
+		TeamName:   c.Query("team_name"),
+		ReviewerID: c.Query("reviewer_id"),
+	}
+
+	var ok bool
+	if filter.From, filter.To, ok = parseStatsPeriod(c); !ok {
+		return
+	}
+
+	var err error
+	if v := c.Query("open"); v != "" {
+		if filter.OpenOnly, err = strconv.ParseBool(v); err != nil {
+			badStatsQuery(c, "open must be a boolean")
+			return
+		}
+	}
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			badStatsQuery(c, "limit must be an integer")
+			return
+		}
+	}
+
+	breaches, err := h.statsService.SLABreaches(c.Request.Context(), filter)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.SLABreachListResponse{
+		Breaches: make([]dto.SLABreachDTO, 0, len(breaches)),
+	}
+	for _, b := range breaches {
+		resp.Breaches = append(resp.Breaches, dto.SLABreachDTOFromDomain(b))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *StatsHandler) Reviewers(c *gin.Context) {
+	filter := domain.ReviewerStatsFilter{
+		TeamName: c.Query("team_name"),
+	}
+
+	var ok bool
+	if filter.From, filter.To, ok = parseStatsPeriod(c); !ok {
+		return
+	}
+
+	stats, err := h.statsService.Reviewers(c.Request.Context(), filter)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.ReviewerStatsResponse{
+		Reviewers: make([]dto.ReviewerStatsDTO, 0, len(stats)),
+	}
+	for _, s := range stats {
+		resp.Reviewers = append(resp.Reviewers, dto.ReviewerStatsDTOFromDomain(s))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// TeamFairness отдаёт отчёт в JSON или, с format=csv, построчно по участникам для таблиц.
+func (h *StatsHandler) TeamFairness(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		badStatsQuery(c, "format must be json or csv")
+		return
+	}
+
+	from, to, ok := parseStatsPeriod(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.statsService.TeamFairness(c.Request.Context(), c.Query("team_name"), from, to)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	if format == "csv" {
+		writeFairnessCSV(c, report)
+		return
+	}
+	c.JSON(http.StatusOK, dto.TeamFairnessResponseFromDomain(report))
+}
+
+// writeFairnessCSV пишет строку на участника; сводка по команде (период, всего назначений,
+// gini, max_min_ratio) повторяется в каждой строке, чтобы файл оставался одной таблицей.
+func writeFairnessCSV(c *gin.Context, report domain.TeamFairness) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	summary := []string{
+		report.From.UTC().Format(time.RFC3339),
+		report.To.UTC().Format(time.RFC3339),
+		strconv.Itoa(report.TotalAssignments),
+		strconv.FormatFloat(report.Gini, 'f', 4, 64),
+		fairnessRatioCSV(report),
+	}
+
+	_ = w.Write([]string{
+		"team_name", "user_id", "username", "is_active", "assignments", "share",
+		"from", "to", "total_assignments", "gini", "max_min_ratio",
+	})
+	for _, m := range report.Members {
+		row := []string{
+			report.TeamName,
+			m.UserID,
+			m.Username,
+			strconv.FormatBool(m.IsActive),
+			strconv.Itoa(m.Assignments),
+			strconv.FormatFloat(m.Share, 'f', 4, 64),
+		}
+		_ = w.Write(append(row, summary...))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": "fairness-" + report.TeamName + ".csv"})
+	c.Header("Content-Disposition", disposition)
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+}
+
+// fairnessRatioCSV — max_min_ratio для CSV: "inf", если кто-то не получил ни одного назначения,
+// и пусто, если назначений не было вовсе.
+func fairnessRatioCSV(report domain.TeamFairness) string {
+	switch {
+	case report.MaxMinRatio > 0:
+		return strconv.FormatFloat(report.MaxMinRatio, 'f', 4, 64)
+	case report.TotalAssignments > 0:
+		return "inf"
+	default:
+		return ""
+	}
+}
+
+// parseStatsPeriod читает необязательные from/to; при ошибке ответ уже записан.
+func parseStatsPeriod(c *gin.Context) (from, to time.Time, ok bool) {
+	var err error
+	if v := c.Query("from"); v != "" {
+		if from, err = time.Parse(time.RFC3339, v); err != nil {
+			badStatsQuery(c, "from must be RFC3339")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	if v := c.Query("to"); v != "" {
+		if to, err = time.Parse(time.RFC3339, v); err != nil {
+			badStatsQuery(c, "to must be RFC3339")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return from, to, true
+}
+
+func badStatsQuery(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": gin.H{
+			"code":    "BAD_REQUEST",
+			"message": message,
+		},
+	})
+}
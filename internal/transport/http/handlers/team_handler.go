@@ -82,3 +82,81 @@ func (h *TeamHandler) ListTeams(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *TeamHandler) GetSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "team_name query param is required",
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamService.GetSettings(c.Request.Context(), teamName)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamSettingsResponse{
+		TeamName: teamName,
+		Settings: dto.TeamSettingsDTOFromDomain(settings),
+	})
+}
+
+func (h *TeamHandler) UpdateSettings(c *gin.Context) {
+	var req dto.TeamSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamService.UpdateSettings(c.Request.Context(), req.TeamName, req.TeamSettingsInput.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.TeamSettingsResponse{
+		TeamName: req.TeamName,
+		Settings: dto.TeamSettingsDTOFromDomain(settings),
+	})
+}
+
+func (h *TeamHandler) DeactivateUsers(c *gin.Context) {
+	var req dto.TeamDeactivateUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	users, reassignments, err := h.teamService.DeactivateUsers(c.Request.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.TeamDeactivateUsersResponse{
+		TeamName:      req.TeamName,
+		Deactivated:   make([]dto.UserDTO, 0, len(users)),
+		Reassignments: dto.ReviewReassignmentDTOsFromDomain(reassignments),
+	}
+	for _, u := range users {
+		resp.Deactivated = append(resp.Deactivated, dto.UserDTOFromDomain(u))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
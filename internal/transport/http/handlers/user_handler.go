@@ -31,15 +31,23 @@ func (h *UserHandler) SetIsActive(c *gin.Context) {
 		return
 	}
 
-	user, err := h.userService.SetIsActive(c.Request.Context(), req.UserID, req.IsActive)
+	user, reassignments, err := h.userService.SetIsActive(
+		c.Request.Context(),
+		req.UserID,
+		req.IsActive,
+		req.ReassignReviews,
+	)
 	if err != nil {
 		httperror.Write(c, err)
 		return
 	}
 
-	resp := dto.UserResponse{
+	resp := dto.SetUserIsActiveResponse{
 		User: dto.UserDTOFromDomain(user),
 	}
+	if req.ReassignReviews {
+		resp.Reassignments = dto.ReviewReassignmentDTOsFromDomain(reassignments)
+	}
 
 	c.JSON(http.StatusOK, resp)
 }
@@ -73,3 +81,98 @@ func (h *UserHandler) GetReview(c *gin.Context) {
 
 	c.JSON(http.StatusOK, resp)
 }
+
+func (h *UserHandler) AddAbsence(c *gin.Context) {
+	var req dto.AbsenceCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	absence, err := h.userService.AddAbsence(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.AbsenceResponse{
+		Absence: dto.AbsenceDTOFromDomain(absence),
+	})
+}
+
+func (h *UserHandler) ListAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "user_id query param is required",
+			},
+		})
+		return
+	}
+
+	absences, err := h.userService.ListAbsences(c.Request.Context(), userID)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.AbsenceListResponse{
+		UserID:   userID,
+		Absences: make([]dto.AbsenceDTO, 0, len(absences)),
+	}
+	for _, a := range absences {
+		resp.Absences = append(resp.Absences, dto.AbsenceDTOFromDomain(a))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *UserHandler) UpdateAbsence(c *gin.Context) {
+	var req dto.AbsenceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	absence, err := h.userService.UpdateAbsence(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.AbsenceResponse{
+		Absence: dto.AbsenceDTOFromDomain(absence),
+	})
+}
+
+func (h *UserHandler) DeleteAbsence(c *gin.Context) {
+	var req dto.AbsenceDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	if err := h.userService.DeleteAbsence(c.Request.Context(), req.AbsenceID); err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/dto"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *service.WebhookService
+}
+
+func NewWebhookHandler(webhookService *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func (h *WebhookHandler) Add(c *gin.Context) {
+	var req dto.WebhookCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	sub, err := h.webhookService.AddSubscription(c.Request.Context(), req.ToDomain())
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.WebhookResponse{Webhook: dto.WebhookDTOFromDomain(sub)})
+}
+
+func (h *WebhookHandler) List(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "team_name query param is required",
+			},
+		})
+		return
+	}
+
+	subs, err := h.webhookService.ListSubscriptions(c.Request.Context(), teamName)
+	if err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	resp := dto.WebhookListResponse{
+		TeamName: teamName,
+		Webhooks: make([]dto.WebhookDTO, 0, len(subs)),
+	}
+	for _, s := range subs {
+		resp.Webhooks = append(resp.Webhooks, dto.WebhookDTOFromDomain(s))
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	var req dto.WebhookDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": gin.H{
+				"code":    "BAD_REQUEST",
+				"message": "invalid request body",
+			},
+		})
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), req.SubscriptionID); err != nil {
+		httperror.Write(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
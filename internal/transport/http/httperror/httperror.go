@@ -34,10 +34,24 @@ func Write(c *gin.Context, err error) {
 		c.JSON(http.StatusConflict, New("PR_EXISTS", err.Error()))
 	case errors.Is(err, domain.ErrPRMerged):
 		c.JSON(http.StatusConflict, New("PR_MERGED", err.Error()))
+	case errors.Is(err, domain.ErrPRNotOpen):
+		c.JSON(http.StatusConflict, New("PR_NOT_OPEN", err.Error()))
+	case errors.Is(err, domain.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, New("INVALID_TRANSITION", err.Error()))
+	case errors.Is(err, domain.ErrNotApproved):
+		c.JSON(http.StatusConflict, New("NOT_APPROVED", err.Error()))
 	case errors.Is(err, domain.ErrNotAssigned):
 		c.JSON(http.StatusConflict, New("NOT_ASSIGNED", err.Error()))
 	case errors.Is(err, domain.ErrNoCandidate):
 		c.JSON(http.StatusConflict, New("NO_CANDIDATE", err.Error()))
+	case errors.Is(err, domain.ErrNotEnoughReviewers):
+		c.JSON(http.StatusConflict, New("NOT_ENOUGH_REVIEWERS", err.Error()))
+	case errors.Is(err, domain.ErrConflict):
+		c.JSON(http.StatusConflict, New("CONFLICT", err.Error()))
+	case errors.Is(err, domain.ErrIdempotencyKeyReused):
+		c.JSON(http.StatusUnprocessableEntity, New("IDEMPOTENCY_KEY_REUSED", err.Error()))
+	case errors.Is(err, domain.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, New("BAD_REQUEST", err.Error()))
 	case errors.Is(err, domain.ErrNotFound):
 		c.JSON(http.StatusNotFound, New("NOT_FOUND", err.Error()))
 	default:
@@ -3,36 +3,87 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
 	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/middleware"
+	"github.com/gin-gonic/gin"
 )
 
 type memTeamRepo struct {
 	teams map[string]domain.Team
+	// users — откуда брать участников для GetByName, как это делает настоящий репозиторий.
+	users   *memUserRepo
+	cursors map[string]string
 }
 
-func (r *memTeamRepo) Create(ctx context.Context, name string) error {
+func (r *memTeamRepo) RoundRobinCursor(ctx context.Context, name string) (string, error) {
+	return r.cursors[name], nil
+}
+
+func (r *memTeamRepo) SetRoundRobinCursor(ctx context.Context, name, userID string) error {
+	if r.cursors == nil {
+		r.cursors = make(map[string]string)
+	}
+	r.cursors[name] = userID
+	return nil
+}
+
+func (r *memTeamRepo) Create(ctx context.Context, name string, settings domain.TeamSettings) error {
 	if r.teams == nil {
 		r.teams = make(map[string]domain.Team)
 	}
 	if _, ok := r.teams[name]; ok {
 		return domain.ErrTeamExists
 	}
-	r.teams[name] = domain.Team{Name: name}
+	r.teams[name] = domain.Team{Name: name, Settings: settings}
 	return nil
 }
 
+func (r *memTeamRepo) GetSettings(ctx context.Context, name string) (domain.TeamSettings, error) {
+	team, ok := r.teams[name]
+	if !ok {
+		return domain.TeamSettings{}, domain.ErrNotFound
+	}
+	return team.Settings, nil
+}
+
+func (r *memTeamRepo) UpdateSettings(ctx context.Context, name string, settings domain.TeamSettings) (domain.TeamSettings, error) {
+	team, ok := r.teams[name]
+	if !ok {
+		return domain.TeamSettings{}, domain.ErrNotFound
+	}
+	team.Settings = settings
+	r.teams[name] = team
+	return settings, nil
+}
+
 func (r *memTeamRepo) GetByName(ctx context.Context, name string) (domain.Team, error) {
 	team, ok := r.teams[name]
 	if !ok {
 		return domain.Team{}, domain.ErrNotFound
 	}
+	if r.users != nil {
+		team.Members = nil
+		for _, u := range r.users.usersByID {
+			if u.TeamName == name {
+				team.Members = append(team.Members, domain.TeamMember{ID: u.ID, Username: u.Username, Email: u.Email, IsActive: u.IsActive})
+			}
+		}
+	}
 	return team, nil
 }
 
@@ -47,6 +98,10 @@ func (r *memTeamRepo) List(ctx context.Context) ([]domain.Team, error) {
 type memUserRepo struct {
 	usersByID    map[string]domain.User
 	activeByTeam map[string][]domain.User
+	// absences и now повторяют фильтр настоящего репозитория: кто отсутствует в момент now,
+	// не попадает в ListActiveByTeam.
+	absences *memAbsenceRepo
+	now      func() time.Time
 }
 
 func (r *memUserRepo) Upsert(ctx context.Context, u domain.User) error {
@@ -82,22 +137,67 @@ func (r *memUserRepo) GetByID(ctx context.Context, id string) (domain.User, erro
 }
 
 func (r *memUserRepo) ListActiveByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
-	return append([]domain.User(nil), r.activeByTeam[teamName]...), nil
+	now := time.Now()
+	if r.now != nil {
+		now = r.now()
+	}
+
+	var res []domain.User
+	for _, u := range r.activeByTeam[teamName] {
+		if !r.absent(u.ID, now) {
+			res = append(res, u)
+		}
+	}
+	return res, nil
 }
 
-func (r *memUserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, error) {
+func (r *memUserRepo) absent(userID string, now time.Time) bool {
+	if r.absences == nil {
+		return false
+	}
+	for _, a := range r.absences.absences {
+		if a.UserID == userID && a.Covers(now) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memUserRepo) SetIsActive(ctx context.Context, userID string, isActive bool) (domain.User, bool, error) {
 	u, ok := r.usersByID[userID]
 	if !ok {
-		return domain.User{}, domain.ErrNotFound
+		return domain.User{}, false, domain.ErrNotFound
 	}
+	wasActive := u.IsActive
 	u.IsActive = isActive
 	r.usersByID[userID] = u
-	return u, nil
+	return u, wasActive, nil
+}
+
+func (r *memUserRepo) DeactivateMany(ctx context.Context, teamName string, userIDs []string) ([]domain.User, map[string]bool, error) {
+	var res []domain.User
+	wasActive := make(map[string]bool)
+	for _, id := range userIDs {
+		u, ok := r.usersByID[id]
+		if !ok || u.TeamName != teamName {
+			continue
+		}
+		wasActive[id] = u.IsActive
+		u.IsActive = false
+		if err := r.Upsert(ctx, u); err != nil {
+			return nil, nil, err
+		}
+		res = append(res, u)
+	}
+	return res, wasActive, nil
 }
 
 type memPRRepo struct {
 	prs       map[string]domain.PullRequest
 	reviewers map[string][]string
+	reviews   map[string]map[string]domain.ReviewDecision
+	// users нужен ListOpenReviews, чтобы взять команду автора для PR без reviewer_team.
+	users *memUserRepo
 }
 
 func (r *memPRRepo) Create(ctx context.Context, pr *domain.PullRequest) error {
@@ -129,9 +229,14 @@ func (r *memPRRepo) GetByID(ctx context.Context, id string) (domain.PullRequest,
 	if !ok {
 		return domain.PullRequest{}, domain.ErrNotFound
 	}
+	pr.Reviews, _ = r.ListReviews(ctx, id)
 	return pr, nil
 }
 
+func (r *memPRRepo) GetByIDForUpdate(ctx context.Context, id string) (domain.PullRequest, error) {
+	return r.GetByID(ctx, id)
+}
+
 func (r *memPRRepo) ListReviewers(ctx context.Context, prID string) ([]string, error) {
 	return append([]string(nil), r.reviewers[prID]...), nil
 }
@@ -191,6 +296,42 @@ func (r *memPRRepo) Merge(ctx context.Context, prID string) error {
 	return nil
 }
 
+func (r *memPRRepo) SetStatus(ctx context.Context, prID string, status domain.PRStatus) error {
+	pr, ok := r.prs[prID]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	pr.Status = status
+	pr.ClosedAt = nil
+	if status == domain.PullRequestStatusClosed {
+		now := time.Now().UTC()
+		pr.ClosedAt = &now
+	}
+	r.prs[prID] = pr
+	return nil
+}
+
+func (r *memPRRepo) SaveReview(ctx context.Context, review domain.ReviewDecision) error {
+	if r.reviews == nil {
+		r.reviews = make(map[string]map[string]domain.ReviewDecision)
+	}
+	if r.reviews[review.PullRequestID] == nil {
+		r.reviews[review.PullRequestID] = make(map[string]domain.ReviewDecision)
+	}
+	r.reviews[review.PullRequestID][review.ReviewerID] = review
+	return nil
+}
+
+func (r *memPRRepo) ListReviews(ctx context.Context, prID string) ([]domain.ReviewDecision, error) {
+	var res []domain.ReviewDecision
+	for _, id := range r.reviewers[prID] {
+		if d, ok := r.reviews[prID][id]; ok {
+			res = append(res, d)
+		}
+	}
+	return res, nil
+}
+
 func (r *memPRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]domain.PullRequest, error) {
 	var res []domain.PullRequest
 	for id, pr := range r.prs {
@@ -204,146 +345,870 @@ func (r *memPRRepo) ListByReviewer(ctx context.Context, reviewerID string) ([]do
 	return res, nil
 }
 
-func TestHTTP_FullFlow(t *testing.T) {
-	teamRepo := &memTeamRepo{}
-	userRepo := &memUserRepo{}
-	prRepo := &memPRRepo{}
+func (r *memPRRepo) OpenReviewCounts(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	res := make(map[string]int)
+	for id, pr := range r.prs {
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+		for _, rid := range r.reviewers[id] {
+			res[rid]++
+		}
+	}
+	return res, nil
+}
 
-	teamSvc := service.NewTeamService(teamRepo, userRepo)
-	userSvc := service.NewUserService(userRepo, prRepo)
-	prSvc := service.NewPRService(prRepo, userRepo, teamRepo)
+func (r *memPRRepo) ListOpenReviews(ctx context.Context, reviewerIDs []string) ([]domain.OpenReview, error) {
+	wanted := make(map[string]struct{}, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		wanted[id] = struct{}{}
+	}
 
-	services := service.NewServices(teamSvc, userSvc, prSvc)
-	router := NewRouter(services)
+	var res []domain.OpenReview
+	for id, pr := range r.prs {
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+		list := r.reviewers[id]
+		found := false
+		for _, rid := range list {
+			if _, ok := wanted[rid]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		team := pr.ReviewerTeam
+		if team == "" && r.users != nil {
+			team = r.users.usersByID[pr.AuthorID].TeamName
+		}
+		res = append(res, domain.OpenReview{
+			PullRequestID: id,
+			AuthorID:      pr.AuthorID,
+			TeamName:      team,
+			Reviewers:     append([]string(nil), list...),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].PullRequestID < res[j].PullRequestID
+	})
+	return res, nil
+}
 
-	doRequest := func(method, path string, body []byte) *httptest.ResponseRecorder {
-		req := httptest.NewRequest(method, path, bytes.NewReader(body))
-		req.Header.Set("Content-Type", "application/json")
-		rr := httptest.NewRecorder()
-		router.ServeHTTP(rr, req)
-		return rr
+func (r *memPRRepo) ApplyReviewerChanges(ctx context.Context, changes []domain.ReviewerChange) error {
+	for _, c := range changes {
+		if c.NewReviewerID == "" {
+			if err := r.RemoveReviewer(ctx, c.PullRequestID, c.OldReviewerID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := r.ReassignReviewer(ctx, c.PullRequestID, c.OldReviewerID, c.NewReviewerID); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	teamBody := []byte(`{
-		"team_name": "backend-int",
-		"members": [
-			{ "user_id": "author", "username": "Author", "is_active": true },
-			{ "user_id": "u1", "username": "Rev1", "is_active": true },
-			{ "user_id": "u2", "username": "Rev2", "is_active": true }
-		]
-	}`)
+type memAbsenceRepo struct {
+	nextID   int64
+	absences map[int64]domain.Absence
+}
 
-	resp := doRequest(http.MethodPost, "/team/add", teamBody)
-	if resp.Code != http.StatusCreated {
-		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+func (r *memAbsenceRepo) Create(ctx context.Context, a *domain.Absence) error {
+	if r.absences == nil {
+		r.absences = make(map[int64]domain.Absence)
 	}
+	r.nextID++
+	a.ID = r.nextID
+	r.absences[a.ID] = *a
+	return nil
+}
 
-	prBody := []byte(`{
-		"pull_request_id": "pr-int-1",
-		"pull_request_name": "Integration PR",
-		"author_id": "author"
-	}`)
-
-	resp = doRequest(http.MethodPost, "/pullRequest/create", prBody)
-	if resp.Code != http.StatusCreated {
-		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+func (r *memAbsenceRepo) GetByID(ctx context.Context, id int64) (domain.Absence, error) {
+	a, ok := r.absences[id]
+	if !ok {
+		return domain.Absence{}, domain.ErrNotFound
 	}
+	return a, nil
+}
 
-	var createResp struct {
-		PR struct {
-			ID                string   `json:"pull_request_id"`
-			AssignedReviewers []string `json:"assigned_reviewers"`
-			Status            string   `json:"status"`
-		} `json:"pr"`
+func (r *memAbsenceRepo) ListByUser(ctx context.Context, userID string) ([]domain.Absence, error) {
+	res := make([]domain.Absence, 0)
+	for _, a := range r.absences {
+		if a.UserID == userID {
+			res = append(res, a)
+		}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
-		t.Fatalf("decode create response: %v", err)
+	return res, nil
+}
+
+func (r *memAbsenceRepo) Update(ctx context.Context, a domain.Absence) (domain.Absence, error) {
+	existing, ok := r.absences[a.ID]
+	if !ok {
+		return domain.Absence{}, domain.ErrNotFound
 	}
+	a.UserID = existing.UserID
+	r.absences[a.ID] = a
+	return a, nil
+}
 
-	if createResp.PR.ID != "pr-int-1" {
-		t.Fatalf("expected PR id pr-int-1, got %s", createResp.PR.ID)
+func (r *memAbsenceRepo) Delete(ctx context.Context, id int64) error {
+	if _, ok := r.absences[id]; !ok {
+		return domain.ErrNotFound
 	}
+	delete(r.absences, id)
+	return nil
+}
 
-	if n := len(createResp.PR.AssignedReviewers); n == 0 || n > 2 {
-		t.Fatalf("expected 1 or 2 reviewers, got %v", createResp.PR.AssignedReviewers)
+type memEventRepo struct {
+	events []domain.ReviewerEvent
+}
+
+func (r *memEventRepo) Append(ctx context.Context, events ...domain.ReviewerEvent) error {
+	for _, e := range events {
+		e.ID = int64(len(r.events) + 1)
+		r.events = append(r.events, e)
 	}
-	for _, id := range createResp.PR.AssignedReviewers {
-		if id == "author" {
-			t.Fatalf("author must not be assigned as reviewer")
+	return nil
+}
+
+func (r *memEventRepo) ListByPR(ctx context.Context, prID string) ([]domain.ReviewerEvent, error) {
+	res := make([]domain.ReviewerEvent, 0)
+	for _, e := range r.events {
+		if e.PullRequestID == prID {
+			res = append(res, e)
 		}
 	}
+	return res, nil
+}
 
-	oldReviewer := createResp.PR.AssignedReviewers[0]
+type memAuditRepo struct {
+	entries []domain.AuditEntry
+}
 
-	reassignBody := struct {
-		PullRequestID string `json:"pull_request_id"`
-		OldUserID     string `json:"old_user_id"`
-	}{
-		PullRequestID: "pr-int-1",
-		OldUserID:     oldReviewer,
-	}
+func (r *memAuditRepo) Insert(ctx context.Context, e domain.AuditEntry) error {
+	e.ID = int64(len(r.entries) + 1)
+	r.entries = append(r.entries, e)
+	return nil
+}
 
-	buf, err := json.Marshal(reassignBody)
-	if err != nil {
-		t.Fatalf("marshal reassign body: %v", err)
+func (r *memAuditRepo) List(ctx context.Context, f domain.AuditFilter) ([]domain.AuditEntry, error) {
+	res := make([]domain.AuditEntry, 0)
+	for i := len(r.entries) - 1; i >= 0 && len(res) < f.Limit; i-- {
+		e := r.entries[i]
+		if f.ActorID != "" && e.ActorID != f.ActorID ||
+			f.EntityType != "" && e.EntityType != f.EntityType ||
+			f.EntityID != "" && e.EntityID != f.EntityID ||
+			!f.From.IsZero() && e.CreatedAt.Before(f.From) ||
+			!f.To.IsZero() && !e.CreatedAt.Before(f.To) {
+			continue
+		}
+		res = append(res, e)
 	}
+	return res, nil
+}
 
-	resp = doRequest(http.MethodPost, "/pullRequest/reassign", buf)
-	if resp.Code != http.StatusOK {
-		t.Fatalf("pullRequest/reassign: expected status 200, got %d", resp.Code)
+type memWebhookRepo struct {
+	subs       []domain.WebhookSubscription
+	deliveries []domain.WebhookDelivery
+}
+
+func (r *memWebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	sub.ID = int64(len(r.subs) + 1)
+	sub.CreatedAt = time.Now().UTC()
+	r.subs = append(r.subs, *sub)
+	return nil
+}
+
+func (r *memWebhookRepo) ListSubscriptions(ctx context.Context, teamName string) ([]domain.WebhookSubscription, error) {
+	res := make([]domain.WebhookSubscription, 0)
+	for _, s := range r.subs {
+		if s.TeamName == teamName {
+			res = append(res, s)
+		}
 	}
+	return res, nil
+}
 
-	var reassignResp struct {
-		PR struct {
-			AssignedReviewers []string `json:"assigned_reviewers"`
-		} `json:"pr"`
-		ReplacedBy string `json:"replaced_by"`
+func (r *memWebhookRepo) DeleteSubscription(ctx context.Context, id int64) error {
+	for i, s := range r.subs {
+		if s.ID == id {
+			r.subs = append(r.subs[:i], r.subs[i+1:]...)
+			return nil
+		}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&reassignResp); err != nil {
-		t.Fatalf("decode reassign response: %v", err)
+	return domain.ErrNotFound
+}
+
+func (r *memWebhookRepo) Enqueue(ctx context.Context, deliveries ...domain.WebhookDelivery) error {
+	for _, d := range deliveries {
+		d.ID = int64(len(r.deliveries) + 1)
+		d.Status = domain.WebhookDeliveryPending
+		r.deliveries = append(r.deliveries, d)
 	}
+	return nil
+}
 
-	if reassignResp.ReplacedBy == "" {
-		t.Fatalf("expected non-empty replaced_by")
+func (r *memWebhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *memWebhookRepo) MarkDelivered(ctx context.Context, id int64) error {
+	return nil
+}
+
+func (r *memWebhookRepo) MarkRetry(ctx context.Context, id int64, attempts int, next time.Time, lastError string) error {
+	return nil
+}
+
+func (r *memWebhookRepo) MarkFailed(ctx context.Context, id int64, attempts int, lastError string) error {
+	return nil
+}
+
+type memOutboxRepo struct {
+	messages []domain.OutboxMessage
+}
+
+func (r *memOutboxRepo) Add(ctx context.Context, messages ...domain.OutboxMessage) error {
+	for _, m := range messages {
+		m.ID = int64(len(r.messages) + 1)
+		r.messages = append(r.messages, m)
 	}
+	return nil
+}
 
-	for _, id := range reassignResp.PR.AssignedReviewers {
-		if id == oldReviewer {
-			t.Fatalf("old reviewer should not remain assigned after reassign")
+func (r *memOutboxRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var res []domain.OutboxMessage
+	for _, m := range r.messages {
+		if m.PublishedAt == nil && len(res) < limit {
+			res = append(res, m)
 		}
 	}
+	return res, nil
+}
 
-	mergeBody := []byte(`{ "pull_request_id": "pr-int-1" }`)
+func (r *memOutboxRepo) MarkPublished(ctx context.Context, id int64) error {
+	now := time.Now()
+	r.messages[id-1].PublishedAt = &now
+	return nil
+}
 
-	resp = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
-	if resp.Code != http.StatusOK {
-		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+func (r *memOutboxRepo) MarkRetry(
+	ctx context.Context,
+	id int64,
+	attempts int,
+	next time.Time,
+	lastError string,
+	deliveredSinks []string,
+) error {
+	r.messages[id-1].Attempts = attempts
+	r.messages[id-1].LastError = lastError
+	r.messages[id-1].DeliveredSinks = deliveredSinks
+	return nil
+}
+
+type memAccountRepo struct {
+	accounts []domain.ExternalAccount
+}
+
+func (r *memAccountRepo) Upsert(ctx context.Context, account *domain.ExternalAccount) error {
+	account.CreatedAt = time.Now()
+	for i, a := range r.accounts {
+		if a.Provider == account.Provider && a.Login == account.Login {
+			r.accounts[i] = *account
+			return nil
+		}
 	}
+	r.accounts = append(r.accounts, *account)
+	return nil
+}
 
-	var mergeResp struct {
-		PR struct {
-			Status string `json:"status"`
-		} `json:"pr"`
+func (r *memAccountRepo) GetUserID(ctx context.Context, provider domain.CodeHost, login string) (string, error) {
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.Login == login {
+			return a.UserID, nil
+		}
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&mergeResp); err != nil {
-		t.Fatalf("decode merge response: %v", err)
+	return "", domain.ErrNotFound
+}
+
+func (r *memAccountRepo) GetLogin(ctx context.Context, provider domain.CodeHost, userID string) (string, error) {
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.UserID == userID {
+			return a.Login, nil
+		}
 	}
-	if mergeResp.PR.Status != string(domain.PullRequestStatusMerged) {
-		t.Fatalf("expected status MERGED, got %s", mergeResp.PR.Status)
+	return "", domain.ErrNotFound
+}
+
+func (r *memAccountRepo) List(ctx context.Context, provider domain.CodeHost) ([]domain.ExternalAccount, error) {
+	res := make([]domain.ExternalAccount, 0)
+	for _, a := range r.accounts {
+		if a.Provider == provider {
+			res = append(res, a)
+		}
 	}
+	return res, nil
+}
 
-	resp = doRequest(http.MethodPost, "/pullRequest/reassign", buf)
-	if resp.Code != http.StatusConflict {
-		t.Fatalf("pullRequest/reassign after merge: expected status 409, got %d", resp.Code)
+type memNotificationRepo struct {
+	prefs     map[string]domain.NotificationPreferences
+	templates map[string]domain.NotificationTemplate
+}
+
+func (r *memNotificationRepo) GetPreferences(ctx context.Context, userID string) (domain.NotificationPreferences, error) {
+	p, ok := r.prefs[userID]
+	if !ok {
+		return domain.NotificationPreferences{}, domain.ErrNotFound
 	}
+	return p, nil
+}
 
-	var errResp struct {
-		Error struct {
-			Code string `json:"code"`
-		} `json:"error"`
+func (r *memNotificationRepo) UpsertPreferences(ctx context.Context, prefs *domain.NotificationPreferences) error {
+	if r.prefs == nil {
+		r.prefs = make(map[string]domain.NotificationPreferences)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
-		t.Fatalf("decode error response: %v", err)
+	prefs.UpdatedAt = time.Now()
+	r.prefs[prefs.UserID] = *prefs
+	return nil
+}
+
+func (r *memNotificationRepo) ListTemplates(ctx context.Context, teamName string) ([]domain.NotificationTemplate, error) {
+	res := make([]domain.NotificationTemplate, 0)
+	for _, t := range r.templates {
+		if t.TeamName == teamName {
+			res = append(res, t)
+		}
+	}
+	return res, nil
+}
+
+func (r *memNotificationRepo) GetTemplate(
+	ctx context.Context,
+	teamName string,
+	kind domain.NotificationKind,
+) (domain.NotificationTemplate, error) {
+	t, ok := r.templates[teamName+"/"+string(kind)]
+	if !ok {
+		return domain.NotificationTemplate{}, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *memNotificationRepo) UpsertTemplate(ctx context.Context, tpl *domain.NotificationTemplate) error {
+	if r.templates == nil {
+		r.templates = make(map[string]domain.NotificationTemplate)
+	}
+	tpl.UpdatedAt = time.Now()
+	r.templates[tpl.TeamName+"/"+string(tpl.Kind)] = *tpl
+	return nil
+}
+
+func (r *memNotificationRepo) DeleteTemplate(ctx context.Context, teamName string, kind domain.NotificationKind) error {
+	delete(r.templates, teamName+"/"+string(kind))
+	return nil
+}
+
+// recordingNotifier запоминает уведомления вместо отправки в Slack.
+type recordingNotifier struct {
+	sent []domain.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, msg domain.Notification) error {
+	n.sent = append(n.sent, msg)
+	return nil
+}
+
+type memIdempotencyRepo struct {
+	records map[string]domain.IdempotencyRecord
+}
+
+func (r *memIdempotencyRepo) Reserve(
+	ctx context.Context,
+	rec domain.IdempotencyRecord,
+	ttl, lease time.Duration,
+) (domain.IdempotencyRecord, bool, error) {
+	if r.records == nil {
+		r.records = make(map[string]domain.IdempotencyRecord)
+	}
+	k := rec.Endpoint + " " + rec.Key
+	if existing, ok := r.records[k]; ok && time.Since(existing.CreatedAt) < ttl {
+		if !existing.InProgress() || time.Since(existing.CreatedAt) < lease {
+			return existing, false, nil
+		}
+	}
+	rec.CreatedAt = time.Now()
+	r.records[k] = rec
+	return rec, true, nil
+}
+
+func (r *memIdempotencyRepo) Complete(ctx context.Context, key, endpoint string, statusCode int, body []byte) error {
+	k := endpoint + " " + key
+	rec := r.records[k]
+	rec.StatusCode = statusCode
+	rec.ResponseBody = append([]byte(nil), body...)
+	r.records[k] = rec
+	return nil
+}
+
+func (r *memIdempotencyRepo) Release(ctx context.Context, key, endpoint string) error {
+	delete(r.records, endpoint+" "+key)
+	return nil
+}
+
+// memSLARepo вычисляет просрочки по memPRRepo; время назначения тест задаёт в assignedAt.
+type memSLARepo struct {
+	prs        *memPRRepo
+	users      *memUserRepo
+	teams      *memTeamRepo
+	assignedAt map[string]time.Time
+	breaches   []domain.SLABreach
+}
+
+func (r *memSLARepo) ListOverdue(ctx context.Context, now time.Time) ([]domain.OverdueReview, error) {
+	var res []domain.OverdueReview
+	for prID, pr := range r.prs.prs {
+		if pr.Status != domain.PullRequestStatusOpen {
+			continue
+		}
+		author, err := r.users.GetByID(ctx, pr.AuthorID)
+		if err != nil {
+			continue
+		}
+		team := pr.ReviewerTeam
+		if team == "" {
+			team = author.TeamName
+		}
+		settings, err := r.teams.GetSettings(ctx, team)
+		if err != nil || settings.SLAHours == 0 {
+			continue
+		}
+		for _, reviewerID := range r.prs.reviewers[prID] {
+			at, ok := r.assignedAt[prID+"/"+reviewerID]
+			if !ok || at.Add(time.Duration(settings.SLAHours)*time.Hour).After(now) {
+				continue
+			}
+			if _, reviewed := r.prs.reviews[prID][reviewerID]; reviewed {
+				continue
+			}
+			res = append(res, domain.OverdueReview{
+				PullRequestID:   prID,
+				PullRequestName: pr.Name,
+				ReviewerID:      reviewerID,
+				AssignedAt:      at,
+				TeamName:        team,
+				SLAHours:        settings.SLAHours,
+				SLAWorkingHours: settings.SLAWorkingHours,
+				SLAAutoReassign: settings.SLAAutoReassign,
+				TeamLead:        settings.TeamLead,
+			})
+		}
+	}
+	return res, nil
+}
+
+func (r *memSLARepo) RecordBreach(ctx context.Context, breach *domain.SLABreach) (bool, error) {
+	for _, b := range r.breaches {
+		if b.PullRequestID == breach.PullRequestID && b.ReviewerID == breach.ReviewerID && b.AssignedAt.Equal(breach.AssignedAt) {
+			return false, nil
+		}
+	}
+	breach.ID = int64(len(r.breaches) + 1)
+	breach.PullRequestName = r.prs.prs[breach.PullRequestID].Name
+	r.breaches = append(r.breaches, *breach)
+	return true, nil
+}
+
+func (r *memSLARepo) SetNewReviewer(ctx context.Context, breachID int64, newReviewerID string) error {
+	r.breaches[breachID-1].NewReviewerID = newReviewerID
+	return nil
+}
+
+func (r *memSLARepo) ListBreaches(ctx context.Context, f domain.SLABreachFilter) ([]domain.SLABreach, error) {
+	res := make([]domain.SLABreach, 0)
+	for i := len(r.breaches) - 1; i >= 0; i-- {
+		b := r.breaches[i]
+		if f.TeamName != "" && b.TeamName != f.TeamName {
+			continue
+		}
+		b.Open = false
+		if r.prs.prs[b.PullRequestID].Status == domain.PullRequestStatusOpen {
+			for _, id := range r.prs.reviewers[b.PullRequestID] {
+				if id == b.ReviewerID {
+					b.Open = true
+				}
+			}
+		}
+		if f.OpenOnly && !b.Open {
+			continue
+		}
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+// memStatsRepo считает сводку по ревьюверам по in-memory репозиториям, как агрегирующий запрос.
+type memStatsRepo struct {
+	prs    *memPRRepo
+	users  *memUserRepo
+	events *memEventRepo
+}
+
+func (r *memStatsRepo) ReviewerStats(ctx context.Context, f domain.ReviewerStatsFilter) ([]domain.ReviewerStats, error) {
+	inPeriod := func(t time.Time) bool {
+		return (f.From.IsZero() || !t.Before(f.From)) && (f.To.IsZero() || t.Before(f.To))
+	}
+
+	byID := make(map[string]*domain.ReviewerStats)
+	res := make([]domain.ReviewerStats, 0)
+	for _, u := range r.users.usersByID {
+		if f.TeamName == "" || u.TeamName == f.TeamName {
+			res = append(res, domain.ReviewerStats{UserID: u.ID, Username: u.Username, TeamName: u.TeamName})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].TeamName != res[j].TeamName {
+			return res[i].TeamName < res[j].TeamName
+		}
+		return res[i].UserID < res[j].UserID
+	})
+	for i := range res {
+		byID[res[i].UserID] = &res[i]
+	}
+
+	for _, e := range r.events.events {
+		if !inPeriod(e.CreatedAt) {
+			continue
+		}
+		switch e.Type {
+		case domain.ReviewerEventAssigned:
+			if s, ok := byID[e.ReviewerID]; ok {
+				s.Assignments++
+			}
+		case domain.ReviewerEventReplaced, domain.ReviewerEventRemoved:
+			if s, ok := byID[e.ReviewerID]; ok {
+				s.ReassignedAway++
+			}
+			if s, ok := byID[e.ReplacedBy]; ok {
+				s.Assignments++
+			}
+		}
+	}
+
+	merged := make(map[string][]time.Duration)
+	for prID, pr := range r.prs.prs {
+		for _, id := range r.prs.reviewers[prID] {
+			s, ok := byID[id]
+			if !ok {
+				continue
+			}
+			switch {
+			case pr.Status == domain.PullRequestStatusOpen:
+				s.OpenReviews++
+			case pr.Status == domain.PullRequestStatusMerged && pr.MergedAt != nil && inPeriod(*pr.MergedAt):
+				s.MergedReviews++
+				merged[id] = append(merged[id], pr.MergedAt.Sub(pr.CreatedAt))
+			}
+		}
+	}
+	for id, durations := range merged {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		mid := len(durations) / 2
+		median := durations[mid]
+		if len(durations)%2 == 0 {
+			median = (durations[mid-1] + durations[mid]) / 2
+		}
+		byID[id].MedianTimeToMerge = median
+	}
+
+	return res, nil
+}
+
+type memTxManager struct{}
+
+func (memTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type testEnv struct {
+	teamRepo    *memTeamRepo
+	userRepo    *memUserRepo
+	prRepo      *memPRRepo
+	absenceRepo *memAbsenceRepo
+	eventRepo   *memEventRepo
+	idemRepo    *memIdempotencyRepo
+	auditRepo   *memAuditRepo
+	webhookRepo *memWebhookRepo
+	outboxRepo  *memOutboxRepo
+	accountRepo *memAccountRepo
+	notifyRepo  *memNotificationRepo
+	notifier    *recordingNotifier
+	slaRepo     *memSLARepo
+	statsRepo   *memStatsRepo
+	relay       *service.OutboxRelay
+	sla         *service.SLAScheduler
+	router      http.Handler
+}
+
+const (
+	testGitHubSecret = "github-test-secret"
+	testGitLabToken  = "gitlab-test-token"
+)
+
+func newTestEnv() *testEnv {
+	env := &testEnv{
+		teamRepo:    &memTeamRepo{},
+		userRepo:    &memUserRepo{},
+		prRepo:      &memPRRepo{},
+		absenceRepo: &memAbsenceRepo{},
+		eventRepo:   &memEventRepo{},
+		idemRepo:    &memIdempotencyRepo{},
+		auditRepo:   &memAuditRepo{},
+		webhookRepo: &memWebhookRepo{},
+		outboxRepo:  &memOutboxRepo{},
+		accountRepo: &memAccountRepo{},
+		notifyRepo:  &memNotificationRepo{},
+		notifier:    &recordingNotifier{},
+	}
+	env.teamRepo.users = env.userRepo
+	env.userRepo.absences = env.absenceRepo
+	env.prRepo.users = env.userRepo
+	env.slaRepo = &memSLARepo{
+		prs:        env.prRepo,
+		users:      env.userRepo,
+		teams:      env.teamRepo,
+		assignedAt: map[string]time.Time{},
+	}
+	env.statsRepo = &memStatsRepo{
+		prs:    env.prRepo,
+		users:  env.userRepo,
+		events: env.eventRepo,
+	}
+
+	webhookSvc := service.NewWebhookService(env.webhookRepo, env.teamRepo)
+	notificationSvc := service.NewNotificationService(env.notifyRepo, env.userRepo, env.teamRepo)
+	publisher := service.NewOutboxPublisher(env.outboxRepo)
+	env.relay = service.NewOutboxRelay(env.outboxRepo, []service.EventSink{
+		service.NewWebhookSink(webhookSvc),
+		service.NewNotificationSink(notificationSvc, env.prRepo, env.userRepo, env.notifier),
+	}, service.OutboxRelayConfig{
+		BatchSize: 100,
+	})
+
+	prSvc := service.NewPRService(env.prRepo, env.userRepo, env.teamRepo, env.eventRepo, nil, publisher, memTxManager{})
+	teamSvc := service.NewTeamService(env.teamRepo, env.userRepo, publisher, memTxManager{}, prSvc)
+	userSvc := service.NewUserService(env.userRepo, env.prRepo, env.absenceRepo, publisher, memTxManager{}, prSvc)
+
+	idemSvc := service.NewIdempotencyService(env.idemRepo)
+	auditSvc := service.NewAuditService(env.auditRepo)
+
+	accountSvc := service.NewAccountService(env.accountRepo, env.userRepo)
+	githubSvc := service.NewGitHubService(prSvc, accountSvc, testGitHubSecret)
+	gitlabSvc := service.NewGitLabService(prSvc, accountSvc, env.teamRepo, testGitLabToken, map[string]string{
+		"Platform/Billing-API": "backend",
+	})
+	env.sla = service.NewSLAScheduler(env.slaRepo, prSvc, publisher, memTxManager{}, service.SLAConfig{
+		Calendar: domain.WorkCalendar{Start: 9 * time.Hour, End: 18 * time.Hour, Location: time.UTC},
+	})
+
+	env.router = NewRouter(service.NewServices(
+		teamSvc,
+		userSvc,
+		prSvc,
+		idemSvc,
+		auditSvc,
+		webhookSvc,
+		accountSvc,
+		githubSvc,
+		gitlabSvc,
+		notificationSvc,
+		service.NewStatsService(env.slaRepo, env.statsRepo, env.teamRepo),
+	))
+	return env
+}
+
+func (e *testEnv) do(method, path string, body []byte) *httptest.ResponseRecorder {
+	return e.doWithHeaders(method, path, body, nil)
+}
+
+func (e *testEnv) doWithHeaders(method, path string, body []byte, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rr := httptest.NewRecorder()
+	e.router.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHTTP_FullFlow(t *testing.T) {
+	teamRepo := &memTeamRepo{}
+	userRepo := &memUserRepo{}
+	prRepo := &memPRRepo{users: userRepo}
+
+	prSvc := service.NewPRService(prRepo, userRepo, teamRepo, &memEventRepo{}, nil, nil, memTxManager{})
+	teamSvc := service.NewTeamService(teamRepo, userRepo, nil, memTxManager{}, prSvc)
+	userSvc := service.NewUserService(userRepo, prRepo, &memAbsenceRepo{}, nil, memTxManager{}, prSvc)
+
+	idemSvc := service.NewIdempotencyService(&memIdempotencyRepo{})
+
+	auditSvc := service.NewAuditService(&memAuditRepo{})
+
+	webhookSvc := service.NewWebhookService(&memWebhookRepo{}, teamRepo)
+
+	accountSvc := service.NewAccountService(&memAccountRepo{}, userRepo)
+	githubSvc := service.NewGitHubService(prSvc, accountSvc, "")
+	gitlabSvc := service.NewGitLabService(prSvc, accountSvc, teamRepo, "", nil)
+
+	notificationSvc := service.NewNotificationService(&memNotificationRepo{}, userRepo, teamRepo)
+
+	services := service.NewServices(
+		teamSvc,
+		userSvc,
+		prSvc,
+		idemSvc,
+		auditSvc,
+		webhookSvc,
+		accountSvc,
+		githubSvc,
+		gitlabSvc,
+		notificationSvc,
+		service.NewStatsService(&memSLARepo{}, &memStatsRepo{}, teamRepo),
+	)
+	router := NewRouter(services)
+
+	doRequest := func(method, path string, body []byte) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		return rr
+	}
+
+	teamBody := []byte(`{
+		"team_name": "backend-int",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "u1", "username": "Rev1", "is_active": true },
+			{ "user_id": "u2", "username": "Rev2", "is_active": true }
+		]
+	}`)
+
+	resp := doRequest(http.MethodPost, "/team/add", teamBody)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	prBody := []byte(`{
+		"pull_request_id": "pr-int-1",
+		"pull_request_name": "Integration PR",
+		"author_id": "author"
+	}`)
+
+	resp = doRequest(http.MethodPost, "/pullRequest/create", prBody)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+	}
+
+	var createResp struct {
+		PR struct {
+			ID                string   `json:"pull_request_id"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+			Status            string   `json:"status"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+
+	if createResp.PR.ID != "pr-int-1" {
+		t.Fatalf("expected PR id pr-int-1, got %s", createResp.PR.ID)
+	}
+
+	if n := len(createResp.PR.AssignedReviewers); n == 0 || n > 2 {
+		t.Fatalf("expected 1 or 2 reviewers, got %v", createResp.PR.AssignedReviewers)
+	}
+	for _, id := range createResp.PR.AssignedReviewers {
+		if id == "author" {
+			t.Fatalf("author must not be assigned as reviewer")
+		}
+	}
+
+	oldReviewer := createResp.PR.AssignedReviewers[0]
+
+	reassignBody := struct {
+		PullRequestID string `json:"pull_request_id"`
+		OldUserID     string `json:"old_user_id"`
+	}{
+		PullRequestID: "pr-int-1",
+		OldUserID:     oldReviewer,
+	}
+
+	buf, err := json.Marshal(reassignBody)
+	if err != nil {
+		t.Fatalf("marshal reassign body: %v", err)
+	}
+
+	resp = doRequest(http.MethodPost, "/pullRequest/reassign", buf)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/reassign: expected status 200, got %d", resp.Code)
+	}
+
+	var reassignResp struct {
+		PR struct {
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+		ReplacedBy string `json:"replaced_by"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reassignResp); err != nil {
+		t.Fatalf("decode reassign response: %v", err)
+	}
+
+	if reassignResp.ReplacedBy == "" {
+		t.Fatalf("expected non-empty replaced_by")
+	}
+
+	for _, id := range reassignResp.PR.AssignedReviewers {
+		if id == oldReviewer {
+			t.Fatalf("old reviewer should not remain assigned after reassign")
+		}
+	}
+
+	mergeBody := []byte(`{ "pull_request_id": "pr-int-1" }`)
+
+	resp = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+	}
+
+	var mergeResp struct {
+		PR struct {
+			Status string `json:"status"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&mergeResp); err != nil {
+		t.Fatalf("decode merge response: %v", err)
+	}
+	if mergeResp.PR.Status != string(domain.PullRequestStatusMerged) {
+		t.Fatalf("expected status MERGED, got %s", mergeResp.PR.Status)
+	}
+
+	resp = doRequest(http.MethodPost, "/pullRequest/reassign", buf)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("pullRequest/reassign after merge: expected status 409, got %d", resp.Code)
+	}
+
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
 	}
 	if errResp.Error.Code != "PR_MERGED" {
 		t.Fatalf("expected error code PR_MERGED, got %s", errResp.Error.Code)
@@ -383,3 +1248,1560 @@ func TestHTTP_FullFlow(t *testing.T) {
 		t.Fatalf("expected pr-int-1 in reviewer PR list, got %v", reviewResp.PullRequests)
 	}
 }
+
+func TestHTTP_TeamSettings(t *testing.T) {
+	env := newTestEnv()
+	doRequest := env.do
+
+	teamBody := []byte(`{
+		"team_name": "docs",
+		"members": [{ "user_id": "d1", "username": "Doc", "is_active": true }],
+		"settings": { "assignment_strategy": "round_robin" }
+	}`)
+	if resp := doRequest(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	var settingsResp struct {
+		TeamName string `json:"team_name"`
+		Settings struct {
+			AssignmentStrategy string `json:"assignment_strategy"`
+		} `json:"settings"`
+	}
+
+	resp := doRequest(http.MethodGet, "/team/settings?team_name=docs", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("team/settings: expected status 200, got %d", resp.Code)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settingsResp); err != nil {
+		t.Fatalf("decode settings response: %v", err)
+	}
+	if settingsResp.Settings.AssignmentStrategy != "round_robin" {
+		t.Fatalf("expected round_robin, got %s", settingsResp.Settings.AssignmentStrategy)
+	}
+
+	resp = doRequest(http.MethodPost, "/team/settings", []byte(`{"team_name": "docs", "assignment_strategy": "weighted"}`))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("team/settings update: expected status 200, got %d", resp.Code)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settingsResp); err != nil {
+		t.Fatalf("decode settings response: %v", err)
+	}
+	if settingsResp.Settings.AssignmentStrategy != "weighted" {
+		t.Fatalf("expected weighted, got %s", settingsResp.Settings.AssignmentStrategy)
+	}
+
+	resp = doRequest(http.MethodPost, "/team/settings", []byte(`{"team_name": "docs", "assignment_strategy": "coin_flip"}`))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("team/settings with unknown strategy: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_TeamStalePolicy(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "docs",
+		"members": [{ "user_id": "d1", "username": "Doc", "is_active": true }],
+		"settings": { "stale_after_days": 7 }
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	resp := env.do(http.MethodPost, "/team/settings", []byte(`{"team_name": "docs", "stale_close_after_days": 14}`))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("team/settings update: expected status 200, got %d", resp.Code)
+	}
+	var settingsResp struct {
+		Settings struct {
+			StaleAfterDays      int `json:"stale_after_days"`
+			StaleCloseAfterDays int `json:"stale_close_after_days"`
+		} `json:"settings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&settingsResp); err != nil {
+		t.Fatalf("decode settings response: %v", err)
+	}
+	if settingsResp.Settings.StaleAfterDays != 7 || settingsResp.Settings.StaleCloseAfterDays != 14 {
+		t.Fatalf("unexpected stale policy: %+v", settingsResp.Settings)
+	}
+
+	// Закрывать можно только то, что сначала помечено устаревшим.
+	resp = env.do(http.MethodPost, "/team/settings", []byte(`{"team_name": "docs", "stale_after_days": 0}`))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("close without stale policy: expected status 400, got %d", resp.Code)
+	}
+	resp = env.do(http.MethodPost, "/team/settings", []byte(`{"team_name": "docs", "stale_after_days": 1000}`))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("stale_after_days over the limit: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_TeamMemberEmail(t *testing.T) {
+	env := newTestEnv()
+
+	bad := []byte(`{"team_name": "mail", "members": [{ "user_id": "m1", "username": "M", "is_active": true, "email": "not-an-email" }]}`)
+	if resp := env.do(http.MethodPost, "/team/add", bad); resp.Code != http.StatusBadRequest {
+		t.Fatalf("team/add with invalid email: expected status 400, got %d", resp.Code)
+	}
+
+	teamBody := []byte(`{
+		"team_name": "mail",
+		"members": [
+			{ "user_id": "m1", "username": "M1", "is_active": true, "email": "m1@example.com" },
+			{ "user_id": "m2", "username": "M2", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	resp := env.do(http.MethodGet, "/team/get?team_name=mail", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("team/get: expected status 200, got %d", resp.Code)
+	}
+	var team struct {
+		Members []struct {
+			UserID string `json:"user_id"`
+			Email  string `json:"email"`
+		} `json:"members"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&team); err != nil {
+		t.Fatalf("decode team response: %v", err)
+	}
+	emails := make(map[string]string)
+	for _, m := range team.Members {
+		emails[m.UserID] = m.Email
+	}
+	if emails["m1"] != "m1@example.com" || emails["m2"] != "" {
+		t.Fatalf("unexpected member emails: %v", emails)
+	}
+}
+
+func TestHTTP_AbsentReviewerIsSkipped(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "ops",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "o1", "username": "O1", "is_active": true },
+			{ "user_id": "o2", "username": "O2", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	now := time.Now().UTC()
+	absence := []byte(`{
+		"user_id": "o1",
+		"starts_at": "` + now.Add(-time.Hour).Format(time.RFC3339) + `",
+		"ends_at": "` + now.Add(time.Hour).Format(time.RFC3339) + `"
+	}`)
+	if resp := env.do(http.MethodPost, "/users/absence/add", absence); resp.Code != http.StatusCreated {
+		t.Fatalf("users/absence/add: expected status 201, got %d", resp.Code)
+	}
+
+	createPR := func(id string) []string {
+		t.Helper()
+		body := []byte(`{"pull_request_id": "` + id + `", "pull_request_name": "Change", "author_id": "author", "reviewers_count": 2}`)
+		if resp := env.do(http.MethodPost, "/pullRequest/create", body); resp.Code != http.StatusCreated {
+			t.Fatalf("pullRequest/create %s: expected status 201, got %d: %s", id, resp.Code, resp.Body)
+		}
+		return env.prRepo.reviewers[id]
+	}
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		if got := createPR(id); len(got) != 1 || got[0] != "o2" {
+			t.Fatalf("%s: absent o1 must not be assigned, got %v", id, got)
+		}
+	}
+
+	// Отсутствие закончилось — o1 снова в выборке.
+	env.userRepo.now = func() time.Time { return now.Add(2 * time.Hour) }
+	got := createPR("pr-4")
+	if len(got) != 2 || (got[0] != "o1" && got[1] != "o1") {
+		t.Fatalf("o1 must be assigned again after the absence, got %v", got)
+	}
+}
+
+func TestHTTP_Absences(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "ops",
+		"members": [{ "user_id": "o1", "username": "Ops", "is_active": true }]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	resp := env.do(http.MethodPost, "/users/absence/add", []byte(`{
+		"user_id": "o1",
+		"starts_at": "2026-07-01T00:00:00Z",
+		"ends_at": "2026-07-15T00:00:00Z",
+		"reason": "vacation"
+	}`))
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("users/absence/add: expected status 201, got %d", resp.Code)
+	}
+
+	var created struct {
+		Absence struct {
+			AbsenceID int64  `json:"absence_id"`
+			Reason    string `json:"reason"`
+		} `json:"absence"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		t.Fatalf("decode absence response: %v", err)
+	}
+
+	resp = env.do(http.MethodPost, "/users/absence/add", []byte(`{
+		"user_id": "o1",
+		"starts_at": "2026-07-15T00:00:00Z",
+		"ends_at": "2026-07-01T00:00:00Z"
+	}`))
+	if resp.Code != http.StatusBadRequest {
+		t.Fatalf("users/absence/add with inverted period: expected status 400, got %d", resp.Code)
+	}
+
+	resp = env.do(http.MethodGet, "/users/absence/list?user_id=o1", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("users/absence/list: expected status 200, got %d", resp.Code)
+	}
+
+	var list struct {
+		Absences []struct {
+			AbsenceID int64 `json:"absence_id"`
+		} `json:"absences"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("decode absence list: %v", err)
+	}
+	if len(list.Absences) != 1 || list.Absences[0].AbsenceID != created.Absence.AbsenceID {
+		t.Fatalf("expected the created absence in list, got %v", list.Absences)
+	}
+
+	deleteBody, _ := json.Marshal(map[string]int64{"absence_id": created.Absence.AbsenceID})
+	if resp := env.do(http.MethodPost, "/users/absence/delete", deleteBody); resp.Code != http.StatusNoContent {
+		t.Fatalf("users/absence/delete: expected status 204, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/users/absence/delete", deleteBody); resp.Code != http.StatusNotFound {
+		t.Fatalf("users/absence/delete twice: expected status 404, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_TeamDeactivateUsers(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true },
+			{ "user_id": "r3", "username": "R3", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	for _, id := range []string{"pr-a", "pr-b", "pr-c"} {
+		body, _ := json.Marshal(map[string]string{
+			"pull_request_id":   id,
+			"pull_request_name": id,
+			"author_id":         "author",
+		})
+		if resp := env.do(http.MethodPost, "/pullRequest/create", body); resp.Code != http.StatusCreated {
+			t.Fatalf("pullRequest/create %s: expected status 201, got %d", id, resp.Code)
+		}
+	}
+
+	deactivateBody := []byte(`{"team_name": "core", "user_ids": ["r1", "r2", "r1"]}`)
+	resp := env.do(http.MethodPost, "/team/deactivateUsers", deactivateBody)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("team/deactivateUsers: expected status 200, got %d", resp.Code)
+	}
+
+	var deactivateResp struct {
+		Deactivated []struct {
+			UserID   string `json:"user_id"`
+			IsActive bool   `json:"is_active"`
+		} `json:"deactivated"`
+		Reassignments []struct {
+			PullRequestID string `json:"pull_request_id"`
+			NewReviewerID string `json:"new_reviewer_id"`
+		} `json:"reassignments"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&deactivateResp); err != nil {
+		t.Fatalf("decode deactivate response: %v", err)
+	}
+	if len(deactivateResp.Deactivated) != 2 {
+		t.Fatalf("expected 2 deactivated users, got %v", deactivateResp.Deactivated)
+	}
+	for _, r := range deactivateResp.Reassignments {
+		if r.NewReviewerID != "r3" {
+			t.Fatalf("expected every review to move to r3, got %+v", r)
+		}
+	}
+
+	for _, prID := range []string{"pr-a", "pr-b", "pr-c"} {
+		for _, id := range env.prRepo.reviewers[prID] {
+			if id == "r1" || id == "r2" {
+				t.Fatalf("%s still assigned to deactivated %s", prID, id)
+			}
+		}
+	}
+
+	resp = env.do(http.MethodPost, "/team/deactivateUsers", deactivateBody)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("repeated team/deactivateUsers: expected status 200, got %d", resp.Code)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&deactivateResp); err != nil {
+		t.Fatalf("decode deactivate response: %v", err)
+	}
+	if len(deactivateResp.Reassignments) != 0 {
+		t.Fatalf("expected no reassignments on repeat, got %v", deactivateResp.Reassignments)
+	}
+
+	resp = env.do(http.MethodPost, "/team/deactivateUsers", []byte(`{"team_name": "core", "user_ids": ["stranger"]}`))
+	if resp.Code != http.StatusNotFound {
+		t.Fatalf("team/deactivateUsers with unknown user: expected status 404, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_IdempotencyKey(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true },
+			{ "user_id": "r3", "username": "R3", "is_active": true },
+			{ "user_id": "r4", "username": "R4", "is_active": true }
+		]
+	}`)
+	teamKey := map[string]string{"Idempotency-Key": "team-1"}
+	if resp := env.doWithHeaders(http.MethodPost, "/team/add", teamBody, teamKey); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+	resp := env.doWithHeaders(http.MethodPost, "/team/add", teamBody, teamKey)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("replayed team/add: expected status 201, got %d", resp.Code)
+	}
+	if resp.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("expected replayed team/add to be marked as replay")
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "PR", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+	}
+
+	oldReviewer := env.prRepo.reviewers["pr-1"][0]
+	reassignBody, _ := json.Marshal(map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     oldReviewer,
+	})
+	reassignKey := map[string]string{"Idempotency-Key": "reassign-1"}
+
+	first := env.doWithHeaders(http.MethodPost, "/pullRequest/reassign", reassignBody, reassignKey)
+	if first.Code != http.StatusOK {
+		t.Fatalf("pullRequest/reassign: expected status 200, got %d", first.Code)
+	}
+	reviewersAfterFirst := append([]string(nil), env.prRepo.reviewers["pr-1"]...)
+
+	// Без ключа повтор упал бы с NOT_ASSIGNED, с ключом — получаем тот же ответ.
+	second := env.doWithHeaders(http.MethodPost, "/pullRequest/reassign", reassignBody, reassignKey)
+	if second.Code != http.StatusOK {
+		t.Fatalf("replayed pullRequest/reassign: expected status 200, got %d", second.Code)
+	}
+	if !bytes.Equal(first.Body.Bytes(), second.Body.Bytes()) {
+		t.Fatalf("replayed body differs:\nfirst:  %s\nsecond: %s", first.Body, second.Body)
+	}
+	if got := env.prRepo.reviewers["pr-1"]; len(got) != len(reviewersAfterFirst) || got[0] != reviewersAfterFirst[0] || got[1] != reviewersAfterFirst[1] {
+		t.Fatalf("replay must not reassign again: before %v, after %v", reviewersAfterFirst, got)
+	}
+
+	otherBody, _ := json.Marshal(map[string]string{
+		"pull_request_id": "pr-1",
+		"old_user_id":     reviewersAfterFirst[0],
+	})
+	resp = env.doWithHeaders(http.MethodPost, "/pullRequest/reassign", otherBody, reassignKey)
+	if resp.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("reused key with another body: expected status 422, got %d", resp.Code)
+	}
+
+	// Тот же ключ на другом эндпоинте — независимый запрос.
+	mergeBody := []byte(`{"pull_request_id": "pr-1"}`)
+	if resp := env.doWithHeaders(http.MethodPost, "/pullRequest/merge", mergeBody, reassignKey); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_IdempotencyKey_ReleasedAfterPanic(t *testing.T) {
+	repo := &memIdempotencyRepo{}
+	calls := 0
+	r := gin.New()
+	r.Use(gin.RecoveryWithWriter(io.Discard))
+	r.POST("/boom", middleware.Idempotency(service.NewIdempotencyService(repo)), func(c *gin.Context) {
+		calls++
+		if calls == 1 {
+			panic("handler failed")
+		}
+		c.JSON(http.StatusOK, gin.H{"calls": calls})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/boom", bytes.NewReader([]byte(`{}`)))
+		req.Header.Set("Idempotency-Key", "boom-1")
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		return rr
+	}
+
+	if resp := send(); resp.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 from panicking handler, got %d", resp.Code)
+	}
+	// Без освобождения ключа повтор получил бы 409 на все 24 часа.
+	if resp := send(); resp.Code != http.StatusOK {
+		t.Fatalf("retry after panic: expected 200, got %d: %s", resp.Code, resp.Body)
+	}
+}
+
+func TestHTTP_IdempotencyKey_StaleReservationTakenOver(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{"team_name": "core", "members": [{ "user_id": "u1", "username": "U1", "is_active": true }]}`)
+	sum := sha256.Sum256(teamBody)
+	hash := hex.EncodeToString(sum[:])
+	env.idemRepo.records = map[string]domain.IdempotencyRecord{
+		"/team/add fresh": {Key: "fresh", Endpoint: "/team/add", RequestHash: hash, CreatedAt: time.Now()},
+		"/team/add stale": {Key: "stale", Endpoint: "/team/add", RequestHash: hash, CreatedAt: time.Now().Add(-time.Hour)},
+	}
+
+	resp := env.doWithHeaders(http.MethodPost, "/team/add", teamBody, map[string]string{"Idempotency-Key": "fresh"})
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("fresh reservation: expected 409, got %d", resp.Code)
+	}
+
+	// Бронь старше lease осталась от упавшего процесса: запрос выполняется заново.
+	resp = env.doWithHeaders(http.MethodPost, "/team/add", teamBody, map[string]string{"Idempotency-Key": "stale"})
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("stale reservation: expected 201, got %d: %s", resp.Code, resp.Body)
+	}
+	if rec := env.idemRepo.records["/team/add stale"]; rec.StatusCode != http.StatusCreated {
+		t.Fatalf("expected response to be stored under taken over key, got status %d", rec.StatusCode)
+	}
+}
+
+func TestHTTP_PRLifecycle(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "PR", "author_id": "author", "draft": true}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create draft: expected status 201, got %d", resp.Code)
+	}
+
+	prBody := []byte(`{"pull_request_id": "pr-1"}`)
+
+	resp := env.do(http.MethodPost, "/pullRequest/merge", prBody)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("merge of draft: expected status 409, got %d", resp.Code)
+	}
+
+	resp = env.do(http.MethodPost, "/pullRequest/ready", prBody)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/ready: expected status 200, got %d", resp.Code)
+	}
+	var readyResp struct {
+		PR struct {
+			Status            string   `json:"status"`
+			AssignedReviewers []string `json:"assigned_reviewers"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&readyResp); err != nil {
+		t.Fatalf("decode ready response: %v", err)
+	}
+	if readyResp.PR.Status != "OPEN" || len(readyResp.PR.AssignedReviewers) != 2 {
+		t.Fatalf("expected OPEN with 2 reviewers, got %+v", readyResp.PR)
+	}
+
+	if resp := env.do(http.MethodPost, "/pullRequest/close", prBody); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/close: expected status 200, got %d", resp.Code)
+	}
+
+	resp = env.do(http.MethodPost, "/pullRequest/reassign", []byte(`{"pull_request_id": "pr-1", "old_user_id": "r1"}`))
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("reassign on closed: expected status 409, got %d", resp.Code)
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.Error.Code != "PR_NOT_OPEN" {
+		t.Fatalf("expected PR_NOT_OPEN, got %s", errResp.Error.Code)
+	}
+
+	if resp := env.do(http.MethodPost, "/pullRequest/reopen", prBody); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/reopen: expected status 200, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/pullRequest/merge", prBody); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/pullRequest/reopen", prBody); resp.Code != http.StatusConflict {
+		t.Fatalf("reopen of merged: expected status 409, got %d", resp.Code)
+	}
+}
+
+// Черновик закрывают без ревьюверов; после reopen он OPEN и должен получить их по обычным правилам.
+func TestHTTP_ReopenClosedDraftAssignsReviewers(t *testing.T) {
+	const prID = "github:acme/backend#42"
+
+	env := newTestEnv()
+	teamBody := []byte(`{
+		"team_name": "backend",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+	link := []byte(`{"provider": "github", "login": "Octo-Author", "user_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/integrations/accounts/link", link); resp.Code != http.StatusOK {
+		t.Fatalf("accounts/link: expected status 200, got %d", resp.Code)
+	}
+
+	for _, file := range []string{"pull_request_opened_draft.json", "pull_request_closed.json", "pull_request_reopened.json"} {
+		if resp := env.replayGitHub(t, "pull_request", file); resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", file, resp.Code, resp.Body)
+		}
+	}
+
+	pr := env.prRepo.prs[prID]
+	if pr.Status != domain.PullRequestStatusOpen {
+		t.Fatalf("expected OPEN after reopen, got %s", pr.Status)
+	}
+	if got := env.prRepo.reviewers[prID]; len(got) != 2 {
+		t.Fatalf("expected 2 reviewers after reopening a closed draft, got %v", got)
+	}
+	var assigned int
+	for _, e := range env.eventRepo.events {
+		if e.PullRequestID == prID && e.Type == domain.ReviewerEventAssigned && e.Reason == domain.ReviewerEventReasonPRReopened {
+			assigned++
+		}
+	}
+	if assigned != 2 {
+		t.Fatalf("expected 2 assigned events with reason pr_reopened, got %d", assigned)
+	}
+
+	// Повторный reopen ничего не меняет и ревьюверов не добавляет.
+	before := append([]string(nil), env.prRepo.reviewers[prID]...)
+	if resp := env.do(http.MethodPost, "/pullRequest/reopen", []byte(`{"pull_request_id": "`+prID+`"}`)); resp.Code != http.StatusOK {
+		t.Fatalf("repeated reopen: expected status 200, got %d", resp.Code)
+	}
+	if got := env.prRepo.reviewers[prID]; len(got) != len(before) || got[0] != before[0] || got[1] != before[1] {
+		t.Fatalf("repeated reopen must keep reviewers: before %v, after %v", before, got)
+	}
+}
+
+func TestHTTP_ReviewDecisions(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true }
+		],
+		"settings": { "min_reviewers": 1, "max_reviewers": 1, "required_approvals": 1 }
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "PR", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+	}
+
+	mergeBody := []byte(`{"pull_request_id": "pr-1"}`)
+	resp := env.do(http.MethodPost, "/pullRequest/merge", mergeBody)
+	if resp.Code != http.StatusConflict {
+		t.Fatalf("merge without approvals: expected status 409, got %d", resp.Code)
+	}
+	var errResp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("decode error response: %v", err)
+	}
+	if errResp.Error.Code != "NOT_APPROVED" {
+		t.Fatalf("expected NOT_APPROVED, got %s", errResp.Error.Code)
+	}
+
+	resp = env.do(http.MethodPost, "/pullRequest/review", []byte(`{"pull_request_id": "pr-1", "reviewer_id": "r1", "decision": "APPROVED"}`))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/review: expected status 200, got %d", resp.Code)
+	}
+	var reviewResp struct {
+		PR struct {
+			Reviews []struct {
+				ReviewerID string `json:"reviewer_id"`
+				Decision   string `json:"decision"`
+			} `json:"reviews"`
+		} `json:"pr"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reviewResp); err != nil {
+		t.Fatalf("decode review response: %v", err)
+	}
+	if len(reviewResp.PR.Reviews) != 1 || reviewResp.PR.Reviews[0].Decision != "APPROVED" {
+		t.Fatalf("expected one APPROVED decision, got %+v", reviewResp.PR.Reviews)
+	}
+
+	if resp := env.do(http.MethodPost, "/pullRequest/merge", mergeBody); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_PRHistory(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true }
+		],
+		"settings": { "max_reviewers": 1 }
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "PR", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+	}
+
+	old := env.prRepo.reviewers["pr-1"][0]
+	reassignBody, _ := json.Marshal(map[string]string{"pull_request_id": "pr-1", "old_user_id": old})
+	actor := map[string]string{"X-Actor-ID": "lead"}
+	if resp := env.doWithHeaders(http.MethodPost, "/pullRequest/reassign", reassignBody, actor); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/reassign: expected status 200, got %d", resp.Code)
+	}
+
+	resp := env.do(http.MethodGet, "/pullRequest/history?pull_request_id=pr-1", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/history: expected status 200, got %d", resp.Code)
+	}
+
+	var historyResp struct {
+		Events []struct {
+			Type       string `json:"type"`
+			ReviewerID string `json:"reviewer_id"`
+			ReplacedBy string `json:"replaced_by"`
+			ActorID    string `json:"actor_id"`
+			Reason     string `json:"reason"`
+		} `json:"events"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&historyResp); err != nil {
+		t.Fatalf("decode history response: %v", err)
+	}
+	if len(historyResp.Events) != 2 {
+		t.Fatalf("expected 2 events, got %+v", historyResp.Events)
+	}
+	if e := historyResp.Events[0]; e.Type != "assigned" || e.ReviewerID != old || e.ActorID != "" {
+		t.Fatalf("unexpected first event %+v", e)
+	}
+	if e := historyResp.Events[1]; e.Type != "replaced" || e.ReviewerID != old || e.ReplacedBy == "" || e.ActorID != "lead" {
+		t.Fatalf("unexpected second event %+v", e)
+	}
+
+	if resp := env.do(http.MethodGet, "/pullRequest/history?pull_request_id=missing", nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("history of unknown pr: expected status 404, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodGet, "/pullRequest/history", nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("history without id: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_AuditLog(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true }
+		]
+	}`)
+	admin := map[string]string{"X-Actor-ID": "admin"}
+	if resp := env.doWithHeaders(http.MethodPost, "/team/add", teamBody, admin); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	deactivate := []byte(`{"user_id": "r1", "is_active": false}`)
+	if resp := env.doWithHeaders(http.MethodPost, "/users/setIsActive", deactivate, admin); resp.Code != http.StatusOK {
+		t.Fatalf("users/setIsActive: expected status 200, got %d", resp.Code)
+	}
+
+	merge := []byte(`{"pull_request_id": "missing"}`)
+	if resp := env.doWithHeaders(http.MethodPost, "/pullRequest/merge", merge, map[string]string{"X-Actor-ID": "bob"}); resp.Code != http.StatusNotFound {
+		t.Fatalf("merge of unknown pr: expected status 404, got %d", resp.Code)
+	}
+
+	if resp := env.do(http.MethodGet, "/team/get?team_name=core", nil); resp.Code != http.StatusOK {
+		t.Fatalf("team/get: expected status 200, got %d", resp.Code)
+	}
+
+	type auditResp struct {
+		Entries []struct {
+			ActorID     string `json:"actor_id"`
+			Endpoint    string `json:"endpoint"`
+			EntityType  string `json:"entity_type"`
+			EntityID    string `json:"entity_id"`
+			PayloadHash string `json:"payload_hash"`
+			StatusCode  int    `json:"status_code"`
+			OutcomeCode string `json:"outcome_code"`
+		} `json:"entries"`
+	}
+
+	resp := env.do(http.MethodGet, "/audit", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("audit: expected status 200, got %d", resp.Code)
+	}
+	var all auditResp
+	if err := json.NewDecoder(resp.Body).Decode(&all); err != nil {
+		t.Fatalf("decode audit response: %v", err)
+	}
+	if len(all.Entries) != 3 {
+		t.Fatalf("expected only the 3 writes in the audit log, got %+v", all.Entries)
+	}
+	if e := all.Entries[0]; e.ActorID != "bob" || e.OutcomeCode != "NOT_FOUND" || e.StatusCode != http.StatusNotFound {
+		t.Fatalf("unexpected latest entry %+v", e)
+	}
+
+	resp = env.do(http.MethodGet, "/audit?actor_id=admin&entity_type=user&entity_id=r1", nil)
+	var byUser auditResp
+	if err := json.NewDecoder(resp.Body).Decode(&byUser); err != nil {
+		t.Fatalf("decode audit response: %v", err)
+	}
+	if len(byUser.Entries) != 1 {
+		t.Fatalf("expected one entry for user r1, got %+v", byUser.Entries)
+	}
+	if e := byUser.Entries[0]; e.Endpoint != "/users/setIsActive" || e.OutcomeCode != "OK" || e.PayloadHash == "" {
+		t.Fatalf("unexpected entry %+v", e)
+	}
+
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	resp = env.do(http.MethodGet, "/audit?from="+future, nil)
+	var none auditResp
+	if err := json.NewDecoder(resp.Body).Decode(&none); err != nil {
+		t.Fatalf("decode audit response: %v", err)
+	}
+	if len(none.Entries) != 0 {
+		t.Fatalf("expected no entries after %s, got %+v", future, none.Entries)
+	}
+
+	if resp := env.do(http.MethodGet, "/audit?from=yesterday", nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("audit with bad from: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_Webhooks(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	subBody := []byte(`{
+		"team_name": "core",
+		"url": "https://bot.example.com/hook",
+		"secret": "s3cret",
+		"event_types": ["pr.created", "pr.merged"]
+	}`)
+	resp := env.do(http.MethodPost, "/webhooks/add", subBody)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("webhooks/add: expected status 201, got %d", resp.Code)
+	}
+	if bytes.Contains(resp.Body.Bytes(), []byte("s3cret")) {
+		t.Fatalf("secret must not be returned: %s", resp.Body)
+	}
+
+	badBody := []byte(`{"team_name": "core", "url": "ftp://x", "secret": "s", "event_types": ["pr.created"]}`)
+	if resp := env.do(http.MethodPost, "/webhooks/add", badBody); resp.Code != http.StatusBadRequest {
+		t.Fatalf("webhooks/add with bad url: expected status 400, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "PR", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/pullRequest/merge", []byte(`{"pull_request_id": "pr-1"}`)); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d", resp.Code)
+	}
+
+	// До relay события лежат только в outbox.
+	if len(env.webhookRepo.deliveries) != 0 {
+		t.Fatalf("expected no deliveries before relay, got %d", len(env.webhookRepo.deliveries))
+	}
+	if len(env.outboxRepo.messages) == 0 {
+		t.Fatal("expected events in outbox")
+	}
+	if _, err := env.relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("relay outbox: %v", err)
+	}
+	for _, m := range env.outboxRepo.messages {
+		if m.PublishedAt == nil {
+			t.Fatalf("outbox message %s is not published: %s", m.EventType, m.LastError)
+		}
+	}
+
+	// reviewer.assigned не входит в подписку, поэтому в очереди только два события.
+	deliveries := env.webhookRepo.deliveries
+	if len(deliveries) != 2 {
+		t.Fatalf("expected 2 queued deliveries, got %d", len(deliveries))
+	}
+	if deliveries[0].EventType != domain.EventPRCreated || deliveries[1].EventType != domain.EventPRMerged {
+		t.Fatalf("unexpected event types %s, %s", deliveries[0].EventType, deliveries[1].EventType)
+	}
+
+	var payload struct {
+		Type     string `json:"type"`
+		TeamName string `json:"team_name"`
+		Data     struct {
+			PullRequestID string `json:"pull_request_id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(deliveries[0].Payload, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Type != "pr.created" || payload.TeamName != "core" || payload.Data.PullRequestID != "pr-1" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+
+	resp = env.do(http.MethodGet, "/webhooks/list?team_name=core", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("webhooks/list: expected status 200, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/webhooks/delete", []byte(`{"subscription_id": 1}`)); resp.Code != http.StatusNoContent {
+		t.Fatalf("webhooks/delete: expected status 204, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodPost, "/webhooks/delete", []byte(`{"subscription_id": 1}`)); resp.Code != http.StatusNotFound {
+		t.Fatalf("repeated webhooks/delete: expected status 404, got %d", resp.Code)
+	}
+}
+
+// replayGitHub отправляет записанный payload из testdata/github так, как его прислал бы GitHub.
+func (e *testEnv) replayGitHub(t *testing.T, event, file string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "github", file))
+	if err != nil {
+		t.Fatalf("read %s: %v", file, err)
+	}
+	return e.doWithHeaders(http.MethodPost, "/integrations/github/webhook", body, map[string]string{
+		service.GitHubEventHeader:     event,
+		service.GitHubSignatureHeader: service.SignWebhookPayload(testGitHubSecret, body),
+	})
+}
+
+func TestHTTP_GitHubWebhook(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "backend",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "lead", "username": "Lead", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	const prID = "github:acme/backend#42"
+
+	decode := func(resp *httptest.ResponseRecorder) (status, reason string) {
+		t.Helper()
+		var body struct {
+			Status        string `json:"status"`
+			PullRequestID string `json:"pull_request_id"`
+			Reason        string `json:"reason"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode integration response: %v", err)
+		}
+		return body.Status, body.Reason
+	}
+	prStatus := func() string {
+		t.Helper()
+		pr, err := env.prRepo.GetByID(context.Background(), prID)
+		if err != nil {
+			t.Fatalf("get %s: %v", prID, err)
+		}
+		return string(pr.Status)
+	}
+
+	body, _ := os.ReadFile(filepath.Join("testdata", "github", "pull_request_opened_draft.json"))
+	resp := env.doWithHeaders(http.MethodPost, "/integrations/github/webhook", body, map[string]string{
+		service.GitHubEventHeader:     "pull_request",
+		service.GitHubSignatureHeader: service.SignWebhookPayload("wrong-secret", body),
+	})
+	if resp.Code != http.StatusUnauthorized {
+		t.Fatalf("bad signature: expected status 401, got %d", resp.Code)
+	}
+
+	if resp := env.replayGitHub(t, "ping", "ping.json"); resp.Code != http.StatusOK {
+		t.Fatalf("ping: expected status 200, got %d", resp.Code)
+	} else if status, _ := decode(resp); status != "ignored" {
+		t.Fatalf("ping: expected ignored, got %s", status)
+	}
+
+	// Пока логин автора не привязан, PR не создаётся.
+	resp = env.replayGitHub(t, "pull_request", "pull_request_opened_draft.json")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("opened without mapping: expected status 200, got %d", resp.Code)
+	}
+	if status, reason := decode(resp); status != "ignored" || !strings.Contains(reason, "Octo-Author") {
+		t.Fatalf("opened without mapping: expected ignored, got %s (%s)", status, reason)
+	}
+
+	for _, link := range []string{
+		`{"provider": "github", "login": "Octo-Author", "user_id": "author"}`,
+		`{"provider": "github", "login": "octo-lead", "user_id": "lead"}`,
+	} {
+		if resp := env.do(http.MethodPost, "/integrations/accounts/link", []byte(link)); resp.Code != http.StatusOK {
+			t.Fatalf("accounts/link: expected status 200, got %d", resp.Code)
+		}
+	}
+	badLink := []byte(`{"provider": "github", "login": "ghost", "user_id": "nobody"}`)
+	if resp := env.do(http.MethodPost, "/integrations/accounts/link", badLink); resp.Code != http.StatusNotFound {
+		t.Fatalf("accounts/link to unknown user: expected status 404, got %d", resp.Code)
+	}
+
+	steps := []struct {
+		event, file string
+		wantResult  string
+		wantStatus  string
+	}{
+		{"pull_request", "pull_request_opened_draft.json", "processed", "DRAFT"},
+		{"pull_request", "pull_request_opened_draft.json", "ignored", "DRAFT"},
+		{"pull_request", "pull_request_labeled.json", "ignored", "DRAFT"},
+		{"pull_request", "pull_request_ready_for_review.json", "processed", "OPEN"},
+		{"pull_request", "pull_request_closed.json", "processed", "CLOSED"},
+		{"pull_request", "pull_request_reopened.json", "processed", "OPEN"},
+		{"pull_request", "pull_request_closed_merged.json", "processed", "MERGED"},
+	}
+	for _, step := range steps {
+		resp := env.replayGitHub(t, step.event, step.file)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", step.file, resp.Code, resp.Body)
+		}
+		if result, reason := decode(resp); result != step.wantResult {
+			t.Fatalf("%s: expected %s, got %s (%s)", step.file, step.wantResult, result, reason)
+		}
+		if got := prStatus(); got != step.wantStatus {
+			t.Fatalf("%s: expected PR status %s, got %s", step.file, step.wantStatus, got)
+		}
+	}
+
+	pr, _ := env.prRepo.GetByID(context.Background(), prID)
+	if pr.AuthorID != "author" || pr.Name != "Add retry to payment client" {
+		t.Fatalf("unexpected PR %+v", pr)
+	}
+	if len(pr.AssignedReviewers) == 0 {
+		t.Fatal("expected reviewers to be assigned on ready_for_review")
+	}
+
+	// Назначения на ready_for_review записаны от имени отправителя события.
+	events, _ := env.eventRepo.ListByPR(context.Background(), prID)
+	if len(events) == 0 || events[0].ActorID != "author" {
+		t.Fatalf("expected history with actor author, got %+v", events)
+	}
+}
+
+// Мёрж в GitHub уже случился, поэтому политика апрувов команды его не блокирует.
+func TestHTTP_GitHubWebhook_MergeWithoutApprovals(t *testing.T) {
+	const prID = "github:acme/backend#42"
+
+	cases := []struct {
+		name  string
+		steps []string
+	}{
+		{"from open", []string{"pull_request_opened_draft.json", "pull_request_ready_for_review.json"}},
+		{"from draft", []string{"pull_request_opened_draft.json"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			env := newTestEnv()
+
+			teamBody := []byte(`{
+				"team_name": "backend",
+				"members": [
+					{ "user_id": "author", "username": "Author", "is_active": true },
+					{ "user_id": "r1", "username": "R1", "is_active": true }
+				],
+				"settings": { "min_reviewers": 1, "required_approvals": 1 }
+			}`)
+			if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+				t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+			}
+			link := []byte(`{"provider": "github", "login": "Octo-Author", "user_id": "author"}`)
+			if resp := env.do(http.MethodPost, "/integrations/accounts/link", link); resp.Code != http.StatusOK {
+				t.Fatalf("accounts/link: expected status 200, got %d", resp.Code)
+			}
+
+			for _, file := range tc.steps {
+				if resp := env.replayGitHub(t, "pull_request", file); resp.Code != http.StatusOK {
+					t.Fatalf("%s: expected status 200, got %d: %s", file, resp.Code, resp.Body)
+				}
+			}
+			if resp := env.do(http.MethodPost, "/pullRequest/merge", []byte(`{"pull_request_id": "`+prID+`"}`)); resp.Code == http.StatusOK {
+				t.Fatal("merge through the API must still require approvals")
+			}
+
+			resp := env.replayGitHub(t, "pull_request", "pull_request_closed_merged.json")
+			if resp.Code != http.StatusOK {
+				t.Fatalf("closed merged: expected status 200, got %d: %s", resp.Code, resp.Body)
+			}
+			pr, err := env.prRepo.GetByID(context.Background(), prID)
+			if err != nil {
+				t.Fatalf("get %s: %v", prID, err)
+			}
+			if pr.Status != domain.PullRequestStatusMerged || pr.MergedAt == nil {
+				t.Fatalf("expected PR merged without approvals, got %s", pr.Status)
+			}
+		})
+	}
+}
+
+func (e *testEnv) replayGitLab(t *testing.T, file string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := os.ReadFile(filepath.Join("testdata", "gitlab", file))
+	if err != nil {
+		t.Fatalf("read %s: %v", file, err)
+	}
+	return e.doWithHeaders(http.MethodPost, "/integrations/gitlab/webhook", body, map[string]string{
+		service.GitLabEventHeader: "Merge Request Hook",
+		service.GitLabTokenHeader: testGitLabToken,
+	})
+}
+
+func TestHTTP_GitLabWebhook(t *testing.T) {
+	env := newTestEnv()
+
+	// dana не привязана в external_accounts и находится по username в команде проекта.
+	teamBody := []byte(`{
+		"team_name": "backend",
+		"members": [
+			{ "user_id": "u-dana", "username": "dana", "is_active": true },
+			{ "user_id": "u-lead", "username": "Lead", "is_active": true },
+			{ "user_id": "u-r1", "username": "R1", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+	link := []byte(`{"provider": "gitlab", "login": "lead", "user_id": "u-lead"}`)
+	if resp := env.do(http.MethodPost, "/integrations/accounts/link", link); resp.Code != http.StatusOK {
+		t.Fatalf("accounts/link: expected status 200, got %d", resp.Code)
+	}
+
+	const prID = "gitlab:platform/billing-api!7"
+
+	body, _ := os.ReadFile(filepath.Join("testdata", "gitlab", "merge_request_open_draft.json"))
+	resp := env.doWithHeaders(http.MethodPost, "/integrations/gitlab/webhook", body, map[string]string{
+		service.GitLabEventHeader: "Merge Request Hook",
+		service.GitLabTokenHeader: "wrong",
+	})
+	if resp.Code != http.StatusUnauthorized {
+		t.Fatalf("bad token: expected status 401, got %d", resp.Code)
+	}
+
+	steps := []struct {
+		file       string
+		wantResult string
+		wantStatus string
+	}{
+		{"merge_request_open_unmapped.json", "ignored", ""},
+		{"merge_request_open_draft.json", "processed", "DRAFT"},
+		{"merge_request_update_title.json", "ignored", "DRAFT"},
+		{"merge_request_update_ready.json", "processed", "OPEN"},
+		{"merge_request_close.json", "processed", "CLOSED"},
+		{"merge_request_reopen.json", "processed", "OPEN"},
+		{"merge_request_merge.json", "processed", "MERGED"},
+	}
+	for _, step := range steps {
+		resp := env.replayGitLab(t, step.file)
+		if resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", step.file, resp.Code, resp.Body)
+		}
+		var result struct {
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("%s: decode response: %v", step.file, err)
+		}
+		if result.Status != step.wantResult {
+			t.Fatalf("%s: expected %s, got %s (%s)", step.file, step.wantResult, result.Status, result.Reason)
+		}
+		if step.wantStatus == "" {
+			continue
+		}
+		pr, err := env.prRepo.GetByID(context.Background(), prID)
+		if err != nil {
+			t.Fatalf("%s: get %s: %v", step.file, prID, err)
+		}
+		if string(pr.Status) != step.wantStatus {
+			t.Fatalf("%s: expected PR status %s, got %s", step.file, step.wantStatus, pr.Status)
+		}
+	}
+
+	pr, _ := env.prRepo.GetByID(context.Background(), prID)
+	if pr.AuthorID != "u-dana" || pr.Name != "Fix invoice rounding" {
+		t.Fatalf("unexpected PR %+v", pr)
+	}
+	if _, err := env.prRepo.GetByID(context.Background(), "gitlab:sandbox/playground!7"); err == nil {
+		t.Fatal("MR from unmapped project must not be created")
+	}
+}
+
+func TestHTTP_GitLabWebhook_MergeWithoutApprovals(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "backend",
+		"members": [
+			{ "user_id": "u-dana", "username": "dana", "is_active": true },
+			{ "user_id": "u-r1", "username": "R1", "is_active": true }
+		],
+		"settings": { "min_reviewers": 1, "required_approvals": 1 }
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	const prID = "gitlab:platform/billing-api!7"
+
+	for _, file := range []string{"merge_request_open_draft.json", "merge_request_update_ready.json"} {
+		if resp := env.replayGitLab(t, file); resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", file, resp.Code, resp.Body)
+		}
+	}
+	if resp := env.do(http.MethodPost, "/pullRequest/merge", []byte(`{"pull_request_id": "`+prID+`"}`)); resp.Code == http.StatusOK {
+		t.Fatal("merge through the API must still require approvals")
+	}
+
+	resp := env.replayGitLab(t, "merge_request_merge.json")
+	if resp.Code != http.StatusOK {
+		t.Fatalf("merge: expected status 200, got %d: %s", resp.Code, resp.Body)
+	}
+	pr, err := env.prRepo.GetByID(context.Background(), prID)
+	if err != nil {
+		t.Fatalf("get %s: %v", prID, err)
+	}
+	if pr.Status != domain.PullRequestStatusMerged {
+		t.Fatalf("expected PR merged without approvals, got %s", pr.Status)
+	}
+}
+
+// Ревьюверы MR берутся из команды проекта, даже если автор числится в другой команде.
+func TestHTTP_GitLabWebhook_ReviewersFromProjectTeam(t *testing.T) {
+	env := newTestEnv()
+
+	teams := [][]byte{
+		[]byte(`{
+			"team_name": "frontend",
+			"members": [
+				{ "user_id": "u-dana", "username": "dana", "is_active": true },
+				{ "user_id": "u-f1", "username": "F1", "is_active": true }
+			]
+		}`),
+		[]byte(`{
+			"team_name": "backend",
+			"members": [
+				{ "user_id": "u-b1", "username": "B1", "is_active": true },
+				{ "user_id": "u-b2", "username": "B2", "is_active": true }
+			]
+		}`),
+	}
+	for _, body := range teams {
+		if resp := env.do(http.MethodPost, "/team/add", body); resp.Code != http.StatusCreated {
+			t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+		}
+	}
+	link := []byte(`{"provider": "gitlab", "login": "dana", "user_id": "u-dana"}`)
+	if resp := env.do(http.MethodPost, "/integrations/accounts/link", link); resp.Code != http.StatusOK {
+		t.Fatalf("accounts/link: expected status 200, got %d", resp.Code)
+	}
+
+	for _, file := range []string{"merge_request_open_draft.json", "merge_request_update_ready.json"} {
+		if resp := env.replayGitLab(t, file); resp.Code != http.StatusOK {
+			t.Fatalf("%s: expected status 200, got %d: %s", file, resp.Code, resp.Body)
+		}
+	}
+
+	pr, err := env.prRepo.GetByID(context.Background(), "gitlab:platform/billing-api!7")
+	if err != nil {
+		t.Fatalf("get PR: %v", err)
+	}
+	if pr.AuthorID != "u-dana" || pr.Status != domain.PullRequestStatusOpen {
+		t.Fatalf("unexpected PR %+v", pr)
+	}
+	if len(pr.AssignedReviewers) != 2 {
+		t.Fatalf("expected 2 reviewers from backend, got %v", pr.AssignedReviewers)
+	}
+	for _, id := range pr.AssignedReviewers {
+		if id != "u-b1" && id != "u-b2" {
+			t.Fatalf("expected reviewers from the project team backend, got %v", pr.AssignedReviewers)
+		}
+	}
+}
+
+func TestHTTP_Notifications(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true }
+		]
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d", resp.Code)
+	}
+
+	badTpl := []byte(`{"team_name": "core", "event": "assigned", "template": "{{.Unknown}}"}`)
+	if resp := env.do(http.MethodPost, "/team/notificationTemplates", badTpl); resp.Code != http.StatusBadRequest {
+		t.Fatalf("template with unknown field: expected status 400, got %d: %s", resp.Code, resp.Body)
+	}
+	tplBody := []byte(`{"team_name": "core", "event": "assigned", "template": "{{.Mention}} please review {{.PullRequest.Name}}"}`)
+	if resp := env.do(http.MethodPost, "/team/notificationTemplates", tplBody); resp.Code != http.StatusOK {
+		t.Fatalf("notificationTemplates: expected status 200, got %d: %s", resp.Code, resp.Body)
+	}
+
+	resp := env.do(http.MethodGet, "/team/notificationTemplates?team_name=core", nil)
+	var templates struct {
+		Templates []struct {
+			Event  string `json:"event"`
+			Custom bool   `json:"custom"`
+		} `json:"templates"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &templates); err != nil {
+		t.Fatalf("decode templates: %v", err)
+	}
+	if len(templates.Templates) != 4 || !templates.Templates[0].Custom || templates.Templates[1].Custom {
+		t.Fatalf("expected custom assigned template and built-in others, got %+v", templates.Templates)
+	}
+
+	prefsBody := []byte(`{"user_id": "r1", "slack_user_id": "U01", "slack_webhook_url": "https://hooks.slack.test/r1"}`)
+	resp = env.do(http.MethodPost, "/users/notifications", prefsBody)
+	if resp.Code != http.StatusOK || bytes.Contains(resp.Body.Bytes(), []byte("hooks.slack.test")) {
+		t.Fatalf("users/notifications: expected 200 without webhook url, got %d: %s", resp.Code, resp.Body)
+	}
+	off := []byte(`{"user_id": "r2", "enabled": false}`)
+	if resp := env.do(http.MethodPost, "/users/notifications", off); resp.Code != http.StatusOK {
+		t.Fatalf("users/notifications: expected status 200, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodGet, "/users/notifications?user_id=ghost", nil); resp.Code != http.StatusNotFound {
+		t.Fatalf("users/notifications for unknown user: expected status 404, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d: %s", resp.Code, resp.Body)
+	}
+	if _, err := env.relay.RelayPending(context.Background()); err != nil {
+		t.Fatalf("relay outbox: %v", err)
+	}
+
+	// r2 уведомления выключил, автору о назначении не пишем.
+	if len(env.notifier.sent) != 1 {
+		t.Fatalf("expected 1 notification, got %+v", env.notifier.sent)
+	}
+	got := env.notifier.sent[0]
+	if got.UserID != "r1" || got.Text != "<@U01> please review Add search" || got.Target != "https://hooks.slack.test/r1" {
+		t.Fatalf("unexpected notification: %+v", got)
+	}
+}
+
+func TestHTTP_SLABreaches(t *testing.T) {
+	env := newTestEnv()
+	ctx := context.Background()
+
+	teamBody := []byte(`{
+		"team_name": "core",
+		"members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true },
+			{ "user_id": "lead", "username": "Lead", "is_active": false }
+		],
+		"settings": { "max_reviewers": 1, "sla_hours": 4, "sla_auto_reassign": true, "team_lead": "lead" }
+	}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d: %s", resp.Code, resp.Body)
+	}
+	if resp := env.do(http.MethodPost, "/team/settings", []byte(`{"team_name": "core", "team_lead": "ghost"}`)); resp.Code != http.StatusBadRequest {
+		t.Fatalf("team/settings with unknown lead: expected status 400, got %d", resp.Code)
+	}
+
+	createBody := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "Add search", "author_id": "author"}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", createBody); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d: %s", resp.Code, resp.Body)
+	}
+	slow := env.prRepo.reviewers["pr-1"][0]
+
+	now := time.Now().UTC()
+	env.slaRepo.assignedAt["pr-1/"+slow] = now.Add(-5 * time.Hour)
+	if found, err := env.sla.Check(ctx, now); err != nil || found != 1 {
+		t.Fatalf("expected one breach, got %d err=%v", found, err)
+	}
+	if _, err := env.relay.RelayPending(ctx); err != nil {
+		t.Fatalf("relay outbox: %v", err)
+	}
+
+	var leadNotified bool
+	for _, n := range env.notifier.sent {
+		if n.UserID == "lead" && n.Kind == domain.NotificationSLABreach && strings.Contains(n.Text, slow) {
+			leadNotified = true
+		}
+	}
+	if !leadNotified {
+		t.Fatalf("expected team lead to be notified, got %+v", env.notifier.sent)
+	}
+
+	resp := env.do(http.MethodGet, "/stats/slaBreaches?team_name=core", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("stats/slaBreaches: expected status 200, got %d", resp.Code)
+	}
+	var breaches struct {
+		Breaches []struct {
+			PullRequestID string `json:"pull_request_id"`
+			ReviewerID    string `json:"reviewer_id"`
+			NewReviewerID string `json:"new_reviewer_id"`
+			Open          bool   `json:"open"`
+		} `json:"breaches"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &breaches); err != nil {
+		t.Fatalf("decode breaches: %v", err)
+	}
+	if len(breaches.Breaches) != 1 {
+		t.Fatalf("expected one breach, got %+v", breaches.Breaches)
+	}
+	b := breaches.Breaches[0]
+	if b.PullRequestID != "pr-1" || b.ReviewerID != slow || b.NewReviewerID == "" || b.NewReviewerID == slow || b.Open {
+		t.Fatalf("expected closed breach reassigned away from %s, got %+v", slow, b)
+	}
+
+	if resp := env.do(http.MethodGet, "/stats/slaBreaches?open=maybe", nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("stats/slaBreaches with bad open: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_ReviewerStats(t *testing.T) {
+	env := newTestEnv()
+
+	for _, body := range []string{
+		`{"team_name": "core", "members": [
+			{ "user_id": "author", "username": "Author", "is_active": true },
+			{ "user_id": "r1", "username": "R1", "is_active": true },
+			{ "user_id": "r2", "username": "R2", "is_active": true },
+			{ "user_id": "r3", "username": "R3", "is_active": true }
+		]}`,
+		`{"team_name": "docs", "members": [{ "user_id": "d1", "username": "Doc", "is_active": true }]}`,
+	} {
+		if resp := env.do(http.MethodPost, "/team/add", []byte(body)); resp.Code != http.StatusCreated {
+			t.Fatalf("team/add: expected status 201, got %d: %s", resp.Code, resp.Body)
+		}
+	}
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		body := []byte(`{"pull_request_id": "` + id + `", "pull_request_name": "Change", "author_id": "author", "reviewers_count": 1}`)
+		if resp := env.do(http.MethodPost, "/pullRequest/create", body); resp.Code != http.StatusCreated {
+			t.Fatalf("pullRequest/create %s: expected status 201, got %d: %s", id, resp.Code, resp.Body)
+		}
+	}
+
+	away := env.prRepo.reviewers["pr-1"][0]
+	if resp := env.do(http.MethodPost, "/pullRequest/reassign", []byte(`{"pull_request_id": "pr-1", "old_user_id": "`+away+`"}`)); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/reassign: expected status 200, got %d: %s", resp.Code, resp.Body)
+	}
+	mergedBy := env.prRepo.reviewers["pr-2"][0]
+	if resp := env.do(http.MethodPost, "/pullRequest/merge", []byte(`{"pull_request_id": "pr-2"}`)); resp.Code != http.StatusOK {
+		t.Fatalf("pullRequest/merge: expected status 200, got %d: %s", resp.Code, resp.Body)
+	}
+
+	resp := env.do(http.MethodGet, "/stats/reviewers?team_name=core", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("stats/reviewers: expected status 200, got %d", resp.Code)
+	}
+	var stats struct {
+		Reviewers []struct {
+			UserID                   string `json:"user_id"`
+			Assignments              int    `json:"assignments"`
+			OpenReviews              int    `json:"open_reviews"`
+			MergedReviews            int    `json:"merged_reviews"`
+			ReassignedAway           int    `json:"reassigned_away"`
+			MedianTimeToMergeSeconds *int64 `json:"median_time_to_merge_seconds"`
+		} `json:"reviewers"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("decode reviewer stats: %v", err)
+	}
+	if len(stats.Reviewers) != 4 {
+		t.Fatalf("expected every core member, got %+v", stats.Reviewers)
+	}
+
+	var assignments, open int
+	for _, s := range stats.Reviewers {
+		assignments += s.Assignments
+		open += s.OpenReviews
+
+		wantAway := 0
+		if s.UserID == away {
+			wantAway = 1
+		}
+		if s.ReassignedAway != wantAway {
+			t.Fatalf("%s: expected %d reassignments away, got %d", s.UserID, wantAway, s.ReassignedAway)
+		}
+		if s.UserID == mergedBy {
+			if s.MergedReviews != 1 || s.MedianTimeToMergeSeconds == nil {
+				t.Fatalf("%s: expected one merged review with median, got %+v", s.UserID, s)
+			}
+		} else if s.MergedReviews != 0 || s.MedianTimeToMergeSeconds != nil {
+			t.Fatalf("%s: expected no merged reviews, got %+v", s.UserID, s)
+		}
+	}
+	// Два первичных назначения и одно на замену; открытым остаётся только pr-1.
+	if assignments != 3 || open != 1 {
+		t.Fatalf("expected 3 assignments and 1 open review, got %d and %d", assignments, open)
+	}
+
+	if resp := env.do(http.MethodGet, "/stats/reviewers?from=2025-03-02T00:00:00Z&to=2025-03-01T00:00:00Z", nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("stats/reviewers with reversed period: expected status 400, got %d", resp.Code)
+	}
+	if resp := env.do(http.MethodGet, "/stats/reviewers?from=yesterday", nil); resp.Code != http.StatusBadRequest {
+		t.Fatalf("stats/reviewers with bad from: expected status 400, got %d", resp.Code)
+	}
+}
+
+func TestHTTP_TeamFairness(t *testing.T) {
+	env := newTestEnv()
+
+	teamBody := []byte(`{"team_name": "core", "members": [
+		{ "user_id": "author", "username": "Author", "is_active": true },
+		{ "user_id": "r1", "username": "R1", "is_active": true },
+		{ "user_id": "r2", "username": "R2", "is_active": true }
+	]}`)
+	if resp := env.do(http.MethodPost, "/team/add", teamBody); resp.Code != http.StatusCreated {
+		t.Fatalf("team/add: expected status 201, got %d: %s", resp.Code, resp.Body)
+	}
+	body := []byte(`{"pull_request_id": "pr-1", "pull_request_name": "Change", "author_id": "author", "reviewers_count": 2}`)
+	if resp := env.do(http.MethodPost, "/pullRequest/create", body); resp.Code != http.StatusCreated {
+		t.Fatalf("pullRequest/create: expected status 201, got %d: %s", resp.Code, resp.Body)
+	}
+
+	resp := env.do(http.MethodGet, "/stats/teams/fairness?team_name=core", nil)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("stats/teams/fairness: expected status 200, got %d: %s", resp.Code, resp.Body)
+	}
+	var report struct {
+		TotalAssignments int      `json:"total_assignments"`
+		Gini             float64  `json:"gini"`
+		MaxMinRatio      *float64 `json:"max_min_ratio"`
+		Members          []struct {
+			UserID      string  `json:"user_id"`
+			Assignments int     `json:"assignments"`
+			Share       float64 `json:"share"`
+		} `json:"members"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decode fairness report: %v", err)
+	}
+	// Автор не ревьюит свой PR: оба назначения у r1 и r2, у автора ни одного.
+	if report.TotalAssignments != 2 || len(report.Members) != 3 || report.MaxMinRatio != nil {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, m := range report.Members {
+		if m.UserID != "author" && m.Share != 0.5 {
+			t.Fatalf("expected half of the load on %s, got %v", m.UserID, m.Share)
+		}
+	}
+
+	resp = env.do(http.MethodGet, "/stats/teams/fairness?team_name=core&format=csv", nil)
+	if resp.Code != http.StatusOK || !strings.HasPrefix(resp.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("csv export: expected text/csv, got %d %q", resp.Code, resp.Header().Get("Content-Type"))
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("decode csv: %v", err)
+	}
+	wantHeader := "team_name,user_id,username,is_active,assignments,share,from,to,total_assignments,gini,max_min_ratio"
+	if len(rows) != 4 || strings.Join(rows[0], ",") != wantHeader {
+		t.Fatalf("unexpected csv: %v", rows)
+	}
+	for _, row := range rows[1:] {
+		// У автора нет назначений, поэтому отношение max/min бесконечно, а не 0.
+		if row[8] != "2" || row[9] != "0.3333" || row[10] != "inf" || row[6] == "" || row[7] == "" {
+			t.Fatalf("unexpected summary columns in %v", row)
+		}
+	}
+	if !strings.Contains(resp.Header().Get("Content-Disposition"), "fairness-core.csv") {
+		t.Fatalf("expected attachment filename, got %q", resp.Header().Get("Content-Disposition"))
+	}
+
+	for _, tc := range []struct {
+		query string
+		want  int
+	}{
+		{"", http.StatusBadRequest},
+		{"?team_name=core&format=xlsx", http.StatusBadRequest},
+		{"?team_name=ghost", http.StatusNotFound},
+	} {
+		if resp := env.do(http.MethodGet, "/stats/teams/fairness"+tc.query, nil); resp.Code != tc.want {
+			t.Fatalf("stats/teams/fairness%s: expected status %d, got %d", tc.query, tc.want, resp.Code)
+		}
+	}
+}
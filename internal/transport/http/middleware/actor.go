@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+const ActorIDHeader = "X-Actor-ID"
+
+// Actor переносит X-Actor-ID в контекст запроса, чтобы сервисы могли записать, кто сделал изменение.
+func Actor() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if actorID := c.GetHeader(ActorIDHeader); actorID != "" {
+			c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actorID))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/domain"
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// auditEntityFields — по какому полю тела понять, над чем выполняется запрос. Порядок важен:
+// у /pullRequest/reassign есть и pull_request_id, и old_user_id, сущность — PR.
+var auditEntityFields = []struct {
+	field      string
+	entityType string
+}{
+	{"pull_request_id", domain.AuditEntityPullRequest},
+	{"absence_id", domain.AuditEntityAbsence},
+	{"subscription_id", domain.AuditEntityWebhook},
+	{"user_id", domain.AuditEntityUser},
+	{"team_name", domain.AuditEntityTeam},
+}
+
+// Audit пишет в журнал каждый изменяющий запрос: кто, куда, хэш тела и чем закончилось.
+// Ошибка записи в журнал не ломает уже отданный ответ, только логируется.
+func Audit(svc *service.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			body = nil
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		rec := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+
+		c.Next()
+
+		entityType, entityID := auditEntity(body)
+		entry := domain.AuditEntry{
+			Method:      c.Request.Method,
+			Endpoint:    c.FullPath(),
+			EntityType:  entityType,
+			EntityID:    entityID,
+			StatusCode:  rec.Status(),
+			OutcomeCode: auditOutcome(rec.Status(), rec.body.Bytes()),
+		}
+		if entry.Endpoint == "" {
+			entry.Endpoint = c.Request.URL.Path
+		}
+
+		ctx := context.WithoutCancel(c.Request.Context())
+		if err := svc.Record(ctx, entry, body); err != nil {
+			log.Printf("audit: failed to record %s %s: %v", entry.Method, entry.Endpoint, err)
+		}
+	}
+}
+
+func auditEntity(body []byte) (string, string) {
+	var fields map[string]any
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return "", ""
+	}
+
+	for _, f := range auditEntityFields {
+		v, ok := fields[f.field]
+		if !ok || v == nil {
+			continue
+		}
+		id := fmt.Sprint(v)
+		if id != "" {
+			return f.entityType, id
+		}
+	}
+	return "", ""
+}
+
+func auditOutcome(status int, body []byte) string {
+	if status < http.StatusBadRequest {
+		return "OK"
+	}
+
+	var resp struct {
+		Error struct {
+			Code string `json:"code"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &resp); err == nil && resp.Error.Code != "" {
+		return resp.Error.Code
+	}
+	return http.StatusText(status)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/Mutter0815/pr-reviewer-service/internal/service"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/httperror"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+)
+
+// Idempotency повторяет сохранённый ответ для запросов с тем же Idempotency-Key и телом.
+// Без заголовка запрос обрабатывается как обычно.
+func Idempotency(svc *service.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, httperror.New("BAD_REQUEST", "cannot read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		endpoint := c.FullPath()
+		// Ответ нужно сохранить, даже если клиент уже отвалился.
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		replay, err := svc.Begin(ctx, key, endpoint, body)
+		if err != nil {
+			httperror.Write(c, err)
+			c.Abort()
+			return
+		}
+		if replay != nil {
+			c.Header(IdempotencyReplayedHeader, "true")
+			c.Data(replay.StatusCode, "application/json; charset=utf-8", replay.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = rec
+
+		// Паника обработчика не должна оставить ключ занятым: освобождаем и отдаём панику дальше, в gin.Recovery.
+		defer func() {
+			if p := recover(); p != nil {
+				if err := svc.Release(ctx, key, endpoint); err != nil {
+					log.Printf("idempotency: failed to release key %q: %v", key, err)
+				}
+				panic(p)
+			}
+		}()
+
+		c.Next()
+
+		if err := svc.Finish(ctx, key, endpoint, rec.Status(), rec.body.Bytes()); err != nil {
+			log.Printf("idempotency: failed to store response for key %q: %v", key, err)
+		}
+	}
+}
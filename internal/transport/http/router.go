@@ -3,29 +3,69 @@ package http
 import (
 	"github.com/Mutter0815/pr-reviewer-service/internal/service"
 	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/handlers"
+	"github.com/Mutter0815/pr-reviewer-service/internal/transport/http/middleware"
 	"github.com/gin-gonic/gin"
 )
 
 func NewRouter(services *service.Services) *gin.Engine {
 	r := gin.New()
 	r.Use(gin.Recovery())
+	r.Use(middleware.Actor())
+	r.Use(middleware.Audit(services.Audit))
 
 	healthHandler := handlers.NewHealthHandler()
 	teamHandler := handlers.NewTeamHandler(services.Team)
 	userHandler := handlers.NewUserHandler(services.User)
 	prHandler := handlers.NewPRHandler(services.PR)
+	auditHandler := handlers.NewAuditHandler(services.Audit)
+	webhookHandler := handlers.NewWebhookHandler(services.Webhook)
+	accountHandler := handlers.NewAccountHandler(services.Accounts)
+	githubHandler := handlers.NewGitHubHandler(services.GitHub)
+	gitlabHandler := handlers.NewGitLabHandler(services.GitLab)
+	notificationHandler := handlers.NewNotificationHandler(services.Notifications)
+	statsHandler := handlers.NewStatsHandler(services.Stats)
+	idempotent := middleware.Idempotency(services.Idempotency)
 
 	r.GET("/health", healthHandler.Health)
-	r.POST("/team/add", teamHandler.AddTeam)
+	r.POST("/team/add", idempotent, teamHandler.AddTeam)
 	r.GET("/team/list", teamHandler.ListTeams)
 
 	r.GET("/team/get", teamHandler.GetTeamInfo)
-	r.POST("/pullRequest/create", prHandler.Create)
-	r.POST("/pullRequest/reassign", prHandler.Reassign)
-	r.POST("/pullRequest/merge", prHandler.Merge)
+	r.GET("/team/settings", teamHandler.GetSettings)
+	r.POST("/team/settings", teamHandler.UpdateSettings)
+	r.POST("/team/deactivateUsers", teamHandler.DeactivateUsers)
+	r.GET("/team/notificationTemplates", notificationHandler.ListTemplates)
+	r.POST("/team/notificationTemplates", notificationHandler.SetTemplate)
+	r.POST("/pullRequest/create", idempotent, prHandler.Create)
+	r.POST("/pullRequest/reassign", idempotent, prHandler.Reassign)
+	r.POST("/pullRequest/merge", idempotent, prHandler.Merge)
+	r.POST("/pullRequest/ready", prHandler.Ready)
+	r.POST("/pullRequest/close", prHandler.Close)
+	r.POST("/pullRequest/reopen", prHandler.Reopen)
+	r.POST("/pullRequest/review", prHandler.Review)
+	r.GET("/pullRequest/history", prHandler.History)
 
 	r.POST("/users/setIsActive", userHandler.SetIsActive)
 	r.GET("/users/getReview", userHandler.GetReview)
+	r.POST("/users/absence/add", userHandler.AddAbsence)
+	r.GET("/users/absence/list", userHandler.ListAbsences)
+	r.POST("/users/absence/update", userHandler.UpdateAbsence)
+	r.POST("/users/absence/delete", userHandler.DeleteAbsence)
+	r.GET("/users/notifications", notificationHandler.GetPreferences)
+	r.POST("/users/notifications", notificationHandler.SetPreferences)
+	r.GET("/audit", auditHandler.List)
+	r.GET("/stats/slaBreaches", statsHandler.SLABreaches)
+	r.GET("/stats/reviewers", statsHandler.Reviewers)
+	r.GET("/stats/teams/fairness", statsHandler.TeamFairness)
+
+	r.POST("/webhooks/add", webhookHandler.Add)
+	r.GET("/webhooks/list", webhookHandler.List)
+	r.POST("/webhooks/delete", webhookHandler.Delete)
+
+	r.POST("/integrations/accounts/link", accountHandler.Link)
+	r.GET("/integrations/accounts/list", accountHandler.List)
+	r.POST("/integrations/github/webhook", githubHandler.Webhook)
+	r.POST("/integrations/gitlab/webhook", gitlabHandler.Webhook)
 	r.Static("/swagger", "internal/transport/http/swagger")
 
 	return r
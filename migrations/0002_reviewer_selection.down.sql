@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS review_weight;
+ALTER TABLE teams DROP COLUMN IF EXISTS assignment_strategy;
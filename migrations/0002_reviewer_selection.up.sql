@@ -0,0 +1,5 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS assignment_strategy TEXT NOT NULL DEFAULT 'random';
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS review_weight INT NOT NULL DEFAULT 1 CHECK (review_weight > 0);
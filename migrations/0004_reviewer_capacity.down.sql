@@ -0,0 +1,2 @@
+ALTER TABLE teams DROP COLUMN IF EXISTS fallback_team;
+ALTER TABLE users DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key TEXT NOT NULL,
+    endpoint        TEXT NOT NULL,
+    request_hash    TEXT NOT NULL,
+    status_code     INT,
+    response_body   BYTEA,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (idempotency_key, endpoint)
+);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS pull_request_reviews;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,11 @@
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS required_approvals INT NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+-- Последнее решение каждого ревьювера по PR; повторный review перезаписывает строку.
+CREATE TABLE IF NOT EXISTS pull_request_reviews (
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    decision        TEXT NOT NULL CHECK (decision IN ('APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    decided_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
@@ -0,0 +1,22 @@
+-- reviewer_id без внешнего ключа: история должна переживать изменения пользователей.
+CREATE TABLE IF NOT EXISTS pull_request_reviewer_events (
+    event_id        BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    event_type      TEXT NOT NULL CHECK (event_type IN ('assigned', 'replaced', 'removed')),
+    reviewer_id     TEXT NOT NULL,
+    replaced_by     TEXT,
+    actor_id        TEXT,
+    reason          TEXT NOT NULL DEFAULT '',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reviewer_events_pr ON pull_request_reviewer_events (pull_request_id, event_id);
+
+-- Текущие назначения, сделанные до появления истории, попадают в неё одним событием.
+INSERT INTO pull_request_reviewer_events (pull_request_id, event_type, reviewer_id, reason, created_at)
+SELECT rr.pull_request_id, 'assigned', rr.reviewer_id, 'backfill', COALESCE(pr.created_at, now())
+FROM pull_request_reviewers rr
+JOIN pull_requests pr ON pr.pull_request_id = rr.pull_request_id
+WHERE NOT EXISTS (
+    SELECT 1 FROM pull_request_reviewer_events e WHERE e.pull_request_id = rr.pull_request_id
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    audit_id     BIGSERIAL PRIMARY KEY,
+    actor_id     TEXT,
+    method       TEXT NOT NULL,
+    endpoint     TEXT NOT NULL,
+    entity_type  TEXT,
+    entity_id    TEXT,
+    payload_hash TEXT NOT NULL,
+    status_code  INT NOT NULL,
+    outcome_code TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON audit_log (created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log (actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log (entity_type, entity_id, created_at);
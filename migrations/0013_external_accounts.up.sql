@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS external_accounts (
+    provider   TEXT NOT NULL,
+    login      TEXT NOT NULL,
+    user_id    TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (provider, login)
+);
+
+CREATE INDEX IF NOT EXISTS idx_external_accounts_user ON external_accounts (user_id);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pr_reviewer_sync (
+    pull_request_id TEXT PRIMARY KEY REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    status          TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'synced', 'failed')),
+    version         BIGINT NOT NULL DEFAULT 1,
+    attempts        INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    last_error      TEXT NOT NULL DEFAULT '',
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pr_reviewer_sync_due ON pr_reviewer_sync (next_attempt_at) WHERE status = 'pending';
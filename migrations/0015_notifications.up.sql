@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id           TEXT PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    enabled           BOOLEAN NOT NULL DEFAULT TRUE,
+    kinds             TEXT[] NOT NULL,
+    slack_user_id     TEXT NOT NULL DEFAULT '',
+    slack_webhook_url TEXT NOT NULL DEFAULT '',
+    updated_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS notification_templates (
+    team_name  TEXT NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    kind       TEXT NOT NULL CHECK (kind IN ('assigned', 'replaced', 'merged')),
+    template   TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (team_name, kind)
+);
@@ -0,0 +1,10 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS email TEXT NOT NULL DEFAULT '';
+
+-- Одна строка на пользователя и день: по ней экземпляры сервиса не отправляют дайджест дважды.
+CREATE TABLE IF NOT EXISTS email_digests (
+    user_id     TEXT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    digest_date DATE NOT NULL,
+    claimed_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, digest_date)
+);
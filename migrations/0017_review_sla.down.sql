@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS review_sla_breaches;
+
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS team_lead,
+    DROP COLUMN IF EXISTS sla_auto_reassign,
+    DROP COLUMN IF EXISTS sla_working_hours,
+    DROP COLUMN IF EXISTS sla_hours;
+
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS assigned_at;
@@ -0,0 +1,43 @@
+-- Время назначения ревьювера: от него считается SLA. Существующие назначения берут время
+-- из истории, а если её нет — время создания PR.
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMPTZ;
+
+UPDATE pull_request_reviewers rr
+SET assigned_at = COALESCE(
+        (SELECT max(e.created_at)
+         FROM pull_request_reviewer_events e
+         WHERE e.pull_request_id = rr.pull_request_id
+           AND ((e.event_type = 'assigned' AND e.reviewer_id = rr.reviewer_id)
+             OR (e.event_type = 'replaced' AND e.replaced_by = rr.reviewer_id))),
+        pr.created_at,
+        now())
+FROM pull_requests pr
+WHERE pr.pull_request_id = rr.pull_request_id
+  AND rr.assigned_at IS NULL;
+
+ALTER TABLE pull_request_reviewers
+    ALTER COLUMN assigned_at SET DEFAULT now(),
+    ALTER COLUMN assigned_at SET NOT NULL;
+
+-- sla_hours = 0 — SLA не отслеживается. team_lead без внешнего ключа: команда создаётся раньше участников.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS sla_hours INT NOT NULL DEFAULT 0 CHECK (sla_hours >= 0),
+    ADD COLUMN IF NOT EXISTS sla_working_hours BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS sla_auto_reassign BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS team_lead TEXT;
+
+-- Одна запись на назначение: assigned_at отличает повторное назначение того же ревьювера.
+CREATE TABLE IF NOT EXISTS review_sla_breaches (
+    breach_id       BIGSERIAL PRIMARY KEY,
+    pull_request_id TEXT NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id     TEXT NOT NULL,
+    team_name       TEXT NOT NULL,
+    assigned_at     TIMESTAMPTZ NOT NULL,
+    deadline        TIMESTAMPTZ NOT NULL,
+    detected_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    new_reviewer_id TEXT,
+    UNIQUE (pull_request_id, reviewer_id, assigned_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_breaches_team ON review_sla_breaches (team_name, detected_at);
@@ -0,0 +1,9 @@
+ALTER TABLE teams
+    DROP COLUMN IF EXISTS stale_close_after_days,
+    DROP COLUMN IF EXISTS stale_after_days;
+
+DROP INDEX IF EXISTS idx_pull_requests_activity;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS stale_since,
+    DROP COLUMN IF EXISTS last_activity_at;
@@ -0,0 +1,24 @@
+-- last_activity_at — последнее изменение PR (статус, ревьюверы, решения); от него считается «протухание».
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS stale_since TIMESTAMPTZ;
+
+UPDATE pull_requests pr
+SET last_activity_at = GREATEST(
+        COALESCE(pr.created_at, now()),
+        pr.merged_at,
+        pr.closed_at,
+        (SELECT max(e.created_at) FROM pull_request_reviewer_events e WHERE e.pull_request_id = pr.pull_request_id),
+        (SELECT max(rv.decided_at) FROM pull_request_reviews rv WHERE rv.pull_request_id = pr.pull_request_id))
+WHERE pr.last_activity_at IS NULL;
+
+ALTER TABLE pull_requests
+    ALTER COLUMN last_activity_at SET DEFAULT now(),
+    ALTER COLUMN last_activity_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_activity ON pull_requests (status, last_activity_at);
+
+-- 0 — политика выключена: PR не помечаются устаревшими / не закрываются.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS stale_after_days INT NOT NULL DEFAULT 0 CHECK (stale_after_days >= 0),
+    ADD COLUMN IF NOT EXISTS stale_close_after_days INT NOT NULL DEFAULT 0 CHECK (stale_close_after_days >= 0);
@@ -0,0 +1,3 @@
+-- round_robin_cursor — user_id последнего ревьювера, назначенного по кругу; общий для всех экземпляров.
+ALTER TABLE teams
+    ADD COLUMN IF NOT EXISTS round_robin_cursor TEXT;
@@ -0,0 +1,9 @@
+-- Одно событие доставляется в подписку один раз, даже если outbox повторил публикацию.
+DELETE FROM webhook_deliveries d
+USING webhook_deliveries dup
+WHERE d.subscription_id = dup.subscription_id
+  AND d.event_id = dup.event_id
+  AND d.delivery_id > dup.delivery_id;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_event
+    ON webhook_deliveries (subscription_id, event_id);
@@ -0,0 +1,4 @@
+-- reviewer_team — команда, из которой назначаются ревьюверы (например, команда проекта GitLab);
+-- NULL — команда автора.
+ALTER TABLE pull_requests
+    ADD COLUMN IF NOT EXISTS reviewer_team TEXT;
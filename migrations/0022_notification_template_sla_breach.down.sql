@@ -0,0 +1,8 @@
+DELETE FROM notification_templates WHERE kind = 'sla_breach';
+
+ALTER TABLE notification_templates
+    DROP CONSTRAINT IF EXISTS notification_templates_kind_check;
+
+ALTER TABLE notification_templates
+    ADD CONSTRAINT notification_templates_kind_check
+        CHECK (kind IN ('assigned', 'replaced', 'merged'));
@@ -0,0 +1,7 @@
+-- Шаблоны для уведомлений о нарушении SLA: CHECK из 0015 знал только первые три вида.
+ALTER TABLE notification_templates
+    DROP CONSTRAINT IF EXISTS notification_templates_kind_check;
+
+ALTER TABLE notification_templates
+    ADD CONSTRAINT notification_templates_kind_check
+        CHECK (kind IN ('assigned', 'replaced', 'merged', 'sla_breach'));